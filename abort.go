@@ -0,0 +1,103 @@
+package httpmock
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+)
+
+var (
+	_ http.ResponseWriter = (*abortResponseWriter)(nil)
+	_ http.Flusher        = (*abortResponseWriter)(nil)
+	_ http.Hijacker       = (*abortResponseWriter)(nil)
+)
+
+// errResponseAborted indicates that the response was aborted by AbortAfterBytes.
+var errResponseAborted = errors.New("response aborted")
+
+// abortResponseWriter writes at most the given number of bytes of the body, then resets the connection.
+type abortResponseWriter struct {
+	http.ResponseWriter
+
+	remain  int
+	aborted bool
+}
+
+func (w *abortResponseWriter) Write(b []byte) (int, error) {
+	if w.aborted {
+		return 0, errResponseAborted
+	}
+
+	if len(b) <= w.remain {
+		w.remain -= len(b)
+
+		return w.ResponseWriter.Write(b)
+	}
+
+	n, err := w.ResponseWriter.Write(b[:w.remain])
+	if err != nil {
+		return n, err
+	}
+
+	w.abort()
+
+	return n, errResponseAborted
+}
+
+func (w *abortResponseWriter) Flush() {
+	if w.aborted {
+		return
+	}
+
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *abortResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking") // nolint: goerr113
+	}
+
+	return h.Hijack()
+}
+
+// abort sends the written part of the response and closes the connection. If the connection can not be hijacked, for
+// example, with HTTP/2, the handler is aborted with http.ErrAbortHandler, so the stream is reset.
+func (w *abortResponseWriter) abort() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+
+	w.aborted = true
+
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		panic(http.ErrAbortHandler)
+	}
+
+	conn, _, err := h.Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+
+	// The connection is not reset with SO_LINGER, a reset could discard the written part before the client reads it.
+	_ = conn.Close() // nolint: errcheck
+}
+
+// AbortAfterBytes writes only the first n bytes of the response body, then resets the connection, to test the
+// resumable downloads and the recovery from a partial read.
+//
+//	Server.Expect(httpmock.MethodGet, "/file").
+//		ReturnFile("resources/fixtures/file.bin").
+//		AbortAfterBytes(1024)
+func (e *requestExpectation) AbortAfterBytes(n int) Expectation {
+	e.lock()
+	defer e.unlock()
+
+	e.abortAfter = n
+
+	return e
+}
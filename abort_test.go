@@ -0,0 +1,78 @@
+package httpmock_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestServer_AbortAfterBytes(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario     string
+		mockServer   func(s *httpmock.Server)
+		expectedBody string
+	}{
+		{
+			scenario: "return",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet("/").
+					Return("hello world!").
+					AbortAfterBytes(5)
+			},
+			expectedBody: "hello",
+		},
+		{
+			scenario: "chunks",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet("/").
+					ReturnChunks([][]byte{[]byte("hello "), []byte("world"), []byte("!")}, 10*time.Millisecond).
+					AbortAfterBytes(8)
+			},
+			expectedBody: "hello wo",
+		},
+		{
+			scenario: "no body",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet("/").
+					Return("hello world!").
+					AbortAfterBytes(0)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			testingT := T()
+
+			s := httpmock.NewServer().WithTest(testingT)
+			defer s.Close()
+
+			tc.mockServer(s)
+
+			resp, err := http.Get(s.URL() + "/") //nolint: noctx
+			require.NoError(t, err)
+
+			defer resp.Body.Close() //nolint: errcheck
+
+			body, err := io.ReadAll(resp.Body)
+
+			assert.Equal(t, httpmock.StatusOK, resp.StatusCode)
+			assert.Equal(t, tc.expectedBody, string(body))
+			assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+			assert.NoError(t, s.ExpectationsWereMet())
+			assert.Empty(t, testingT.String())
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package httpmock
+
+import (
+	"go.nhat.io/httpmock/matcher"
+	"go.nhat.io/httpmock/test"
+)
+
+// AssertAcceptEncoding asserts that the client advertised the encodings in the Accept-Encoding header of every request,
+// for example, after enabling the compression of the client.
+//
+//	// Your requests.
+//
+//	srv.AssertAcceptEncoding(t, "gzip", "br")
+func (s *Server) AssertAcceptEncoding(t test.T, encodings ...string) bool {
+	journal := s.Journal()
+
+	if len(journal) == 0 {
+		t.Errorf("expected the client to accept %q, no request received", encodings)
+
+		return false
+	}
+
+	m := matcher.AcceptsEncoding(encodings...)
+
+	for _, entry := range journal {
+		header := entry.Header.Get("Accept-Encoding")
+
+		if matched, err := m.Match(header); err != nil || !matched {
+			t.Errorf("expected the client to accept %q in %s %s, %q received", encodings, entry.Method, entry.RequestURI, header)
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// AssertNotAcceptEncoding asserts that the client did not advertise the encoding in the Accept-Encoding header of any
+// request, for example, after disabling the compression of the client.
+//
+//	// Your requests.
+//
+//	srv.AssertNotAcceptEncoding(t, "gzip")
+func (s *Server) AssertNotAcceptEncoding(t test.T, encoding string) bool {
+	m := matcher.AcceptsEncoding(encoding)
+
+	for _, entry := range s.Journal() {
+		header := entry.Header.Get("Accept-Encoding")
+
+		if matched, err := m.Match(header); err == nil && matched {
+			t.Errorf("expected the client not to accept %q in %s %s, %q received", encoding, entry.Method, entry.RequestURI, header)
+
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,60 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/matcher"
+)
+
+func TestServer_AcceptsEncoding(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer().WithTest(T())
+	defer s.Close()
+
+	s.ExpectGet("/").
+		WithHeader("Accept-Encoding", matcher.AcceptsEncoding("gzip", "br"))
+
+	code, _, _, _ := doRequest(t, s.URL(), http.MethodGet, "/", httpmock.Header{"Accept-Encoding": "gzip"}, nil, 0)
+
+	assert.Equal(t, http.StatusInternalServerError, code)
+
+	code, _, _, _ = doRequest(t, s.URL(), http.MethodGet, "/", httpmock.Header{"Accept-Encoding": "br, gzip;q=0.5"}, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestServer_AssertAcceptEncoding(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+	defer s.Close()
+
+	testingT := T()
+
+	assert.False(t, s.AssertAcceptEncoding(testingT, "gzip"))
+	assert.Equal(t, `expected the client to accept ["gzip"], no request received`, testingT.String())
+	assert.True(t, s.AssertNotAcceptEncoding(T(), "gzip"))
+
+	s.ExpectGet("/").Twice()
+
+	doRequest(t, s.URL(), http.MethodGet, "/", httpmock.Header{"Accept-Encoding": "gzip, br"}, nil, 0)
+
+	assert.True(t, s.AssertAcceptEncoding(T(), "gzip", "br"))
+
+	testingT = T()
+
+	assert.False(t, s.AssertNotAcceptEncoding(testingT, "br"))
+	assert.Equal(t, `expected the client not to accept "br" in GET /, "gzip, br" received`, testingT.String())
+
+	doRequest(t, s.URL(), http.MethodGet, "/", httpmock.Header{"Accept-Encoding": "identity"}, nil, 0)
+
+	testingT = T()
+
+	assert.False(t, s.AssertAcceptEncoding(testingT, "gzip"))
+	assert.Equal(t, `expected the client to accept ["gzip"] in GET /, "identity" received`, testingT.String())
+}
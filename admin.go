@@ -0,0 +1,205 @@
+package httpmock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.nhat.io/httpmock/value"
+)
+
+const (
+	adminPrefix             = "/__admin/"
+	adminExpectationsPath   = adminPrefix + "expectations"
+	adminExpectationsPrefix = adminExpectationsPath + "/"
+	adminRequestsPath       = adminPrefix + "requests"
+)
+
+// adminExpectation is the representation of an expectation in the admin endpoints.
+type adminExpectation struct {
+	ID        int               `json:"id"`
+	Method    string            `json:"method"`
+	URI       string            `json:"uri"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Body      string            `json:"body,omitempty"`
+	Fulfilled uint              `json:"fulfilled"`
+	Remaining uint              `json:"remaining"`
+}
+
+func newAdminExpectation(e *requestExpectation) adminExpectation {
+	result := adminExpectation{
+		ID:        e.id,
+		Method:    e.Method(),
+		URI:       e.URIMatcher().Expected(),
+		Fulfilled: e.FulfilledTimes(),
+		Remaining: e.RemainTimes(),
+	}
+
+	if h := e.HeaderMatcher(); len(h) > 0 {
+		result.Headers = make(map[string]string, len(h))
+
+		for k, m := range h {
+			result.Headers[k] = m.Expected()
+		}
+	}
+
+	if b := e.BodyMatcher(); b != nil {
+		result.Body = b.Expected()
+	}
+
+	return result
+}
+
+// WithAdmin enables the admin endpoints to manage the expectations and to fetch the request journal over HTTP.
+//
+//	GET    /__admin/expectations       lists all the expectations.
+//	POST   /__admin/expectations       adds one or many expectations, see ExpectationSpec.
+//	DELETE /__admin/expectations       removes all the expectations.
+//	DELETE /__admin/expectations/{id}  removes an expectation.
+//	GET    /__admin/requests           lists all the received requests.
+//	DELETE /__admin/requests           clears the received requests.
+func (s *Server) WithAdmin() *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.admin = true
+
+	return s
+}
+
+func (s *Server) isAdminRequest(r *http.Request) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.admin && strings.HasPrefix(r.URL.Path, adminPrefix)
+}
+
+func (s *Server) serveAdmin(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+
+	switch {
+	case path == adminExpectationsPath && r.Method == http.MethodGet:
+		s.adminListExpectations(w)
+
+	case path == adminExpectationsPath && r.Method == http.MethodPost:
+		s.adminAddExpectations(w, r)
+
+	case path == adminExpectationsPath && r.Method == http.MethodDelete:
+		s.ResetExpectations()
+
+		w.WriteHeader(http.StatusNoContent)
+
+	case strings.HasPrefix(path, adminExpectationsPrefix) && r.Method == http.MethodDelete:
+		s.adminDeleteExpectation(w, strings.TrimPrefix(path, adminExpectationsPrefix))
+
+	case path == adminRequestsPath && r.Method == http.MethodGet:
+		writeAdminJSON(w, http.StatusOK, s.Journal())
+
+	case path == adminRequestsPath && r.Method == http.MethodDelete:
+		s.mu.Lock()
+		s.journal = nil
+		s.mu.Unlock()
+
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) adminListExpectations(w http.ResponseWriter) {
+	s.mu.Lock()
+
+	result := make([]adminExpectation, len(s.expectations))
+
+	for i, e := range s.expectations {
+		result[i] = newAdminExpectation(e)
+	}
+
+	s.mu.Unlock()
+
+	writeAdminJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) adminAddExpectations(w http.ResponseWriter, r *http.Request) {
+	body, err := value.PeekBody(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not read request body: %s", err.Error()), http.StatusBadRequest)
+
+		return
+	}
+
+	body = bytes.TrimSpace(body)
+
+	if !bytes.HasPrefix(body, []byte("[")) {
+		body = append(append([]byte("["), body...), ']')
+	}
+
+	specs, err := ReadExpectationSpecs(bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	// The specs are checked before adding any of them, so a bad request does not add a part of the expectations.
+	for i, spec := range specs {
+		if err := spec.check(); err != nil {
+			http.Error(w, fmt.Sprintf("invalid expectation #%d: %s", i+1, err.Error()), http.StatusBadRequest)
+
+			return
+		}
+	}
+
+	result := make([]adminExpectation, len(specs))
+
+	for i, spec := range specs {
+		e, _ := s.ExpectSpec(spec) //nolint: errcheck // The specs are checked above.
+
+		result[i] = newAdminExpectation(e.(*requestExpectation)) //nolint: errcheck,forcetypeassert
+	}
+
+	writeAdminJSON(w, http.StatusCreated, result)
+}
+
+func (s *Server) adminDeleteExpectation(w http.ResponseWriter, rawID string) {
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid expectation id: %s", rawID), http.StatusBadRequest)
+
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	found := false
+
+	s.removeExpectations(func(e *requestExpectation) bool {
+		if e.id == id {
+			found = true
+
+			return true
+		}
+
+		return false
+	})
+
+	if !found {
+		http.Error(w, fmt.Sprintf("expectation not found: %d", id), http.StatusNotFound)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeAdminJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	_ = json.NewEncoder(w).Encode(v) //nolint: errcheck
+}
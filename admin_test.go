@@ -0,0 +1,162 @@
+package httpmock_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/planner"
+)
+
+func TestServer_WithAdmin(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer().
+		WithPlanner(planner.FirstMatch()).
+		WithAdmin()
+
+	defer s.Close()
+
+	s.ExpectGet("/hi").Return("hello")
+
+	// Add expectations.
+	code, _, body, _ := doRequest(t, s.URL(), http.MethodPost, "/__admin/expectations", nil,
+		[]byte(`{"method": "GET", "uri": "/users", "headers": {"Authorization": "Bearer token"}, "response": {"body": "[]"}}`), 0,
+	)
+
+	assert.Equal(t, http.StatusCreated, code)
+	assert.JSONEq(t, `[{"id":2,"method":"GET","uri":"/users","headers":{"Authorization":"Bearer token"},"fulfilled":0,"remaining":0}]`, string(body))
+
+	code, _, body, _ = doRequest(t, s.URL(), http.MethodGet, "/users", Header{"Authorization": "Bearer token"}, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `[]`, string(body))
+
+	// List expectations.
+	code, headers, body, _ := doRequest(t, s.URL(), http.MethodGet, "/__admin/expectations", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "application/json", headers["Content-Type"])
+	assert.JSONEq(t, `[
+		{"id":1,"method":"GET","uri":"/hi","fulfilled":0,"remaining":1},
+		{"id":2,"method":"GET","uri":"/users","headers":{"Authorization":"Bearer token"},"fulfilled":1,"remaining":0}
+	]`, string(body))
+
+	// Delete an expectation.
+	code, _, _, _ = doRequest(t, s.URL(), http.MethodDelete, "/__admin/expectations/1", nil, nil, 0)
+
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.NoError(t, s.ExpectationsWereMet())
+
+	code, _, body, _ = doRequest(t, s.URL(), http.MethodDelete, "/__admin/expectations/1", nil, nil, 0)
+
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, "expectation not found: 1\n", string(body))
+
+	// Fetch the journal.
+	code, _, body, _ = doRequest(t, s.URL(), http.MethodGet, "/__admin/requests", nil, nil, 0)
+
+	var journal []httpmock.JournalEntry
+
+	require.NoError(t, json.Unmarshal(body, &journal))
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, journal, 1)
+	assert.Equal(t, "/users", journal[0].RequestURI)
+	assert.True(t, journal[0].Matched)
+
+	// Clear the journal.
+	code, _, _, _ = doRequest(t, s.URL(), http.MethodDelete, "/__admin/requests", nil, nil, 0)
+
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Empty(t, s.Journal())
+
+	// Delete all expectations.
+	code, _, _, _ = doRequest(t, s.URL(), http.MethodDelete, "/__admin/expectations", nil, nil, 0)
+
+	assert.Equal(t, http.StatusNoContent, code)
+
+	code, _, body, _ = doRequest(t, s.URL(), http.MethodGet, "/__admin/expectations", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `[]`, string(body))
+}
+
+func TestServer_WithAdmin_Error(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario     string
+		method       string
+		uri          string
+		body         []byte
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			scenario:     "invalid spec",
+			method:       http.MethodPost,
+			uri:          "/__admin/expectations",
+			body:         []byte(`[{"method": "GET"}]`),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "invalid expectation #1: missing uri or uriPattern\n",
+		},
+		{
+			scenario:     "missing response file",
+			method:       http.MethodPost,
+			uri:          "/__admin/expectations",
+			body:         []byte(`[{"method": "GET", "uri": "/file", "response": {"file": "not-found.txt"}}]`),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "invalid expectation #1: invalid response file: stat not-found.txt: no such file or directory\n",
+		},
+		{
+			scenario:     "invalid id",
+			method:       http.MethodDelete,
+			uri:          "/__admin/expectations/foo",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "invalid expectation id: foo\n",
+		},
+		{
+			scenario:     "unknown endpoint",
+			method:       http.MethodGet,
+			uri:          "/__admin/unknown",
+			expectedCode: http.StatusNotFound,
+			expectedBody: "404 page not found\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			s := httpmock.NewServer().WithAdmin()
+
+			defer s.Close()
+
+			code, _, body, _ := doRequest(t, s.URL(), tc.method, tc.uri, nil, tc.body, 0)
+
+			assert.Equal(t, tc.expectedCode, code)
+			assert.Equal(t, tc.expectedBody, string(body))
+		})
+	}
+}
+
+func TestServer_WithoutAdmin(t *testing.T) {
+	t.Parallel()
+
+	testingT := T()
+
+	s := httpmock.NewServer().WithTest(testingT)
+
+	defer s.Close()
+
+	code, _, _, _ := doRequest(t, s.URL(), http.MethodGet, "/__admin/expectations", nil, nil, 0)
+
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Equal(t, `unexpected request received: GET /__admin/expectations`, testingT.String())
+}
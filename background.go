@@ -0,0 +1,46 @@
+package httpmock
+
+import (
+	"net/http"
+
+	"go.nhat.io/httpmock/planner"
+)
+
+// ExpectBackground adds a new expected request that is answered in the background, for example, the liveness probes of
+// the system under test. The expectation is matched before the planner, it can be matched any number of times, even
+// none, and it is not checked by ExpectationsWereMet, so it does not interfere with the other expectations.
+//
+//	Server.ExpectBackground(httpmock.MethodGet, "/healthz").
+//		Return(`{"status":"ok"}`)
+func (s *Server) ExpectBackground(method string, requestURI any) Expectation {
+	expect := s.newExpectation(method, requestURI)
+
+	expect.UnlimitedTimes()
+	expect.register = s.registerBackgroundClone
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.background = append(s.background, expect)
+
+	return expect
+}
+
+// registerBackgroundClone registers a clone of a background expectation, see Expectation.Clone.
+func (s *Server) registerBackgroundClone(expect *requestExpectation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.background = append(s.background, expect)
+}
+
+// findBackgroundHandler finds a background expectation for the request. The caller must hold the lock.
+func (s *Server) findBackgroundHandler(r *http.Request) *requestExpectation {
+	for _, expected := range s.background {
+		if planner.MatchRequest(expected, r) == nil {
+			return expected
+		}
+	}
+
+	return nil
+}
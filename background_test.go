@@ -0,0 +1,42 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestServer_ExpectBackground(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer().WithTest(T())
+	defer s.Close()
+
+	s.ExpectBackground(httpmock.MethodGet, "/healthz").
+		Return("ok")
+
+	s.ExpectGet("/first").Return("first")
+	s.ExpectGet("/second").Return("second")
+
+	// The background expectation does not need to be called.
+	assert.Error(t, s.ExpectationsWereMet())
+
+	for _, uri := range []string{"/healthz", "/first", "/healthz", "/healthz", "/second", "/healthz"} {
+		code, _, _, _ := doRequest(t, s.URL(), http.MethodGet, uri, nil, nil, 0)
+
+		assert.Equal(t, http.StatusOK, code)
+	}
+
+	assert.NoError(t, s.ExpectationsWereMet())
+	assert.Len(t, s.Journal(), 6)
+	assert.Len(t, s.MatchedExpectations(), 2)
+
+	s.ResetExpectations()
+
+	code, _, _, _ := doRequest(t, s.URL(), http.MethodGet, "/healthz", nil, nil, 0)
+
+	assert.Equal(t, http.StatusInternalServerError, code)
+}
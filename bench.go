@@ -0,0 +1,112 @@
+package httpmock
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// RequestSpec is a request to send to the server, see Bench.
+type RequestSpec struct {
+	// Method is the HTTP method of the request.
+	Method string
+	// URI is the request uri, relative to the url of the server.
+	URI string
+	// Header is the header of the request.
+	Header Header
+	// Body is the body of the request.
+	Body []byte
+	// ExpectedCode is the expected status code of the response, 0 if any code below 400 is expected.
+	ExpectedCode int
+}
+
+// Bench drives the server with the requests at high concurrency, using b.RunParallel, and reports the throughput in
+// requests per second. The requests are sent in turn, so the server must have the expectations for them, usually
+// unlimited. The benchmark fails if a response has an unexpected status code.
+//
+//	func BenchmarkUsers(b *testing.B) {
+//		srv := httpmock.MockServer(func(s *httpmock.Server) {
+//			s.ExpectGet("/users").
+//				Return(`[]`).
+//				UnlimitedTimes()
+//		})
+//
+//		defer srv.Close()
+//
+//		httpmock.Bench(b, srv, httpmock.RequestSpec{Method: httpmock.MethodGet, URI: "/users"})
+//	}
+func Bench(b *testing.B, s *Server, reqs ...RequestSpec) {
+	b.Helper()
+
+	if len(reqs) == 0 {
+		b.Fatal("no request to benchmark")
+	}
+
+	client := newBenchClient(s.Client())
+	url := s.URL()
+
+	var next uint64
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	start := time.Now()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			req := reqs[(atomic.AddUint64(&next, 1)-1)%uint64(len(reqs))]
+
+			if err := sendBenchRequest(client, url, req); err != nil {
+				b.Error(err)
+
+				return
+			}
+		}
+	})
+
+	b.ReportMetric(float64(b.N)/time.Since(start).Seconds(), "req/s")
+}
+
+// newBenchClient returns a copy of the client that keeps enough idle connections for the parallel requests.
+func newBenchClient(c *http.Client) *http.Client {
+	client := *c
+
+	if t, ok := client.Transport.(*http.Transport); ok {
+		t = t.Clone()
+		t.MaxIdleConnsPerHost = 100
+
+		client.Transport = t
+	}
+
+	return &client
+}
+
+func sendBenchRequest(client *http.Client, url string, spec RequestSpec) error {
+	req, err := http.NewRequestWithContext(context.Background(), spec.Method, url+spec.URI, bytes.NewReader(spec.Body))
+	if err != nil {
+		return err
+	}
+
+	for k, v := range spec.Header {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body) // nolint: errcheck
+	_ = resp.Body.Close()                 // nolint: errcheck
+
+	if spec.ExpectedCode == 0 && resp.StatusCode < http.StatusBadRequest || resp.StatusCode == spec.ExpectedCode {
+		return nil
+	}
+
+	return fmt.Errorf("unexpected status code of %s %s: %d", spec.Method, spec.URI, resp.StatusCode) // nolint: goerr113
+}
@@ -0,0 +1,78 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/planner"
+)
+
+func BenchmarkServer_ServeHTTP(b *testing.B) {
+	benchmarkServeHTTP(b, httpmock.NewUnstartedServer())
+}
+
+func BenchmarkServer_ServeHTTP_WithoutBodyCapture(b *testing.B) {
+	benchmarkServeHTTP(b, httpmock.NewUnstartedServer().WithoutBodyCapture())
+}
+
+func benchmarkServeHTTP(b *testing.B, s *httpmock.Server) {
+	b.Helper()
+
+	s.WithPlanner(planner.FirstMatch()).
+		WithDefaultResponseHeaders(httpmock.Header{"Content-Type": "application/json"})
+
+	s.ExpectPost("/users").
+		WithHeader("Authorization", "Bearer token").
+		Return(`{"id":42}`).
+		UnlimitedTimes()
+
+	body := strings.Repeat("x", 4096)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer token")
+
+		w := httptest.NewRecorder()
+
+		s.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			b.Fatalf("unexpected status code: %d", w.Code)
+		}
+	}
+}
+
+func BenchmarkBench(b *testing.B) {
+	s := httpmock.MockServer(func(s *httpmock.Server) {
+		s.WithPlanner(planner.FirstMatch())
+
+		s.ExpectGet("/users").
+			Return(`[]`).
+			UnlimitedTimes()
+
+		s.ExpectPost("/users").
+			WithHeader("Authorization", "Bearer token").
+			ReturnCode(httpmock.StatusCreated).
+			Return(`{"id":42}`).
+			UnlimitedTimes()
+	})
+
+	defer s.Close()
+
+	httpmock.Bench(b, s,
+		httpmock.RequestSpec{Method: httpmock.MethodGet, URI: "/users"},
+		httpmock.RequestSpec{
+			Method:       httpmock.MethodPost,
+			URI:          "/users",
+			Header:       httpmock.Header{"Authorization": "Bearer token"},
+			Body:         []byte(`{"name":"john"}`),
+			ExpectedCode: httpmock.StatusCreated,
+		},
+	)
+}
@@ -0,0 +1,160 @@
+package httpmock
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CA is a certificate authority that signs the certificates of the hosts on the fly, for example, to intercept the
+// tunnels of a forward proxy, see Server.WithProxyCA. The clients trust the certificates of the hosts if they trust
+// the certificate of the authority, see CertPool and CertificatePEM.
+type CA struct {
+	// Certificate is the self-signed certificate of the authority.
+	Certificate *x509.Certificate
+	// PrivateKey is the private key of the authority.
+	PrivateKey *ecdsa.PrivateKey
+
+	mu    sync.Mutex
+	certs map[string]*tls.Certificate
+}
+
+// GenerateCA generates a self-signed certificate authority that is valid for a day.
+//
+//	ca, err := httpmock.GenerateCA()
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//
+//	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: ca.CertPool()}}}
+func GenerateCA() (*CA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"httpmock"}, CommonName: "httpmock CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CA{
+		Certificate: cert,
+		PrivateKey:  key,
+		certs:       make(map[string]*tls.Certificate),
+	}, nil
+}
+
+// CertPool returns a pool that trusts the certificate authority, for example, for the RootCAs of a tls.Config.
+func (ca *CA) CertPool() *x509.CertPool {
+	pool := x509.NewCertPool()
+
+	pool.AddCert(ca.Certificate)
+
+	return pool
+}
+
+// CertificatePEM returns the certificate of the authority in PEM, for example, to install it to the trust store of a
+// client that is not written in Go.
+func (ca *CA) CertificatePEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Certificate.Raw})
+}
+
+// ServerCertFor returns a certificate of the host signed by the certificate authority. The host could be a domain name
+// or an IP, with or without a port. The certificates are cached by host.
+//
+//	cert, err := ca.ServerCertFor("api.example.com")
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//
+//	srv := httptest.NewUnstartedServer(handler)
+//	srv.TLS = &tls.Config{Certificates: []tls.Certificate{*cert}}
+//	srv.StartTLS()
+func (ca *CA) ServerCertFor(host string) (*tls.Certificate, error) {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	host = strings.ToLower(strings.Trim(host, "[]"))
+
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+
+	if cert, ok := ca.certs[host]; ok {
+		return cert, nil
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{Organization: []string{"httpmock"}, CommonName: host},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	} else {
+		template.DNSNames = []string{host}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.Certificate, &key.PublicKey, ca.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	cert := &tls.Certificate{Certificate: [][]byte{der, ca.Certificate.Raw}, PrivateKey: key}
+
+	ca.certs[host] = cert
+
+	return cert, nil
+}
+
+func newSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
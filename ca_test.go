@@ -0,0 +1,122 @@
+package httpmock_test
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestGenerateCA(t *testing.T) {
+	t.Parallel()
+
+	ca, err := httpmock.GenerateCA()
+	require.NoError(t, err)
+
+	assert.True(t, ca.Certificate.IsCA)
+	assert.Equal(t, "httpmock CA", ca.Certificate.Subject.CommonName)
+
+	block, rest := pem.Decode(ca.CertificatePEM())
+
+	require.NotNil(t, block)
+	assert.Empty(t, rest)
+	assert.Equal(t, "CERTIFICATE", block.Type)
+	assert.Equal(t, ca.Certificate.Raw, block.Bytes)
+}
+
+func TestCA_ServerCertFor(t *testing.T) {
+	t.Parallel()
+
+	ca, err := httpmock.GenerateCA()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		scenario string
+		host     string
+		dnsName  string
+	}{
+		{
+			scenario: "domain name",
+			host:     "API.example.com",
+			dnsName:  "api.example.com",
+		},
+		{
+			scenario: "domain name with port",
+			host:     "api.example.com:8443",
+			dnsName:  "api.example.com",
+		},
+		{
+			scenario: "ipv4",
+			host:     "127.0.0.1:443",
+			dnsName:  "127.0.0.1",
+		},
+		{
+			scenario: "ipv6",
+			host:     "[::1]:443",
+			dnsName:  "::1",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			cert, err := ca.ServerCertFor(tc.host)
+			require.NoError(t, err)
+
+			leaf, err := x509.ParseCertificate(cert.Certificate[0])
+			require.NoError(t, err)
+
+			_, err = leaf.Verify(x509.VerifyOptions{DNSName: tc.dnsName, Roots: ca.CertPool()})
+
+			assert.NoError(t, err)
+
+			_, err = leaf.Verify(x509.VerifyOptions{DNSName: "other.example.com", Roots: ca.CertPool()})
+
+			assert.Error(t, err)
+
+			again, err := ca.ServerCertFor(tc.host)
+			require.NoError(t, err)
+
+			assert.Same(t, cert, again)
+		})
+	}
+}
+
+func TestServer_WithProxyCA(t *testing.T) {
+	t.Parallel()
+
+	ca, err := httpmock.GenerateCA()
+	require.NoError(t, err)
+
+	s := httpmock.NewServer().
+		WithTest(T()).
+		WithProxyCA(ca)
+
+	defer s.Close()
+
+	s.ExpectGet("/users").
+		WithHost("api.example.com").
+		Return("users")
+
+	assert.Equal(t, ca.Certificate, s.ProxyCA())
+
+	resp, err := s.ProxyClient().Get("https://api.example.com/users") //nolint: noctx
+	require.NoError(t, err)
+
+	defer resp.Body.Close() //nolint: errcheck
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "users", string(body))
+	assert.Equal(t, "CN=api.example.com,O=httpmock", resp.TLS.PeerCertificates[0].Subject.String())
+}
@@ -0,0 +1,88 @@
+package httpmock
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"go.nhat.io/httpmock/planner"
+)
+
+// WithoutCountingCancelledRequests does not count the requests that the clients cancelled before the responses were
+// written as the calls of their expectations, so the expectations are expected again, for example, when the client
+// retries after a timeout. If the planner has already moved past the expectation, like the sequence planner does, the
+// expectation is queued again at the end. The cancelled requests are marked in the journal, see
+// JournalEntry.ClientCancelled.
+func (s *Server) WithoutCountingCancelledRequests() *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.uncountCancelled = true
+
+	return s
+}
+
+// isClientCancelled checks whether the handling of the request failed because the client cancelled it, for example,
+// the client disconnected while the response was delayed.
+func isClientCancelled(r *http.Request, err error) bool {
+	return err != nil && !errors.Is(err, ErrHandlerPanicked) && errors.Is(r.Context().Err(), context.Canceled)
+}
+
+// forgetCancelled reverts the call of the expectation if the cancelled requests are not counted.
+func (s *Server) forgetCancelled(h ExpectationHandler) {
+	e, ok := asRequestExpectation(h)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The background expectations are not registered.
+	if !s.uncountCancelled || e.id == 0 {
+		return
+	}
+
+	e.unfulfill()
+
+	for i := len(s.Requests) - 1; i >= 0; i-- {
+		if planner.Unwrap(s.Requests[i]) == e {
+			s.Requests = append(s.Requests[:i], s.Requests[i+1:]...)
+
+			break
+		}
+	}
+
+	for _, expected := range s.planner.Remain() {
+		if expected == e {
+			return
+		}
+	}
+
+	s.planner.Expect(e)
+}
+
+// unfulfill reverts the last call of the expectation.
+func (e *requestExpectation) unfulfill() {
+	e.lock()
+	defer e.unlock()
+
+	if e.fulfilledTimes == 0 {
+		return
+	}
+
+	e.fulfilledTimes--
+
+	if e.times > 0 {
+		e.repeatTimes++
+	}
+
+	e.calledAt = e.calledAt[:len(e.calledAt)-1]
+
+	if len(e.calledAt) == 0 {
+		e.firstCalledAt, e.lastCalledAt = time.Time{}, time.Time{}
+	} else {
+		e.lastCalledAt = e.calledAt[len(e.calledAt)-1]
+	}
+}
@@ -0,0 +1,103 @@
+package httpmock_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/planner"
+)
+
+func cancelledRequest(t *testing.T, s *httpmock.Server, uri string) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL()+uri, nil)
+	require.NoError(t, err)
+
+	_, err = http.DefaultClient.Do(req) // nolint: bodyclose
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.Eventually(t, func() bool {
+		return len(s.Journal()) > 0
+	}, 500*time.Millisecond, 10*time.Millisecond, "the handler is not aborted")
+}
+
+func TestServer_ClientCancelled(t *testing.T) {
+	t.Parallel()
+
+	testingT := &errorRecorderT{}
+
+	s := httpmock.NewServer().WithTest(testingT)
+	defer s.Close()
+
+	s.ExpectGet("/slow").
+		After(time.Minute)
+
+	cancelledRequest(t, s, "/slow")
+
+	journal := s.Journal()
+
+	assert.True(t, journal[0].Matched)
+	assert.True(t, journal[0].ClientCancelled)
+	assert.Empty(t, testingT.Errors())
+	assert.NoError(t, s.ExpectationsWereMet())
+}
+
+func TestServer_WithoutCountingCancelledRequests(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.New(func(s *httpmock.Server) {
+		s.WithoutCountingCancelledRequests()
+
+		s.ExpectGet("/slow").
+			AfterFunc(func(callN uint) time.Duration {
+				if callN == 1 {
+					return time.Minute
+				}
+
+				return 0
+			}).
+			Return("hello world!")
+	})(t)
+
+	cancelledRequest(t, s, "/slow")
+
+	assert.True(t, s.Journal()[0].ClientCancelled)
+	assert.Error(t, s.ExpectationsWereMet())
+	assert.Equal(t, uint(0), s.Stats()[0].FulfilledTimes)
+	assert.Empty(t, s.MatchedExpectations())
+
+	// The retry is expected.
+	code, _, body, _ := doRequest(t, s.URL(), http.MethodGet, "/slow", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "hello world!", string(body))
+	assert.False(t, s.Journal()[1].ClientCancelled)
+}
+
+func TestServer_WithoutCountingCancelledRequests_PerSession(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer().
+		WithPlanner(planner.PerSession(planner.SessionByRemoteAddr(), planner.FirstMatch)).
+		WithoutCountingCancelledRequests()
+	defer s.Close()
+
+	s.ExpectGet("/slow").
+		After(time.Minute).
+		UnlimitedTimes()
+
+	cancelledRequest(t, s, "/slow")
+
+	assert.True(t, s.Journal()[0].ClientCancelled)
+	assert.Equal(t, uint(0), s.Stats()[0].FulfilledTimes)
+	assert.Empty(t, s.MatchedExpectations())
+}
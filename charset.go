@@ -0,0 +1,104 @@
+package httpmock
+
+import (
+	"encoding/binary"
+	"fmt"
+	"mime"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// windows1252 maps the runes of the bytes from 0x80 to 0x9F of windows-1252, the other bytes are the same as
+// ISO-8859-1.
+var windows1252 = map[rune]byte{
+	'€': 0x80, '\u0081': 0x81, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86,
+	'‡': 0x87, 'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, '\u008D': 0x8D,
+	'Ž': 0x8E, '\u008F': 0x8F, '\u0090': 0x90, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94,
+	'•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, '\u009D': 0x9D, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// encodeCharset transcodes the utf-8 string to the charset. The supported charsets are utf-8, us-ascii, iso-8859-1,
+// windows-1252, utf-16, utf-16le and utf-16be, and their common aliases.
+func encodeCharset(s, charset string) ([]byte, error) {
+	switch strings.ToLower(charset) {
+	case "utf-8", "utf8":
+		if !utf8.ValidString(s) {
+			return nil, fmt.Errorf("could not encode %q to %s: invalid utf-8", s, charset) // nolint: goerr113
+		}
+
+		return []byte(s), nil
+
+	case "us-ascii", "ascii":
+		return encodeSingleByte(s, charset, func(r rune) (byte, bool) {
+			return byte(r), r < utf8.RuneSelf
+		})
+
+	case "iso-8859-1", "iso8859-1", "latin1", "l1":
+		return encodeSingleByte(s, charset, func(r rune) (byte, bool) {
+			return byte(r), r <= 0xFF
+		})
+
+	case "windows-1252", "cp1252":
+		return encodeSingleByte(s, charset, func(r rune) (byte, bool) {
+			if b, ok := windows1252[r]; ok {
+				return b, true
+			}
+
+			return byte(r), r <= 0x7F || (r >= 0xA0 && r <= 0xFF)
+		})
+
+	case "utf-16", "utf-16be":
+		return encodeUTF16(s, binary.BigEndian, strings.EqualFold(charset, "utf-16")), nil
+
+	case "utf-16le":
+		return encodeUTF16(s, binary.LittleEndian, false), nil
+	}
+
+	return nil, fmt.Errorf("could not encode %q to %s: unsupported charset", s, charset) // nolint: goerr113
+}
+
+func encodeSingleByte(s, charset string, encode func(r rune) (byte, bool)) ([]byte, error) {
+	result := make([]byte, 0, len(s))
+
+	for _, r := range s {
+		b, ok := encode(r)
+		if !ok {
+			return nil, fmt.Errorf("could not encode %q to %s: %q is not in the charset", s, charset, r) // nolint: goerr113
+		}
+
+		result = append(result, b)
+	}
+
+	return result, nil
+}
+
+// encodeUTF16 encodes the string to utf-16, with the byte order mark if bom is true.
+func encodeUTF16(s string, order binary.ByteOrder, bom bool) []byte {
+	units := utf16.Encode([]rune(s))
+
+	if bom {
+		units = append([]uint16{0xFEFF}, units...)
+	}
+
+	result := make([]byte, 2*len(units))
+
+	for i, u := range units {
+		order.PutUint16(result[2*i:], u)
+	}
+
+	return result
+}
+
+// withCharset sets the charset parameter of the content type, text/plain is used if the content type is empty.
+func withCharset(contentType, charset string) string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, params = "text/plain", make(map[string]string)
+	}
+
+	params["charset"] = charset
+
+	return mime.FormatMediaType(mediaType, params)
+}
@@ -0,0 +1,116 @@
+package httpmock
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeCharset(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		charset       string
+		body          string
+		expected      []byte
+		expectedError string
+	}{
+		{
+			scenario: "utf-8",
+			charset:  "UTF-8",
+			body:     "café",
+			expected: []byte("café"),
+		},
+		{
+			scenario:      "invalid utf-8",
+			charset:       "utf-8",
+			body:          "caf\xe9",
+			expectedError: `could not encode "caf\xe9" to utf-8: invalid utf-8`,
+		},
+		{
+			scenario: "us-ascii",
+			charset:  "US-ASCII",
+			body:     "cafe",
+			expected: []byte("cafe"),
+		},
+		{
+			scenario:      "not in us-ascii",
+			charset:       "ascii",
+			body:          "café",
+			expectedError: `could not encode "café" to ascii: 'é' is not in the charset`,
+		},
+		{
+			scenario: "iso-8859-1",
+			charset:  "ISO-8859-1",
+			body:     "café ÿ",
+			expected: []byte("caf\xe9 \xff"),
+		},
+		{
+			scenario:      "not in iso-8859-1",
+			charset:       "latin1",
+			body:          "5€",
+			expectedError: `could not encode "5€" to latin1: '€' is not in the charset`,
+		},
+		{
+			scenario: "windows-1252",
+			charset:  "windows-1252",
+			body:     "5€ “café”",
+			expected: []byte("5\x80 \x93caf\xe9\x94"),
+		},
+		{
+			scenario:      "not in windows-1252",
+			charset:       "cp1252",
+			body:          "\u0080",
+			expectedError: `could not encode "\u0080" to cp1252: '\u0080' is not in the charset`,
+		},
+		{
+			scenario: "utf-16",
+			charset:  "UTF-16",
+			body:     "é€",
+			expected: []byte{0xFE, 0xFF, 0x00, 0xE9, 0x20, 0xAC},
+		},
+		{
+			scenario: "utf-16be",
+			charset:  "utf-16be",
+			body:     "é€",
+			expected: []byte{0x00, 0xE9, 0x20, 0xAC},
+		},
+		{
+			scenario: "utf-16le",
+			charset:  "utf-16le",
+			body:     "é€",
+			expected: []byte{0xE9, 0x00, 0xAC, 0x20},
+		},
+		{
+			scenario:      "unsupported",
+			charset:       "shift_jis",
+			body:          "cafe",
+			expectedError: `could not encode "cafe" to shift_jis: unsupported charset`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := encodeCharset(tc.body, tc.charset)
+
+			assert.Equal(t, tc.expected, actual)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestWithCharset(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "text/plain; charset=ISO-8859-1", withCharset("", "ISO-8859-1"))
+	assert.Equal(t, "text/html; charset=ISO-8859-1", withCharset("text/html; charset=utf-8", "ISO-8859-1"))
+}
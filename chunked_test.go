@@ -0,0 +1,90 @@
+package httpmock_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/matcher"
+)
+
+// chunkedReader hides the length of the body, so the client sends it with Transfer-Encoding: chunked.
+type chunkedReader struct {
+	io.Reader
+}
+
+func TestServer_ChunkedUpload(t *testing.T) {
+	t.Parallel()
+
+	payload := strings.Repeat("0123456789", 10000)
+	sum := sha256.Sum256([]byte(payload))
+	digest := hex.EncodeToString(sum[:])
+
+	testCases := []struct {
+		scenario     string
+		server       func() *httpmock.Server
+		body         any
+		expectedBody string
+	}{
+		{
+			scenario:     "exact body",
+			server:       httpmock.NewServer,
+			body:         payload,
+			expectedBody: payload,
+		},
+		{
+			scenario:     "hash",
+			server:       httpmock.NewServer,
+			body:         matcher.BodyHash("sha256", sha256.New, digest),
+			expectedBody: payload,
+		},
+		{
+			scenario: "hash without body capture",
+			server: func() *httpmock.Server {
+				return httpmock.NewServer().WithoutBodyCapture()
+			},
+			body: matcher.BodyHash("sha256", sha256.New, digest),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			testingT := T()
+
+			s := tc.server().WithTest(testingT).WithHeaderOrderCapture()
+
+			defer s.Close()
+
+			s.ExpectPost("/upload").
+				WithBody(tc.body).
+				ReturnCode(http.StatusCreated)
+
+			req, err := http.NewRequest(http.MethodPost, s.URL()+"/upload", chunkedReader{strings.NewReader(payload)}) //nolint: noctx
+			require.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+
+			defer resp.Body.Close() // nolint: errcheck
+
+			assert.Equal(t, http.StatusCreated, resp.StatusCode)
+			assert.Empty(t, testingT.String())
+
+			journal := s.Journal()
+
+			require.Len(t, journal, 1)
+			assert.Contains(t, journal[0].HeaderOrder, "Transfer-Encoding")
+			assert.Equal(t, tc.expectedBody, journal[0].Body)
+		})
+	}
+}
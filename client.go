@@ -0,0 +1,180 @@
+package httpmock
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// ClientOption configures the client of DoRequestWithClientOptions.
+type ClientOption func(c *http.Client)
+
+// ClientResponse is the response of DoRequestWithClientOptions.
+type ClientResponse struct {
+	// Code is the status code of the final response.
+	Code int
+	// Header is the header of the final response.
+	Header map[string]string
+	// Body is the body of the final response.
+	Body []byte
+	// Elapsed is the total execution time, including the redirects.
+	Elapsed time.Duration
+	// Redirects are the responses that redirected the request before the final response, in order. Their bodies are
+	// already closed.
+	Redirects []*http.Response
+}
+
+// WithClientTimeout sets the timeout of the client. The default timeout is 1 second.
+func WithClientTimeout(timeout time.Duration) ClientOption {
+	return func(c *http.Client) {
+		c.Timeout = timeout
+	}
+}
+
+// WithClientCookieJar sets the cookie jar of the client, so the cookies are kept across the requests and the redirects.
+//
+//	jar, _ := cookiejar.New(nil)
+//
+//	httpmock.DoRequestWithClientOptions(t, http.MethodGet, srv.URL()+"/login", nil, nil,
+//		httpmock.WithClientCookieJar(jar),
+//	)
+func WithClientCookieJar(jar http.CookieJar) ClientOption {
+	return func(c *http.Client) {
+		c.Jar = jar
+	}
+}
+
+// WithClientNoRedirect stops the client from following the redirects, the redirect response is the final response.
+func WithClientNoRedirect() ClientOption {
+	return func(c *http.Client) {
+		c.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+}
+
+// WithClientMaxRedirects sets the maximum number of redirects to follow, the request fails if there are more. By
+// default, the client follows the redirects like http.Client does.
+func WithClientMaxRedirects(n int) ClientOption {
+	return func(c *http.Client) {
+		c.CheckRedirect = func(_ *http.Request, via []*http.Request) error {
+			if len(via) > n {
+				return fmt.Errorf("stopped after %d redirects", n) // nolint: goerr113
+			}
+
+			return nil
+		}
+	}
+}
+
+// WithClientTLSConfig sets the TLS configuration of the client, for example, to trust the certificate of a TLS server.
+func WithClientTLSConfig(cfg *tls.Config) ClientOption {
+	return func(c *http.Client) {
+		c.Transport = &http.Transport{TLSClientConfig: cfg}
+	}
+}
+
+// DoRequestWithClientOptions sends a HTTP request with a configurable client and returns the final response along with
+// the redirect responses before it.
+//
+//	resp := httpmock.DoRequestWithClientOptions(t, http.MethodGet, srv.URL()+"/old", nil, nil,
+//		httpmock.WithClientNoRedirect(),
+//	)
+func DoRequestWithClientOptions(
+	tb testing.TB,
+	method, requestURI string,
+	headers Header,
+	body []byte,
+	opts ...ClientOption,
+) ClientResponse {
+	tb.Helper()
+
+	client := newClient(opts...)
+
+	var redirects []*http.Response
+
+	checkRedirect := client.CheckRedirect
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		redirects = append(redirects, req.Response)
+
+		if checkRedirect != nil {
+			return checkRedirect(req, via)
+		}
+
+		return defaultCheckRedirect(req, via)
+	}
+
+	req := newRequest(tb, method, requestURI, headers, body)
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	elapsed := time.Since(start)
+
+	require.NoError(tb, err, "could not make a request to mocked server")
+
+	code, header, respBody := readResponse(tb, resp)
+
+	// The last redirect response is the final response when the client does not follow it.
+	if len(redirects) > 0 && redirects[len(redirects)-1] == resp {
+		redirects = redirects[:len(redirects)-1]
+	}
+
+	return ClientResponse{
+		Code:      code,
+		Header:    header,
+		Body:      respBody,
+		Elapsed:   elapsed,
+		Redirects: redirects,
+	}
+}
+
+// DoRawRequest sends a HTTP request and returns the response without reading its body, so the test can assert on the
+// trailers, the TLS state, the protocol version or the streaming behavior. The body is closed when the test finishes.
+// The client times out after 1 second, including reading the body, use WithClientTimeout to change it.
+//
+//	resp := httpmock.DoRawRequest(t, http.MethodGet, srv.URL()+"/events", nil, nil)
+//
+//	assert.Equal(t, "HTTP/1.1", resp.Proto)
+func DoRawRequest(
+	tb testing.TB,
+	method, requestURI string,
+	headers Header,
+	body []byte,
+	opts ...ClientOption,
+) *http.Response {
+	tb.Helper()
+
+	client := newClient(opts...)
+	req := newRequest(tb, method, requestURI, headers, body)
+
+	resp, err := client.Do(req) // nolint: bodyclose
+	require.NoError(tb, err, "could not make a request to mocked server")
+
+	tb.Cleanup(func() {
+		_ = resp.Body.Close() // nolint: errcheck
+	})
+
+	return resp
+}
+
+func newClient(opts ...ClientOption) *http.Client {
+	client := &http.Client{Timeout: time.Second}
+
+	for _, o := range opts {
+		o(client)
+	}
+
+	return client
+}
+
+func defaultCheckRedirect(_ *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return fmt.Errorf("stopped after 10 redirects") // nolint: goerr113
+	}
+
+	return nil
+}
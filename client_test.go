@@ -0,0 +1,169 @@
+package httpmock_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/planner"
+)
+
+func TestDoRequestWithClientOptions_Redirect(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario          string
+		options           []httpmock.ClientOption
+		expectedCode      int
+		expectedBody      string
+		expectedRedirects []int
+	}{
+		{
+			scenario:          "follow",
+			expectedCode:      http.StatusOK,
+			expectedBody:      "c",
+			expectedRedirects: []int{http.StatusFound, http.StatusMovedPermanently},
+		},
+		{
+			scenario:     "no follow",
+			options:      []httpmock.ClientOption{httpmock.WithClientNoRedirect()},
+			expectedCode: http.StatusFound,
+		},
+		{
+			scenario:          "max redirects",
+			options:           []httpmock.ClientOption{httpmock.WithClientMaxRedirects(2)},
+			expectedCode:      http.StatusOK,
+			expectedBody:      "c",
+			expectedRedirects: []int{http.StatusFound, http.StatusMovedPermanently},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			s := httpmock.NewServer().WithPlanner(planner.FirstMatch())
+
+			defer s.Close()
+
+			s.ExpectGet("/a").UnlimitedTimes().
+				ReturnCode(httpmock.StatusFound).
+				ReturnHeader("Location", "/b")
+
+			s.ExpectGet("/b").UnlimitedTimes().
+				ReturnCode(httpmock.StatusMovedPermanently).
+				ReturnHeader("Location", "/c")
+
+			s.ExpectGet("/c").UnlimitedTimes().
+				Return("c")
+
+			resp := httpmock.DoRequestWithClientOptions(t, http.MethodGet, s.URL()+"/a", nil, nil, tc.options...)
+
+			redirects := make([]int, 0, len(resp.Redirects))
+
+			for _, r := range resp.Redirects {
+				redirects = append(redirects, r.StatusCode)
+			}
+
+			assert.Equal(t, tc.expectedCode, resp.Code)
+			assert.Equal(t, tc.expectedBody, string(resp.Body))
+
+			if tc.expectedRedirects == nil {
+				assert.Empty(t, redirects)
+			} else {
+				assert.Equal(t, tc.expectedRedirects, redirects)
+			}
+		})
+	}
+}
+
+func TestDoRequestWithClientOptions_CookieJar(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+
+	defer s.Close()
+
+	s.ExpectPost("/login").
+		ReturnHeader("Set-Cookie", "session=42; Path=/")
+
+	s.ExpectGet("/profile").
+		WithHeader("Cookie", "session=42").
+		Return("john")
+
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+
+	resp := httpmock.DoRequestWithClientOptions(t, http.MethodPost, s.URL()+"/login", nil, nil, httpmock.WithClientCookieJar(jar))
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = httpmock.DoRequestWithClientOptions(t, http.MethodGet, s.URL()+"/profile", nil, nil, httpmock.WithClientCookieJar(jar))
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "john", string(resp.Body))
+	assert.NoError(t, s.ExpectationsWereMet())
+}
+
+func TestDoRequestWithClientOptions_TLSConfig(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewUnstartedServer()
+
+	defer s.Close()
+
+	s.ExpectGet("/").Return("secured")
+
+	srv := httptest.NewTLSServer(s)
+
+	defer srv.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+
+	resp := httpmock.DoRequestWithClientOptions(t, http.MethodGet, srv.URL+"/", nil, nil,
+		httpmock.WithClientTLSConfig(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}),
+		httpmock.WithClientTimeout(0),
+	)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "secured", string(resp.Body))
+}
+
+func TestDoRawRequest(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+
+	defer s.Close()
+
+	s.ExpectGet("/events").
+		RunHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Trailer", "X-Checksum")
+
+			_, _ = w.Write([]byte("hello")) //nolint: errcheck
+
+			w.Header().Set("X-Checksum", "42")
+		}))
+
+	resp := httpmock.DoRawRequest(t, http.MethodGet, s.URL()+"/events", nil, nil)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "HTTP/1.1", resp.Proto)
+	assert.Nil(t, resp.TLS)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "hello", string(body))
+	assert.Equal(t, "42", resp.Trailer.Get("X-Checksum"))
+}
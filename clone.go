@@ -0,0 +1,86 @@
+package httpmock
+
+import (
+	"math/rand"
+	"sync"
+
+	"go.nhat.io/httpmock/matcher"
+)
+
+// Clone returns an independent copy of the expectation, without its calls, to register a variation of it. If the
+// expectation is registered with a server, the copy is registered with the same server, after the expectation. The
+// handlers are shared, so a handler that keeps its own state counts the calls of both, but the CallNumber of
+// ReturnTemplate is the calls of the expectation that handles the request.
+//
+//	Server.Expect(http.MethodGet, "/path").
+//		Return("hello world!").
+//		Clone().
+//		ReturnCode(httpmock.StatusInternalServerError)
+func (e *requestExpectation) Clone() Expectation {
+	e.lock()
+
+	c := &requestExpectation{
+		locker:               &sync.Mutex{},
+		waiter:               e.waiter,
+		requestMethod:        e.requestMethod,
+		requestURIMatcher:    e.requestURIMatcher,
+		requestHeaderMatcher: cloneMap(e.requestHeaderMatcher),
+		requestHeaderNames:   append([]string(nil), e.requestHeaderNames...),
+		requestMatchers:      append([]requestMatcher(nil), e.requestMatchers...),
+		responseCode:         e.responseCode,
+		responseHeader:       cloneMap(e.responseHeader),
+		cacheHeaders:         e.cacheHeaders,
+		handle:               e.handle,
+		example:              append([]byte(nil), e.example...),
+		httpHandler:          e.httpHandler,
+		timeout:              e.timeout,
+		timeoutCode:          e.timeoutCode,
+		timeoutBody:          append([]byte(nil), e.timeoutBody...),
+		random:               rand.New(rand.NewSource(e.random.Int63())), // nolint: gosec
+		repeatTimes:          e.times,
+		times:                e.times,
+		wrappers:             append([]func(next ExpectationHandler) ExpectationHandler(nil), e.wrappers...),
+		abortAfter:           e.abortAfter,
+		headerMergeOf:        e.headerMergeOf,
+		headerOrderCaptureOf: e.headerOrderCaptureOf,
+		noDefaultHeaders:     e.noDefaultHeaders,
+		within:               e.within,
+		location:             e.location,
+		tags:                 append([]string(nil), e.tags...),
+		register:             e.register,
+		scenarioOf:           e.scenarioOf,
+		scenario:             e.scenario,
+		scenarioState:        e.scenarioState,
+		scenarioNextState:    e.scenarioNextState,
+	}
+
+	if e.example == nil {
+		c.example = nil
+	}
+
+	if e.requestBodyMatcher != nil {
+		c.requestBodyMatcher = matcher.Body(e.requestBodyMatcher.Matcher())
+	}
+
+	e.unlock()
+
+	if c.register != nil {
+		c.register(c)
+	}
+
+	return c
+}
+
+func cloneMap[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return nil
+	}
+
+	c := make(map[K]V, len(m))
+
+	for k, v := range m {
+		c[k] = v
+	}
+
+	return c
+}
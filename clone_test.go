@@ -0,0 +1,65 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestExpectation_Clone(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		e := s.ExpectGet("/users/42").
+			WithHeader("Authorization", "Bearer token").
+			ReturnHeader("Content-Type", "application/json").
+			Return(`{"id":42}`)
+
+		e.Clone().
+			ReturnCode(httpmock.StatusInternalServerError).
+			Twice()
+
+		s.ExpectBackground(httpmock.MethodGet, "/healthz").
+			Return(`{"status":"ok"}`).
+			Clone()
+	})(t)
+
+	headers := Header{"Authorization": "Bearer token"}
+
+	code, respHeaders, body, _ := doRequest(t, srv.URL(), http.MethodGet, "/users/42", headers, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "application/json", respHeaders["Content-Type"])
+	assert.Equal(t, `{"id":42}`, string(body))
+
+	for i := 0; i < 2; i++ {
+		code, respHeaders, body, _ = doRequest(t, srv.URL(), http.MethodGet, "/users/42", headers, nil, 0)
+
+		assert.Equal(t, http.StatusInternalServerError, code)
+		assert.Equal(t, "application/json", respHeaders["Content-Type"])
+		assert.Equal(t, `{"id":42}`, string(body))
+	}
+
+	code, _, _, _ = doRequest(t, srv.URL(), http.MethodGet, "/healthz", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, srv.Expectations(), 2)
+}
+
+func TestExpectation_Clone_Standalone(t *testing.T) {
+	t.Parallel()
+
+	e := httpmock.NewExpectation(http.MethodGet, "/").
+		Return("hello world!")
+
+	c := e.Clone().
+		ReturnCode(httpmock.StatusNotFound).
+		UnlimitedTimes()
+
+	assert.Equal(t, uint(1), e.(httpmock.PlannedExpectation).RemainTimes())
+	assert.Equal(t, uint(0), c.(httpmock.PlannedExpectation).RemainTimes())
+	assert.NotSame(t, e, c)
+}
@@ -0,0 +1,165 @@
+// Package main provides a standalone mock server that serves the expectations loaded from files, so the same stubs can
+// be used from non-Go integration environments. The expectations can also be managed at runtime via the admin
+// endpoints, see httpmock.Server.WithAdmin.
+//
+//	httpmock -addr :8080 expectations.json [more-expectations.json...]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/planner"
+)
+
+// config is the configuration of the mock server.
+type config struct {
+	addr       string
+	planner    string
+	admin      bool
+	metrics    bool
+	delayScale float64
+	files      []string
+}
+
+// logT is a test.T that logs the errors instead of failing a test.
+type logT struct {
+	logger *log.Logger
+}
+
+func (t logT) Errorf(format string, args ...any) {
+	t.logger.Printf(format, args...)
+}
+
+func (logT) FailNow() {}
+
+func (logT) Cleanup(func()) {}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			_, _ = fmt.Fprintln(os.Stderr, err) //nolint: errcheck
+		}
+
+		os.Exit(1) // nolint: gocritic
+	}
+}
+
+func run(ctx context.Context, args []string, out io.Writer) error {
+	cfg, err := parseConfig(args, out)
+	if err != nil {
+		return err
+	}
+
+	logger := log.New(out, "httpmock: ", log.LstdFlags)
+
+	l, err := net.Listen("tcp", cfg.addr)
+	if err != nil {
+		return fmt.Errorf("could not listen on %s: %w", cfg.addr, err)
+	}
+
+	srv, err := newServer(cfg, logger)
+	if err != nil {
+		_ = l.Close() //nolint: errcheck
+
+		return err
+	}
+
+	srv.WithListener(l).Start()
+
+	defer srv.Close()
+
+	logger.Printf("serving on %s", srv.URL())
+
+	<-ctx.Done()
+
+	logger.Print("shutting down")
+
+	return nil
+}
+
+func parseConfig(args []string, out io.Writer) (config, error) {
+	var cfg config
+
+	fs := flag.NewFlagSet("httpmock", flag.ContinueOnError)
+	fs.SetOutput(out)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(out, "Usage: httpmock [flags] expectations.json [more-expectations.json...]") //nolint: errcheck
+
+		fs.PrintDefaults()
+	}
+
+	fs.StringVar(&cfg.addr, "addr", ":8080", "the address to listen on")
+	fs.StringVar(&cfg.planner, "planner", "first-match", "the execution planner: first-match, fifo, round-robin or sequence")
+	fs.BoolVar(&cfg.admin, "admin", true, "enable the admin endpoints at /__admin/")
+	fs.BoolVar(&cfg.metrics, "metrics", false, "expose the metrics in Prometheus format at /metrics")
+	fs.Float64Var(&cfg.delayScale, "delay-scale", 1, "scale the response delays of the expectations, 0 to ignore them")
+
+	if err := fs.Parse(args); err != nil {
+		return config{}, err
+	}
+
+	cfg.files = fs.Args()
+
+	return cfg, nil
+}
+
+func newServer(cfg config, logger *log.Logger) (*httpmock.Server, error) {
+	srv := httpmock.NewUnstartedServer().
+		WithTest(logT{logger: logger}).
+		WithSpecDelayScale(cfg.delayScale)
+
+	switch cfg.planner {
+	case "first-match":
+		srv.WithPlanner(planner.FirstMatch())
+
+	case "fifo":
+		srv.WithPlanner(planner.FIFO())
+
+	case "round-robin":
+		srv.WithPlanner(planner.RoundRobin())
+
+	case "sequence":
+		srv.WithPlanner(planner.Sequence())
+
+	default:
+		return nil, fmt.Errorf("unknown planner: %s", cfg.planner) // nolint: goerr113
+	}
+
+	if cfg.admin {
+		srv.WithAdmin()
+	}
+
+	if cfg.metrics {
+		srv.WithMetrics()
+	}
+
+	for _, file := range cfg.files {
+		specs, err := httpmock.LoadExpectationSpecs(file)
+		if err != nil {
+			return nil, fmt.Errorf("could not load %s: %w", file, err)
+		}
+
+		for i, spec := range specs {
+			if _, err := srv.ExpectSpec(spec); err != nil {
+				return nil, fmt.Errorf("could not load expectation #%d of %s: %w", i+1, file, err)
+			}
+		}
+
+		logger.Printf("loaded %d expectation(s) from %s", len(specs), file)
+	}
+
+	return srv, nil
+}
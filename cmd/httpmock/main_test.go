@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestNewServer(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "expectations.json")
+
+	err := os.WriteFile(file, []byte(`[
+		{"method": "GET", "uri": "/hi", "response": {"body": "hello"}},
+		{"method": "GET", "uri": "/bye", "response": {"code": 410, "body": "bye"}}
+	]`), 0o600)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+
+	srv, err := newServer(config{planner: "first-match", metrics: true, files: []string{file}}, log.New(&out, "", 0))
+	require.NoError(t, err)
+
+	srv.Start()
+
+	defer srv.Close()
+
+	for i := 0; i < 2; i++ {
+		code, _, body, _ := httpmock.DoRequest(t, http.MethodGet, srv.URL()+"/bye", nil, nil)
+
+		assert.Equal(t, http.StatusGone, code)
+		assert.Equal(t, "bye", string(body))
+
+		code, _, body, _ = httpmock.DoRequest(t, http.MethodGet, srv.URL()+"/hi", nil, nil)
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "hello", string(body))
+	}
+
+	code, _, _, _ := httpmock.DoRequest(t, http.MethodGet, srv.URL()+"/unknown", nil, nil)
+
+	assert.Equal(t, http.StatusInternalServerError, code)
+
+	code, _, body, _ := httpmock.DoRequest(t, http.MethodGet, srv.URL()+"/metrics", nil, nil)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, string(body), `httpmock_requests_total{result="matched"} 4`)
+	assert.Contains(t, out.String(), "loaded 2 expectation(s) from "+file)
+	assert.Contains(t, out.String(), "Actual: GET /unknown")
+}
+
+func TestNewServer_Error(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		config        config
+		expectedError string
+	}{
+		{
+			scenario:      "unknown planner",
+			config:        config{planner: "unknown"},
+			expectedError: "unknown planner: unknown",
+		},
+		{
+			scenario:      "file not found",
+			config:        config{planner: "sequence", files: []string{"not-found.json"}},
+			expectedError: "could not load not-found.json: open not-found.json: no such file or directory",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			srv, err := newServer(tc.config, log.New(&bytes.Buffer{}, "", 0))
+
+			assert.Nil(t, srv)
+			assert.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestNewServer_MissingResponseFile(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "expectations.json")
+
+	err := os.WriteFile(file, []byte(`[
+		{"method": "GET", "uri": "/file", "response": {"file": "not-found.txt"}}
+	]`), 0o600)
+	require.NoError(t, err)
+
+	srv, err := newServer(config{planner: "sequence", files: []string{file}}, log.New(&bytes.Buffer{}, "", 0))
+
+	assert.Nil(t, srv)
+	assert.EqualError(t, err, fmt.Sprintf("could not load expectation #1 of %s: invalid response file: stat not-found.txt: no such file or directory", file))
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var out bytes.Buffer
+
+	err := run(ctx, []string{"-addr", "127.0.0.1:0"}, &out)
+
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "serving on http://127.0.0.1:")
+	assert.Contains(t, out.String(), "shutting down")
+}
+
+func TestRun_InvalidFlag(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+
+	err := run(context.Background(), []string{"-unknown"}, &out)
+
+	assert.EqualError(t, err, "flag provided but not defined: -unknown")
+}
+
+func TestParseConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := parseConfig([]string{"-planner", "fifo", "-delay-scale", "0.5", "a.json", "b.json"}, &bytes.Buffer{})
+	require.NoError(t, err)
+
+	expected := config{
+		addr:       ":8080",
+		planner:    "fifo",
+		admin:      true,
+		delayScale: 0.5,
+		files:      []string{"a.json", "b.json"},
+	}
+
+	assert.Equal(t, expected, cfg)
+}
@@ -0,0 +1,72 @@
+package httpmock
+
+import (
+	"sort"
+	"time"
+)
+
+// inFlightRange is the time range when a request was in flight, from when it was received to when its response was
+// written. The ranges are recorded instead of counting the requests in flight, because the requests may wait for each
+// other before they are counted, for example, when they are handled by the same expectation.
+type inFlightRange struct {
+	start time.Time
+	end   time.Time
+}
+
+// MaxConcurrentObserved returns the maximum number of requests that were in flight at the same time, to assert that
+// the client honors its connection pool or parallelism limits. The maximum of each expectation is in Stats.
+//
+//	srv.ExpectGet("/users").UnlimitedTimes().After(100 * time.Millisecond)
+//
+//	// Your requests.
+//
+//	assert.LessOrEqual(t, srv.MaxConcurrentObserved(), 4)
+func (s *Server) MaxConcurrentObserved() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return maxConcurrent(s.inFlight)
+}
+
+// recordInFlight records the time range when a request of the expectation was in flight.
+func (e *requestExpectation) recordInFlight(start, end time.Time) {
+	e.lock()
+	defer e.unlock()
+
+	e.inFlight = append(e.inFlight, inFlightRange{start: start, end: end})
+}
+
+// maxConcurrent returns the maximum number of the ranges that overlap each other.
+func maxConcurrent(ranges []inFlightRange) int {
+	type event struct {
+		at    time.Time
+		delta int
+	}
+
+	events := make([]event, 0, 2*len(ranges))
+
+	for _, r := range ranges {
+		events = append(events, event{at: r.start, delta: 1}, event{at: r.end, delta: -1})
+	}
+
+	// A range that ends at the same time as another one starts does not overlap it.
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].at.Equal(events[j].at) {
+			return events[i].delta < events[j].delta
+		}
+
+		return events[i].at.Before(events[j].at)
+	})
+
+	var current, result int
+
+	for _, ev := range events {
+		current += ev.delta
+
+		if current > result {
+			result = current
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,94 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/planner"
+)
+
+func TestServer_MaxConcurrentObserved(t *testing.T) {
+	t.Parallel()
+
+	const concurrency = 3
+
+	s := httpmock.NewServer()
+	defer s.Close()
+
+	s.ExpectGet("/slow").
+		After(50 * time.Millisecond).
+		UnlimitedTimes()
+
+	s.ExpectGet("/fast").
+		UnlimitedTimes()
+
+	assert.Equal(t, 0, s.MaxConcurrentObserved())
+
+	var wg sync.WaitGroup
+
+	wg.Add(concurrency)
+
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+
+			doRequest(t, s.URL(), http.MethodGet, "/slow", nil, nil, time.Second)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, concurrency, s.MaxConcurrentObserved())
+	assert.Equal(t, concurrency, s.Stats()[0].MaxConcurrent)
+}
+
+func TestServer_MaxConcurrentObserved_Sequential(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+	defer s.Close()
+
+	s.ExpectGet("/").Times(3)
+
+	for i := 0; i < 3; i++ {
+		doRequest(t, s.URL(), http.MethodGet, "/", nil, nil, 0)
+	}
+
+	assert.Equal(t, 1, s.MaxConcurrentObserved())
+	assert.Equal(t, 1, s.Stats()[0].MaxConcurrent)
+}
+
+func TestServer_MaxConcurrentObserved_PerSession(t *testing.T) {
+	t.Parallel()
+
+	const concurrency = 3
+
+	s := httpmock.NewServer().
+		WithPlanner(planner.PerSession(planner.SessionByRemoteAddr(), planner.FirstMatch))
+	defer s.Close()
+
+	s.ExpectGet("/slow").
+		After(50 * time.Millisecond).
+		UnlimitedTimes()
+
+	var wg sync.WaitGroup
+
+	wg.Add(concurrency)
+
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+
+			doRequest(t, s.URL(), http.MethodGet, "/slow", nil, nil, time.Second)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, concurrency, s.Stats()[0].MaxConcurrent)
+}
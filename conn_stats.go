@@ -0,0 +1,66 @@
+package httpmock
+
+import (
+	"net"
+	"net/http"
+	"sync"
+)
+
+// ConnStats contains the statistics of the connections to the server.
+type ConnStats struct {
+	// New is the number of the connections opened by the clients.
+	New int
+	// Reused is the number of the requests sent on a connection that was already used by a previous request.
+	Reused int
+	// Closed is the number of the connections that were closed.
+	Closed int
+}
+
+// connTracker counts the connections of the server with http.Server.ConnState.
+type connTracker struct {
+	mu    sync.Mutex
+	stats ConnStats
+	// used contains the open connections that were used by a request.
+	used map[net.Conn]bool
+}
+
+func (c *connTracker) track(conn net.Conn, state http.ConnState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch state {
+	case http.StateNew:
+		c.stats.New++
+
+	case http.StateActive:
+		if c.used[conn] {
+			c.stats.Reused++
+		}
+
+		if c.used == nil {
+			c.used = make(map[net.Conn]bool)
+		}
+
+		c.used[conn] = true
+
+	case http.StateClosed, http.StateHijacked:
+		c.stats.Closed++
+
+		delete(c.used, conn)
+
+	case http.StateIdle:
+	}
+}
+
+// ConnStats returns the statistics of the connections to the server, to assert that the client keeps the connections
+// alive instead of reconnecting for every request. Only the HTTP/1.x connections are counted by request.
+//
+//	// Your requests.
+//
+//	assert.Equal(t, 1, srv.ConnStats().New)
+func (s *Server) ConnStats() ConnStats {
+	s.conns.mu.Lock()
+	defer s.conns.mu.Unlock()
+
+	return s.conns.stats
+}
@@ -0,0 +1,62 @@
+package httpmock_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestServer_ConnStats(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario          string
+		disableKeepAlives bool
+		expected          httpmock.ConnStats
+	}{
+		{
+			scenario: "keep alive",
+			expected: httpmock.ConnStats{New: 1, Reused: 2, Closed: 1},
+		},
+		{
+			scenario:          "reconnect",
+			disableKeepAlives: true,
+			expected:          httpmock.ConnStats{New: 3, Reused: 0, Closed: 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			s := httpmock.NewServer()
+
+			s.ExpectGet("/").Times(3)
+
+			transport := &http.Transport{DisableKeepAlives: tc.disableKeepAlives}
+			client := &http.Client{Transport: transport}
+
+			for i := 0; i < 3; i++ {
+				resp, err := client.Get(s.URL() + "/")
+				require.NoError(t, err)
+
+				_, err = io.Copy(io.Discard, resp.Body)
+				require.NoError(t, err)
+				require.NoError(t, resp.Body.Close())
+			}
+
+			transport.CloseIdleConnections()
+
+			// Closing the server waits for the connections to be closed.
+			s.Close()
+
+			assert.Equal(t, tc.expected, s.ConnStats())
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package httpmock
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ExpectWithin expects the request to arrive within the duration since the server was started, or since the last call
+// of the expectation registered before it, to catch the clients that stall before calling an endpoint. The late or
+// missing requests are reported by Server.ExpectationsWereMet.
+//
+//	Server.Expect(http.MethodPost, "/login")
+//
+//	Server.Expect(http.MethodGet, "/profile").
+//		ExpectWithin(time.Second)
+func (e *requestExpectation) ExpectWithin(d time.Duration) Expectation {
+	e.lock()
+	defer e.unlock()
+
+	e.within = d
+
+	return e
+}
+
+// deadline returns the duration that the request is expected to arrive within and the time of the first call.
+func (e *requestExpectation) deadline() (time.Duration, time.Time) {
+	e.lock()
+	defer e.unlock()
+
+	return e.within, e.firstCalledAt
+}
+
+// lastCalledBefore returns the time of the last call of the expectation before the time.
+func (e *requestExpectation) lastCalledBefore(t time.Time) (time.Time, bool) {
+	e.lock()
+	defer e.unlock()
+
+	for i := len(e.calledAt) - 1; i >= 0; i-- {
+		if !e.calledAt[i].After(t) {
+			return e.calledAt[i], true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// markStarted records when the server was started.
+func (s *Server) markStarted() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.startedAt = time.Now()
+}
+
+// checkDeadlines returns an error that lists the expectations that were not received in time, see
+// Expectation.ExpectWithin. The caller must hold the lock.
+func (s *Server) checkDeadlines() error {
+	var (
+		sb    strings.Builder
+		count int
+	)
+
+	now := time.Now()
+
+	for i, e := range s.expectations {
+		within, receivedAt := e.deadline()
+		if within <= 0 {
+			continue
+		}
+
+		arrival := receivedAt
+		if arrival.IsZero() {
+			arrival = now
+		}
+
+		since := s.startedAt
+
+		if i > 0 {
+			if last, ok := s.expectations[i-1].lastCalledBefore(arrival); ok {
+				since = last
+			}
+		}
+
+		elapsed := arrival.Sub(since)
+		if elapsed <= within {
+			continue
+		}
+
+		status := "received"
+		if receivedAt.IsZero() {
+			status = "not received"
+		}
+
+		_, _ = fmt.Fprintf(&sb, "- %s %s expected within %s, %s after %s\n", //nolint: errcheck
+			e.Method(), e.URIMatcher().Expected(), within, status, elapsed,
+		)
+
+		count++
+	}
+
+	if count == 0 {
+		return nil
+	}
+
+	// nolint:goerr113
+	return errors.New("there are expectations that were not received in time:\n" + sb.String())
+}
@@ -0,0 +1,97 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestExpectation_ExpectWithin(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		mock          func(s *httpmock.Server)
+		do            func(t *testing.T, s *httpmock.Server)
+		expectedError string
+	}{
+		{
+			scenario: "in time",
+			mock: func(s *httpmock.Server) {
+				s.ExpectGet("/users").
+					ExpectWithin(time.Second)
+			},
+			do: func(t *testing.T, s *httpmock.Server) {
+				t.Helper()
+
+				doRequest(t, s.URL(), http.MethodGet, "/users", nil, nil, 0)
+			},
+		},
+		{
+			scenario: "late",
+			mock: func(s *httpmock.Server) {
+				s.ExpectGet("/users").
+					ExpectWithin(10 * time.Millisecond)
+			},
+			do: func(t *testing.T, s *httpmock.Server) {
+				t.Helper()
+
+				time.Sleep(30 * time.Millisecond)
+				doRequest(t, s.URL(), http.MethodGet, "/users", nil, nil, 0)
+			},
+			expectedError: `^there are expectations that were not received in time:\n- GET /users expected within 10ms, received after \d+(\.\d+)?ms\n$`,
+		},
+		{
+			scenario: "not received",
+			mock: func(s *httpmock.Server) {
+				s.ExpectGet("/users").
+					ExpectWithin(10 * time.Millisecond)
+			},
+			do: func(*testing.T, *httpmock.Server) {
+				time.Sleep(30 * time.Millisecond)
+			},
+			expectedError: `^there are expectations that were not received in time:\n- GET /users expected within 10ms, not received after \d+(\.\d+)?ms\n$`,
+		},
+		{
+			scenario: "since the previous expectation",
+			mock: func(s *httpmock.Server) {
+				s.ExpectPost("/login")
+
+				s.ExpectGet("/profile").
+					ExpectWithin(100 * time.Millisecond)
+			},
+			do: func(t *testing.T, s *httpmock.Server) {
+				t.Helper()
+
+				time.Sleep(150 * time.Millisecond)
+				doRequest(t, s.URL(), http.MethodPost, "/login", nil, nil, 0)
+				doRequest(t, s.URL(), http.MethodGet, "/profile", nil, nil, 0)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			s := httpmock.MockServer(tc.mock)
+			defer s.Close()
+
+			tc.do(t, s)
+
+			err := s.ExpectationsWereMet()
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Regexp(t, tc.expectedError, err.Error())
+			}
+		})
+	}
+}
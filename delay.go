@@ -0,0 +1,77 @@
+package httpmock
+
+import (
+	"net/http"
+	"time"
+
+	"go.nhat.io/wait"
+)
+
+// delayer is implemented by the handlers that can tell whether they have their own delay.
+type delayer interface {
+	hasDelay() bool
+}
+
+// WithDefaultDelay delays the responses of all the expectations that do not have their own delay, for example, set by
+// Expectation.After or Expectation.WaitUntil, so the tests can run against a realistic upstream latency. See also
+// WithDefaultJitter.
+//
+//	Server.WithDefaultDelay(50 * time.Millisecond)
+func (s *Server) WithDefaultDelay(d time.Duration) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.defaultDelay = d
+
+	return s
+}
+
+// WithDefaultJitter adds a random duration, up to the jitter, to the default delay of every response.
+//
+//	Server.WithDefaultDelay(50 * time.Millisecond).
+//		WithDefaultJitter(20 * time.Millisecond)
+func (s *Server) WithDefaultJitter(jitter time.Duration) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.defaultJitter = jitter
+
+	return s
+}
+
+// defaultDelayFor returns the default delay for the handler, 0 if the handler has its own delay. The caller must hold
+// the lock.
+func (s *Server) defaultDelayFor(h ExpectationHandler) time.Duration {
+	if s.defaultDelay <= 0 && s.defaultJitter <= 0 && s.latencyProfile == nil {
+		return 0
+	}
+
+	if d, ok := h.(delayer); ok && d.hasDelay() {
+		return 0
+	}
+
+	if s.latencyProfile != nil {
+		return s.latencyProfile.sample(s.random)
+	}
+
+	delay := s.defaultDelay
+
+	if s.defaultJitter > 0 {
+		delay += time.Duration(s.random.Int63n(int64(s.defaultJitter)))
+	}
+
+	return delay
+}
+
+// delay waits for the default delay of the handler.
+func (s *Server) delay(r *http.Request, h ExpectationHandler) error {
+	s.mu.Lock()
+	d := s.defaultDelayFor(h)
+	s.mu.Unlock()
+
+	if d <= 0 {
+		return nil
+	}
+
+	return wait.ForDuration(d).Wait(r.Context())
+}
@@ -0,0 +1,85 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestServer_WithDefaultDelay(t *testing.T) {
+	t.Parallel()
+
+	const delay = 100 * time.Millisecond
+
+	s := httpmock.NewServer().
+		WithDefaultDelay(delay).
+		WithDefaultJitter(20 * time.Millisecond)
+
+	defer s.Close()
+
+	s.ExpectGet("/default").Return("default")
+	s.ExpectGet("/own").After(0).Return("own")
+
+	code, _, body, elapsed := doRequest(t, s.URL(), http.MethodGet, "/default", nil, nil, delay)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "default", string(body))
+	assert.GreaterOrEqual(t, elapsed, delay)
+
+	code, _, body, elapsed = doRequest(t, s.URL(), http.MethodGet, "/own", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "own", string(body))
+	assert.Less(t, elapsed, delay)
+
+	assert.NoError(t, s.ExpectationsWereMet())
+}
+
+func TestServer_WithDefaultDelay_AutoHead(t *testing.T) {
+	t.Parallel()
+
+	const delay = 100 * time.Millisecond
+
+	s := httpmock.NewServer().
+		WithAutoHead().
+		WithDefaultDelay(time.Hour)
+
+	defer s.Close()
+
+	s.ExpectGet("/").After(delay).Return("hello")
+
+	code, _, _, elapsed := doRequest(t, s.URL(), http.MethodHead, "/", nil, nil, delay)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.GreaterOrEqual(t, elapsed, delay)
+	assert.Less(t, elapsed, time.Hour)
+}
+
+func TestServer_WithLatencyProfile(t *testing.T) {
+	t.Parallel()
+
+	const minDelay = 15 * time.Millisecond
+
+	s := httpmock.NewServer().
+		WithDefaultDelay(time.Hour).
+		WithLatencyProfile(30*time.Millisecond, 40*time.Millisecond, 50*time.Millisecond)
+
+	defer s.Close()
+
+	s.ExpectGet("/").Return("hello")
+
+	code, _, body, elapsed := doRequest(t, s.URL(), http.MethodGet, "/", nil, nil, minDelay)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "hello", string(body))
+	assert.GreaterOrEqual(t, elapsed, minDelay)
+	assert.Less(t, elapsed, time.Hour)
+
+	assert.Panics(t, func() {
+		s.WithLatencyProfile(time.Second, time.Millisecond, time.Second)
+	})
+}
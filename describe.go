@@ -0,0 +1,290 @@
+package httpmock
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.nhat.io/wait"
+
+	"go.nhat.io/httpmock/format"
+	"go.nhat.io/httpmock/matcher"
+)
+
+// ExpectationDescription describes an expectation, see Expectation.MarshalJSON. It is a superset of ExpectationSpec, so
+// an expectation of exact or regular expression matchers and a static response could be read back by
+// ReadExpectationSpecs and added to another server by Server.ExpectSpec.
+type ExpectationDescription struct {
+	ExpectationSpec
+
+	// ID is the identifier of the expectation in the server, 0 if it is not registered.
+	ID int `json:"id,omitempty"`
+	// Matchers describes all the matchers of the request, including the ones that could not be written in a spec.
+	Matchers RequestMatchersDescription `json:"matchers"`
+	// Scenario describes the scenario of the expectation, nil if it is not in a scenario.
+	Scenario *ScenarioDescription `json:"scenario,omitempty"`
+	// DynamicResponse indicates whether the response body is built when the request is handled, for example, by Run,
+	// so it is not described.
+	DynamicResponse bool `json:"dynamicResponse,omitempty"`
+	// Fulfilled is the number of times the expectation was matched.
+	Fulfilled uint `json:"fulfilled"`
+	// Remaining is the number of remaining calls, 0 means unlimited or no call is left.
+	Remaining uint `json:"remaining"`
+}
+
+// RequestMatchersDescription describes the matchers of the request of an expectation.
+type RequestMatchersDescription struct {
+	// URI describes the matcher of the request uri.
+	URI MatcherDescription `json:"uri"`
+	// Headers describes the matchers of the headers.
+	Headers map[string]MatcherDescription `json:"headers,omitempty"`
+	// Body describes the matcher of the body, nil if the body is not matched.
+	Body *MatcherDescription `json:"body,omitempty"`
+	// Request describes the matchers of the whole request, for example, WithScheme or WithFormField.
+	Request []string `json:"request,omitempty"`
+}
+
+// MatcherDescription describes a matcher.
+type MatcherDescription struct {
+	// Type is the type of the matcher, for example, exact, regex, or json. The custom matchers are described by their Go
+	// type.
+	Type string `json:"type"`
+	// Expected is the expectation of the matcher.
+	Expected string `json:"expected"`
+}
+
+// ScenarioDescription describes the scenario of an expectation, see Expectation.InScenario.
+type ScenarioDescription struct {
+	// Name is the name of the scenario.
+	Name string `json:"name"`
+	// State is the expected state of the scenario, empty if any state is expected.
+	State string `json:"state,omitempty"`
+	// NextState is the state that the scenario moves to when the expectation is matched, empty if it does not move.
+	NextState string `json:"nextState,omitempty"`
+}
+
+// MarshalJSON describes the expectation in JSON, see ExpectationDescription.
+//
+//	b, err := json.MarshalIndent(srv.Expectations(), "", "  ")
+func (e *requestExpectation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.describe())
+}
+
+// String describes the expectation in a human-readable format.
+//
+//	for _, e := range srv.Expectations() {
+//		t.Log(e)
+//	}
+func (e *requestExpectation) String() string {
+	e.lock()
+	defer e.unlock()
+
+	var sb strings.Builder
+
+	format.ExpectedRequestTimes(&sb,
+		e.requestMethod,
+		e.requestURIMatcher,
+		e.requestHeaderMatcher,
+		e.requestBodyMatcher,
+		int(e.fulfilledTimes),
+		int(e.repeatTimes), //nolint: gosec
+	)
+
+	for _, m := range e.requestMatchers {
+		if m.description != "" {
+			_, _ = fmt.Fprintf(&sb, "    with %s\n", m.description) //nolint: errcheck
+		}
+	}
+
+	if sc := e.describeScenario(); sc != nil {
+		_, _ = fmt.Fprintf(&sb, "    in scenario %q", sc.Name) //nolint: errcheck
+
+		if sc.State != "" {
+			_, _ = fmt.Fprintf(&sb, " when state is %q", sc.State) //nolint: errcheck
+		}
+
+		if sc.NextState != "" {
+			_, _ = fmt.Fprintf(&sb, " then set state to %q", sc.NextState) //nolint: errcheck
+		}
+
+		sb.WriteString("\n")
+	}
+
+	_, _ = fmt.Fprintf(&sb, "    returns %d\n", e.responseCode) //nolint: errcheck
+
+	for _, header := range sortedKeys(e.responseHeader) {
+		_, _ = fmt.Fprintf(&sb, "        %s: %s\n", header, e.responseHeader[header]) //nolint: errcheck
+	}
+
+	switch {
+	case e.example != nil:
+		if len(e.example) > 0 {
+			_, _ = fmt.Fprintf(&sb, "        %s\n", e.example) //nolint: errcheck
+		}
+
+	case e.handle != nil || e.httpHandler != nil:
+		sb.WriteString("        <dynamic>\n")
+	}
+
+	if d, ok := e.waiter.(wait.ForDuration); ok {
+		_, _ = fmt.Fprintf(&sb, "    after %s\n", time.Duration(d)) //nolint: errcheck
+	}
+
+	return sb.String()
+}
+
+func (e *requestExpectation) describe() ExpectationDescription {
+	e.lock()
+	defer e.unlock()
+
+	result := ExpectationDescription{
+		ExpectationSpec: ExpectationSpec{
+			Method: e.requestMethod,
+			Times:  e.times,
+			Response: ResponseSpec{
+				Code: e.responseCode,
+			},
+		},
+		ID:        e.id,
+		Scenario:  e.describeScenario(),
+		Fulfilled: e.fulfilledTimes,
+		Remaining: e.repeatTimes,
+	}
+
+	result.Matchers.URI = describeMatcher(e.requestURIMatcher)
+
+	switch result.Matchers.URI.Type {
+	case matcherTypeExact:
+		result.URI = result.Matchers.URI.Expected
+
+	case matcherTypeRegex:
+		result.URIPattern = result.Matchers.URI.Expected
+	}
+
+	if len(e.requestHeaderMatcher) > 0 {
+		result.Matchers.Headers = make(map[string]MatcherDescription, len(e.requestHeaderMatcher))
+
+		for header, m := range e.requestHeaderMatcher {
+			d := describeMatcher(m)
+
+			result.Matchers.Headers[header] = d
+
+			if d.Type == matcherTypeExact {
+				if result.Headers == nil {
+					result.Headers = make(map[string]string)
+				}
+
+				result.Headers[header] = d.Expected
+			}
+		}
+	}
+
+	if e.requestBodyMatcher != nil {
+		d := describeMatcher(e.requestBodyMatcher)
+
+		result.Matchers.Body = &d
+
+		switch {
+		case d.Type == matcherTypeExact:
+			result.Body = d.Expected
+
+		case d.Type == matcherTypeJSON && json.Valid([]byte(d.Expected)):
+			result.BodyJSON = json.RawMessage(d.Expected)
+		}
+	}
+
+	for _, m := range e.requestMatchers {
+		if m.description != "" {
+			result.Matchers.Request = append(result.Matchers.Request, m.description)
+		}
+	}
+
+	if len(e.responseHeader) > 0 {
+		result.Response.Headers = make(map[string]string, len(e.responseHeader))
+
+		for header, val := range e.responseHeader {
+			result.Response.Headers[header] = val
+		}
+	}
+
+	switch {
+	case e.example != nil:
+		if utf8.Valid(e.example) {
+			result.Response.Body = string(e.example)
+		} else {
+			result.Response.BodyBase64 = base64.StdEncoding.EncodeToString(e.example)
+		}
+
+	case e.handle != nil || e.httpHandler != nil:
+		result.DynamicResponse = true
+	}
+
+	if d, ok := e.waiter.(wait.ForDuration); ok {
+		result.Response.Delay = time.Duration(d).String()
+	}
+
+	return result
+}
+
+// describeScenario describes the scenario of the expectation. The caller must hold the lock.
+func (e *requestExpectation) describeScenario() *ScenarioDescription {
+	if e.scenario == nil {
+		return nil
+	}
+
+	return &ScenarioDescription{
+		Name:      e.scenario.Name(),
+		State:     e.scenarioState,
+		NextState: e.scenarioNextState,
+	}
+}
+
+const (
+	matcherTypeExact = "exact"
+	matcherTypeRegex = "regex"
+	matcherTypeJSON  = "json"
+)
+
+// describeMatcher describes the matcher by its type and its expectation.
+func describeMatcher(m matcher.Matcher) MatcherDescription {
+	switch v := m.(type) {
+	case *matcher.BodyMatcher:
+		return describeMatcher(v.Matcher())
+
+	case matcher.Callback:
+		return describeMatcher(v.Matcher())
+	}
+
+	var typ string
+
+	switch m.(type) {
+	case matcher.ExactMatcher:
+		typ = matcherTypeExact
+
+	case matcher.RegexMatcher:
+		typ = matcherTypeRegex
+
+	case matcher.JSONMatcher:
+		typ = matcherTypeJSON
+
+	case matcher.FnMatcher:
+		typ = "fn"
+
+	case matcher.HashMatcher, *matcher.HashMatcher:
+		typ = "hash"
+
+	case matcher.XMLMatcher, *matcher.XMLMatcher:
+		typ = "xml"
+
+	case matcher.XPathMatcher, *matcher.XPathMatcher:
+		typ = "xpath"
+
+	default:
+		typ = fmt.Sprintf("%T", m)
+	}
+
+	return MatcherDescription{Type: typ, Expected: m.Expected()}
+}
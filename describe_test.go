@@ -0,0 +1,164 @@
+package httpmock_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestExpectation_String(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+
+	defer s.Close()
+
+	s.ExpectPost("/users").
+		WithHeader("Authorization", httpmock.RegexPattern(`^Bearer `)).
+		WithBody(httpmock.JSON(`{"name":"John Doe"}`)).
+		WithScheme("http").
+		WithFormField("page", 2).
+		InScenario("signup").
+		WhenScenarioStateIs(httpmock.ScenarioStarted).
+		WillSetStateTo("created").
+		ReturnCode(httpmock.StatusCreated).
+		ReturnHeader("Content-Type", "application/json").
+		Return(`{"id":42}`).
+		After(150 * time.Millisecond).
+		Twice()
+
+	s.ExpectGet("/users/42").
+		Run(func(*http.Request) ([]byte, error) {
+			return []byte(`{"id":42}`), nil
+		})
+
+	expectations := s.Expectations()
+
+	require.Len(t, expectations, 2)
+
+	expected := `POST /users (called: 0 time(s), remaining: 2 time(s))
+    with header:
+        Authorization: matcher.RegexMatcher("^Bearer ")
+    with body using matcher.JSONMatcher
+        {"name":"John Doe"}
+    with scheme "http"
+    with form field "page" with int value 2
+    in scenario "signup" when state is "Started" then set state to "created"
+    returns 201
+        Content-Type: application/json
+        {"id":42}
+    after 150ms
+`
+
+	assert.Equal(t, expected, expectations[0].String())
+
+	expected = `GET /users/42
+    returns 200
+        <dynamic>
+`
+
+	assert.Equal(t, expected, expectations[1].String())
+}
+
+func TestExpectation_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+
+	defer s.Close()
+
+	s.ExpectPost(httpmock.RegexPattern(`^/users`)).
+		WithHeader("Authorization", "Bearer token").
+		WithHeader("X-Request-ID", httpmock.IsNotEmpty()).
+		WithBody(httpmock.JSON(`{"name":"John Doe"}`)).
+		WithHost("api.example.com").
+		InScenario("signup").
+		WillSetStateTo("created").
+		ReturnCode(httpmock.StatusCreated).
+		ReturnHeader("Content-Type", "application/json").
+		Return(`{"id":42}`).
+		After(time.Second)
+
+	actual, err := json.Marshal(s.Expectations()[0])
+	require.NoError(t, err)
+
+	expected := `{
+		"id": 1,
+		"method": "POST",
+		"uriPattern": "^/users",
+		"headers": {"Authorization": "Bearer token"},
+		"bodyJSON": {"name": "John Doe"},
+		"times": 1,
+		"response": {
+			"code": 201,
+			"headers": {"Content-Type": "application/json"},
+			"body": "{\"id\":42}",
+			"delay": "1s"
+		},
+		"matchers": {
+			"uri": {"type": "regex", "expected": "^/users"},
+			"headers": {
+				"Authorization": {"type": "exact", "expected": "Bearer token"},
+				"X-Request-Id": {"type": "matcher.NotEmptyMatcher", "expected": "is not empty"}
+			},
+			"body": {"type": "json", "expected": "{\"name\":\"John Doe\"}"},
+			"request": ["host \"api.example.com\""]
+		},
+		"scenario": {"name": "signup", "nextState": "created"},
+		"fulfilled": 0,
+		"remaining": 1
+	}`
+
+	assert.JSONEq(t, expected, string(actual))
+}
+
+func TestExpectation_MarshalJSON_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	src := httpmock.NewServer()
+
+	defer src.Close()
+
+	src.ExpectPost("/users").
+		WithHeader("Authorization", "Bearer token").
+		WithBody(`{"name":"John Doe"}`).
+		ReturnCode(httpmock.StatusCreated).
+		ReturnHeader("Content-Type", "application/json").
+		Return(`{"id":42}`)
+
+	src.ExpectGet("/avatar").
+		Return([]byte{0xff, 0xd8, 0xff}).
+		UnlimitedTimes()
+
+	b, err := json.Marshal(src.Expectations())
+	require.NoError(t, err)
+
+	specs, err := httpmock.ReadExpectationSpecs(bytes.NewReader(b))
+	require.NoError(t, err)
+
+	s := httpmock.New(func(s *httpmock.Server) {
+		for _, spec := range specs {
+			_, err := s.ExpectSpec(spec)
+			require.NoError(t, err)
+		}
+	})(t)
+
+	code, headers, body, _ := doRequest(t, s.URL(), http.MethodPost, "/users", Header{"Authorization": "Bearer token"}, []byte(`{"name":"John Doe"}`), 0)
+
+	assert.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, "application/json", headers["Content-Type"])
+	assert.Equal(t, `{"id":42}`, string(body))
+
+	for i := 0; i < 2; i++ {
+		_, _, body, _ = doRequest(t, s.URL(), http.MethodGet, "/avatar", nil, nil, 0)
+
+		assert.Equal(t, []byte{0xff, 0xd8, 0xff}, body)
+	}
+}
@@ -0,0 +1,143 @@
+package httpmock
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.nhat.io/wait"
+)
+
+// DialerOption configures the faults of DialerWithFaults.
+type DialerOption func(d *FaultDialer)
+
+// FaultDialer is a net.Dialer that injects the faults of the name resolution into the connections of a client, see
+// DialerWithFaults.
+type FaultDialer struct {
+	dialer net.Dialer
+
+	mu          sync.Mutex
+	dnsDelay    time.Duration
+	dnsFailures int
+	records     map[string]string
+	lookups     int
+}
+
+// WithDNSDelay delays every dial by the duration, like a slow name resolution.
+func WithDNSDelay(d time.Duration) DialerOption {
+	return func(fd *FaultDialer) {
+		fd.dnsDelay = d
+	}
+}
+
+// WithDNSFailures fails the first n dials with a *net.DNSError that the host is not found, then the dials succeed, to
+// test the retries of the clients.
+func WithDNSFailures(n int) DialerOption {
+	return func(fd *FaultDialer) {
+		fd.dnsFailures = n
+	}
+}
+
+// WithDNSRecord resolves the host to the address, for example, the address of the server, so the client could call it
+// with a real host name. If the address has no port, the port of the request is kept.
+//
+//	u, _ := url.Parse(srv.URL())
+//
+//	httpmock.DialerWithFaults(
+//		httpmock.WithDNSRecord("api.example.com", u.Host),
+//	)
+func WithDNSRecord(host, address string) DialerOption {
+	return func(fd *FaultDialer) {
+		if fd.records == nil {
+			fd.records = make(map[string]string)
+		}
+
+		fd.records[host] = address
+	}
+}
+
+// DialerWithFaults creates a dialer that injects the delays and the failures of the name resolution when connecting
+// to the server, to test the resilience of the clients at the network level, alongside the faults of the server. Use
+// it with WithClientDialer, or as the DialContext of a http.Transport.
+//
+//	d := httpmock.DialerWithFaults(
+//		httpmock.WithDNSDelay(100*time.Millisecond),
+//		httpmock.WithDNSFailures(1),
+//	)
+//
+//	client := &http.Client{Transport: &http.Transport{DialContext: d.DialContext}}
+func DialerWithFaults(opts ...DialerOption) *FaultDialer {
+	d := &FaultDialer{}
+
+	for _, o := range opts {
+		o(d)
+	}
+
+	return d
+}
+
+// DialContext resolves the address with the faults, then connects to it.
+func (d *FaultDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	d.mu.Lock()
+	d.lookups++
+	delay := d.dnsDelay
+	fail := d.dnsFailures > 0
+
+	if fail {
+		d.dnsFailures--
+	}
+	d.mu.Unlock()
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := wait.ForDuration(delay).Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	if fail {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	d.mu.Lock()
+	resolved, ok := d.records[host]
+	d.mu.Unlock()
+
+	if ok {
+		address = resolved
+
+		if _, _, err := net.SplitHostPort(resolved); err != nil {
+			address = net.JoinHostPort(resolved, port)
+		}
+	}
+
+	return d.dialer.DialContext(ctx, network, address)
+}
+
+// Lookups returns the number of the name resolutions, including the failed ones.
+func (d *FaultDialer) Lookups() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.lookups
+}
+
+// WithClientDialer sets the dialer of the client, for example, a dialer with faults, see DialerWithFaults. The other
+// settings of the transport are kept, if any.
+func WithClientDialer(d *FaultDialer) ClientOption {
+	return func(c *http.Client) {
+		t, ok := c.Transport.(*http.Transport)
+		if !ok {
+			t = &http.Transport{}
+		} else {
+			t = t.Clone()
+		}
+
+		t.DialContext = d.DialContext
+		c.Transport = t
+	}
+}
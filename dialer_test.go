@@ -0,0 +1,60 @@
+package httpmock_test
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestDialerWithFaults(t *testing.T) {
+	t.Parallel()
+
+	delay := 50 * time.Millisecond
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet("/users").
+			Return(`[]`)
+	})(t)
+
+	u, err := url.Parse(srv.URL())
+	require.NoError(t, err)
+
+	_, port, err := net.SplitHostPort(u.Host)
+	require.NoError(t, err)
+
+	d := httpmock.DialerWithFaults(
+		httpmock.WithDNSDelay(delay),
+		httpmock.WithDNSFailures(1),
+		httpmock.WithDNSRecord("api.example.com", "127.0.0.1"),
+	)
+
+	client := &http.Client{Transport: &http.Transport{DialContext: d.DialContext}}
+	uri := "http://" + net.JoinHostPort("api.example.com", port) + "/users"
+
+	// The first lookup fails.
+	_, err = client.Get(uri) // nolint: noctx,bodyclose
+
+	var dnsErr *net.DNSError
+
+	require.True(t, errors.As(err, &dnsErr))
+	assert.True(t, dnsErr.IsNotFound)
+	assert.Equal(t, "api.example.com", dnsErr.Name)
+
+	// The retry succeeds after the delay.
+	resp := httpmock.DoRequestWithClientOptions(t, http.MethodGet, uri, nil, nil,
+		httpmock.WithClientDialer(d),
+	)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `[]`, string(resp.Body))
+	assert.GreaterOrEqual(t, resp.Elapsed, delay)
+	assert.Equal(t, 2, d.Lookups())
+}
@@ -0,0 +1,162 @@
+package httpmock
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"go.nhat.io/httpmock/value"
+)
+
+var (
+	_ http.ResponseWriter = (*dumpResponseWriter)(nil)
+	_ http.Flusher        = (*dumpResponseWriter)(nil)
+	_ http.Hijacker       = (*dumpResponseWriter)(nil)
+)
+
+// dumpResponseWriter records the response while writing it to the client.
+type dumpResponseWriter struct {
+	http.ResponseWriter
+
+	seq  int
+	code int
+	body bytes.Buffer
+}
+
+func (w *dumpResponseWriter) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *dumpResponseWriter) Write(b []byte) (int, error) {
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+
+	w.body.Write(b)
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *dumpResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *dumpResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking") // nolint: goerr113
+	}
+
+	return h.Hijack()
+}
+
+// WithRequestDump writes every request received by the server and its response to numbered files in the directory,
+// for example, 0001.request and 0001.response, so a failing test can be debugged without adding logs.
+//
+//	Server.WithRequestDump(t.TempDir())
+func (s *Server) WithRequestDump(dir string) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requestDumpDir = dir
+
+	return s
+}
+
+// newDumpResponseWriter wraps the response writer to record the response, nil if the requests are neither dumped nor
+// logged, see LogOnFailure.
+func (s *Server) newDumpResponseWriter(w http.ResponseWriter) *dumpResponseWriter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.requestDumpDir == "" && len(s.failureLogs) == 0 {
+		return nil
+	}
+
+	s.requestDumpSeq++
+
+	return &dumpResponseWriter{ResponseWriter: w, seq: s.requestDumpSeq}
+}
+
+// dump writes the request and the response to the dump directory and to the failure logs.
+func (s *Server) dump(r *http.Request, w *dumpResponseWriter) {
+	req, resp := formatDump(r, w)
+
+	s.logInteraction(w.seq, req, resp)
+
+	s.mu.Lock()
+	dir, t := s.requestDumpDir, s.test
+	s.mu.Unlock()
+
+	if dir == "" {
+		return
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil { // nolint: gosec
+		t.Errorf("could not dump request: %s", err.Error())
+
+		return
+	}
+
+	for ext, data := range map[string][]byte{"request": req, "response": resp} {
+		path := filepath.Join(dir, fmt.Sprintf("%04d.%s", w.seq, ext))
+
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Errorf("could not dump request: %s", err.Error())
+		}
+	}
+}
+
+// formatDump formats the request and the recorded response in the HTTP/1.x wire format.
+func formatDump(r *http.Request, w *dumpResponseWriter) ([]byte, []byte) {
+	var req bytes.Buffer
+
+	_, _ = fmt.Fprintf(&req, "%s %s %s\r\n", r.Method, r.RequestURI, r.Proto) //nolint: errcheck
+	writeDumpHeader(&req, r.Header)
+
+	if body, err := value.PeekBody(r); err == nil {
+		req.Write(body)
+	}
+
+	var resp bytes.Buffer
+
+	code := w.code
+	if code == 0 {
+		code = http.StatusOK
+	}
+
+	_, _ = fmt.Fprintf(&resp, "%s %d %s\r\n", r.Proto, code, http.StatusText(code)) //nolint: errcheck
+	writeDumpHeader(&resp, w.Header())
+	resp.Write(w.body.Bytes())
+
+	return req.Bytes(), resp.Bytes()
+}
+
+func writeDumpHeader(buf *bytes.Buffer, header http.Header) {
+	keys := make([]string, 0, len(header))
+
+	for k := range header {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		for _, v := range header[k] {
+			_, _ = fmt.Fprintf(buf, "%s: %s\r\n", k, v) //nolint: errcheck
+		}
+	}
+
+	buf.WriteString("\r\n")
+}
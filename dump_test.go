@@ -0,0 +1,62 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestServer_WithRequestDump(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "dump")
+
+	s := httpmock.NewServer().
+		WithTest(T()).
+		WithRequestDump(dir)
+
+	defer s.Close()
+
+	s.ExpectPost("/users").
+		ReturnCode(httpmock.StatusCreated).
+		ReturnHeader("Content-Type", "application/json").
+		Return(`{"id":42}`)
+
+	doRequest(t, s.URL(), http.MethodPost, "/users", Header{"Authorization": "Bearer token"}, []byte(`{"name":"john"}`), 0)
+	doRequest(t, s.URL(), http.MethodGet, "/unknown", nil, nil, 0)
+
+	testCases := []struct {
+		file     string
+		expected string
+	}{
+		{
+			file:     "0001.request",
+			expected: "POST /users HTTP/1.1\r\nAccept-Encoding: gzip\r\nAuthorization: Bearer token\r\nContent-Length: 15\r\nUser-Agent: Go-http-client/1.1\r\n\r\n{\"name\":\"john\"}",
+		},
+		{
+			file:     "0001.response",
+			expected: "HTTP/1.1 201 Created\r\nContent-Type: application/json\r\n\r\n{\"id\":42}",
+		},
+		{
+			file:     "0002.request",
+			expected: "GET /unknown HTTP/1.1\r\nAccept-Encoding: gzip\r\nUser-Agent: Go-http-client/1.1\r\n\r\n",
+		},
+		{
+			file:     "0002.response",
+			expected: "HTTP/1.1 500 Internal Server Error\r\n\r\nunexpected request received: GET /unknown",
+		},
+	}
+
+	for _, tc := range testCases {
+		data, err := os.ReadFile(filepath.Join(dir, tc.file))
+		require.NoError(t, err)
+
+		assert.Equal(t, tc.expected, string(data), tc.file)
+	}
+}
@@ -0,0 +1,79 @@
+package httpmock
+
+import (
+	"strings"
+
+	"go.nhat.io/httpmock/format"
+)
+
+// DuplicateCheck is how the server reports the duplicate expectations.
+type DuplicateCheck int
+
+const (
+	// DuplicateWarn logs a warning if the test supports logging, such as *testing.T.
+	DuplicateWarn DuplicateCheck = iota + 1
+	// DuplicateFail fails the test.
+	DuplicateFail
+)
+
+// logger is implemented by the tests that support logging, such as *testing.T.
+type logger interface {
+	Logf(format string, args ...any)
+}
+
+// WithDuplicateCheck reports the expectations that have the same method, uri, header and body matchers as an
+// expectation registered before. They usually come from a copy-paste mistake and silently consume the calls, in the
+// sequence planner for example. The matchers are compared by their descriptions, and the check runs when the server
+// receives a request or when ExpectationsWereMet is called, so the expectations are fully configured.
+//
+//	Server.WithDuplicateCheck(httpmock.DuplicateFail)
+func (s *Server) WithDuplicateCheck(check DuplicateCheck) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.duplicateCheck = check
+
+	return s
+}
+
+// checkDuplicates reports the duplicate expectations that were registered since the last check. The caller must hold
+// the lock.
+func (s *Server) checkDuplicates() {
+	if s.duplicateCheck == 0 || s.duplicateCheckedID >= s.lastID {
+		return
+	}
+
+	seen := make(map[string]struct{}, len(s.expectations))
+
+	for _, e := range s.expectations {
+		signature := expectationSignature(e)
+
+		if _, ok := seen[signature]; ok && e.id > s.duplicateCheckedID {
+			s.reportDuplicate("duplicate expectation, it has the same method, uri, header and body as an expectation registered before:\n%s", signature)
+		}
+
+		seen[signature] = struct{}{}
+	}
+
+	s.duplicateCheckedID = s.lastID
+}
+
+func (s *Server) reportDuplicate(msg string, args ...any) {
+	if s.duplicateCheck == DuplicateFail {
+		s.test.Errorf(msg, args...)
+
+		return
+	}
+
+	if l, ok := s.test.(logger); ok {
+		l.Logf(msg, args...)
+	}
+}
+
+func expectationSignature(e *requestExpectation) string {
+	var sb strings.Builder
+
+	format.ExpectedRequest(&sb, e.Method(), e.URIMatcher(), e.HeaderMatcher(), e.BodyMatcher())
+
+	return sb.String()
+}
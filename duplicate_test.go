@@ -0,0 +1,96 @@
+package httpmock_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+)
+
+type loggingT struct {
+	*TestingT
+
+	logs []string
+}
+
+func (t *loggingT) Logf(format string, args ...any) {
+	t.logs = append(t.logs, fmt.Sprintf(format, args...))
+}
+
+func TestServer_WithDuplicateCheck_Fail(t *testing.T) {
+	t.Parallel()
+
+	testingT := T()
+
+	s := httpmock.NewServer().
+		WithTest(testingT).
+		WithDuplicateCheck(httpmock.DuplicateFail)
+
+	defer s.Close()
+
+	s.ExpectPost("/users").WithHeader("Authorization", "Bearer token").WithBody(`{"name":"john"}`).Return("1")
+	s.ExpectPost("/users").WithHeader("Authorization", "Bearer token").WithBody(`{"name":"jane"}`).Return("2")
+	s.ExpectPost("/users").WithHeader("Authorization", "Bearer token").WithBody(`{"name":"john"}`).Return("3")
+
+	code, _, _, _ := doRequest(t, s.URL(), http.MethodPost, "/users", Header{"Authorization": "Bearer token"}, []byte(`{"name":"john"}`), 0)
+
+	expected := `duplicate expectation, it has the same method, uri, header and body as an expectation registered before:
+POST /users
+    with header:
+        Authorization: Bearer token
+    with body
+        {"name":"john"}
+`
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, expected, testingT.String())
+
+	// The duplicates are reported only once.
+	_ = s.ExpectationsWereMet() //nolint: errcheck
+
+	assert.Equal(t, expected, testingT.String())
+}
+
+func TestServer_WithDuplicateCheck_Warn(t *testing.T) {
+	t.Parallel()
+
+	testingT := &loggingT{TestingT: T()}
+
+	s := httpmock.NewServer().
+		WithTest(testingT).
+		WithDuplicateCheck(httpmock.DuplicateWarn)
+
+	defer s.Close()
+
+	s.ExpectGet("/users").Return("1")
+	s.ExpectGet("/users").Return("2")
+	s.ExpectGet("/users/42").Return("42")
+
+	err := s.ExpectationsWereMet()
+
+	assert.Error(t, err)
+	assert.Empty(t, testingT.String())
+	assert.Equal(t, []string{"duplicate expectation, it has the same method, uri, header and body as an expectation registered before:\nGET /users\n"}, testingT.logs)
+}
+
+func TestServer_WithoutDuplicateCheck(t *testing.T) {
+	t.Parallel()
+
+	testingT := &loggingT{TestingT: T()}
+
+	s := httpmock.NewServer().WithTest(testingT)
+
+	defer s.Close()
+
+	s.ExpectGet("/users").Return("1")
+	s.ExpectGet("/users").Return("2")
+
+	code, _, _, _ := doRequest(t, s.URL(), http.MethodGet, "/users", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, testingT.String())
+	assert.Empty(t, testingT.logs)
+}
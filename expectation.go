@@ -1,14 +1,25 @@
 package httpmock
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math/rand"
+	"net"
 	"net/http"
-	"net/textproto"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"testing/iotest"
 	"time"
 
 	"go.nhat.io/wait"
@@ -23,7 +34,8 @@ import (
 //
 // nolint: interfacebloat
 type Expectation interface {
-	// WithHeader sets an expected header of the given request.
+	// WithHeader sets an expected header of the given request. The header name is case-insensitive, unless
+	// WithStrictHeaderCase is used.
 	//
 	//	Server.Expect(httpmock.MethodGet, "/path").
 	//		WithHeader("foo", "bar")
@@ -33,7 +45,60 @@ type Expectation interface {
 	//	Server.Expect(httpmock.MethodGet, "/path").
 	//		WithHeaders(map[string]any{"foo": "bar"})
 	WithHeaders(headers map[string]any) Expectation
-	// WithBody sets the expected body of the given request. It could be []byte, string, fmt.Stringer, or a Matcher.
+	// WithHeaderOrder sets the expected order of the headers of the given request. The headers must be sent in the same
+	// relative order, the other headers could be anywhere. The header names are case-insensitive.
+	//
+	//	Server.Expect(httpmock.MethodGet, "/path").
+	//		WithHeaderOrder("Host", "User-Agent", "Accept")
+	WithHeaderOrder(headers ...string) Expectation
+	// WithStrictHeaderCase requires the headers set by WithHeader to be sent with the exact same casing, for the clients
+	// that must send a specific casing to a picky server.
+	//
+	//	Server.Expect(httpmock.MethodGet, "/path").
+	//		WithHeader("x-api-key", "secret").
+	//		WithStrictHeaderCase()
+	WithStrictHeaderCase() Expectation
+	// WithoutQuery expects the query parameter not to be sent, for example, a deprecated or forbidden one.
+	//
+	//	Server.Expect(httpmock.MethodGet, httpmock.RegexPattern(`^/users`)).
+	//		WithoutQuery("api_key")
+	WithoutQuery(key string) Expectation
+	// WithProto expects the request to be sent with the protocol version, for example, HTTP/2.0 when the server is
+	// started with WithHTTP2 and StartTLS.
+	//
+	//	Server.Expect(httpmock.MethodGet, "/path").
+	//		WithProto("HTTP/2.0")
+	WithProto(proto string) Expectation
+	// WithRemoteAddr expects the request to be sent from the address, for example, to tell apart the simulated clients
+	// that are bound to different local addresses. The address is the IP of the client, without the port, and it could
+	// be a string or a Matcher.
+	//
+	//	Server.Expect(httpmock.MethodGet, "/path").
+	//		WithRemoteAddr("127.0.0.2")
+	WithRemoteAddr(addr any) Expectation
+	// WithScheme expects the target of the request to have the scheme, for example, http or https. The scheme of an
+	// absolute-form request, for example, a request to a forward proxy, is taken from its request uri, otherwise, it
+	// is https if the request is sent over TLS.
+	//
+	//	Server.Expect(httpmock.MethodGet, "/path").
+	//		WithScheme("https")
+	WithScheme(scheme string) Expectation
+	// WithHost expects the target of the request to have the host, without the default port of the scheme. The host
+	// of an absolute-form request, for example, a request to a forward proxy, is taken from its request uri,
+	// otherwise, it is the Host header. The host could be a string or a Matcher.
+	//
+	//	Server.Expect(httpmock.MethodGet, "/path").
+	//		WithHost("api.example.com")
+	WithHost(host any) Expectation
+	// WithPathParam constrains a parameter of the request uri template, for example, the id of /users/{id}. The value
+	// could be a string or a Matcher. It panics if the request uri is not a template, see PathTemplate, or does not have
+	// the parameter. The values of the parameters are accessible in the handlers with PathParams.
+	//
+	//	Server.Expect(httpmock.MethodGet, httpmock.PathTemplate("/users/{id}")).
+	//		WithPathParam("id", httpmock.RegexPattern(`^\d+$`))
+	WithPathParam(name string, value any) Expectation
+	// WithBody sets the expected body of the given request. It could be []byte, string, fmt.Stringer, a Matcher, or a
+	// RequestMatcherFunc, see MatchRequest.
 	//
 	//	Server.Expect(httpmock.MethodGet, "/path").
 	//		WithBody("hello world!")
@@ -49,6 +114,20 @@ type Expectation interface {
 	//		WithBodyJSON(map[string]string{"foo": "bar"})
 	//
 	WithBodyJSON(v any) Expectation
+	// WithBodyBase64 decodes the standard base64 string and uses it as the expected body of the given request, so a
+	// binary payload can be embedded in the test. It panics if the string is not valid.
+	//
+	//	Server.Expect(httpmock.MethodPost, "/upload").
+	//		WithBodyBase64("iVBORw0KGgo=")
+	WithBodyBase64(b64 string) Expectation
+	// WithFormField expects the url-encoded body of the request to have the form field. A number or a boolean is
+	// compared with the parsed value of the field, so 2 matches "2" and "02", and true matches "true" and "1".
+	// Otherwise, the value could be a string, a []byte, or a Matcher.
+	//
+	//	Server.Expect(httpmock.MethodPost, "/search").
+	//		WithFormField("page", 2).
+	//		WithFormField("exact", true)
+	WithFormField(field string, expected any) Expectation
 
 	// ReturnCode sets the response code.
 	//
@@ -65,6 +144,23 @@ type Expectation interface {
 	//	Server.Expect(httpmock.MethodGet, "/path").
 	//		ReturnHeaders(httpmock.Header{"foo": "bar"})
 	ReturnHeaders(headers Header) Expectation
+	// WithoutDefaultHeaders does not write the default response headers of the server in the response of the
+	// expectation, for example, to test a misconfigured endpoint.
+	//
+	//	Server.Expect(httpmock.MethodGet, "/legacy").
+	//		WithoutDefaultHeaders()
+	WithoutDefaultHeaders() Expectation
+	// ReturnCacheable sets the Cache-Control, Expires and Age headers to let the clients cache the response for the
+	// duration.
+	//
+	//	Server.Expect(httpmock.MethodGet, "/path").
+	//		ReturnCacheable(time.Hour)
+	ReturnCacheable(maxAge time.Duration) Expectation
+	// ReturnNoCache sets the Cache-Control, Pragma and Expires headers to stop the clients from caching the response.
+	//
+	//	Server.Expect(httpmock.MethodGet, "/path").
+	//		ReturnNoCache()
+	ReturnNoCache() Expectation
 	// Return sets the result to return to client.
 	//
 	//	Server.Expect(httpmock.MethodGet, "/path").
@@ -80,18 +176,113 @@ type Expectation interface {
 	//	Server.Expect(httpmock.MethodGet, "/path").
 	//		ReturnJSON(map[string]string{"foo": "bar"})
 	ReturnJSON(body any) Expectation
+	// ReturnTemplate uses the result of the text/template as the response, so the responses of the calls could differ
+	// without a custom handler, for example, an incrementing id or a cursor. The data of the template is TemplateData.
+	//
+	//	Server.Expect(httpmock.MethodGet, "/events").
+	//		ReturnTemplate(`{"id":{{.CallNumber}}}`).
+	//		UnlimitedTimes()
+	ReturnTemplate(tmpl string) Expectation
 	// ReturnFile reads the file using ioutil.ReadFile and uses it as the result to return to client.
 	//
 	//	Server.Expect(httpmock.MethodGet, "/path").
 	//		ReturnFile("resources/fixtures/response.txt")
 	ReturnFile(filePath string) Expectation
-	// Run sets the handler to handle a given request.
+	// ReturnBase64 decodes the standard base64 string and uses it as the result to return to client, so a binary
+	// payload can be embedded in the test. It panics if the string is not valid.
+	//
+	//	Server.Expect(httpmock.MethodGet, "/logo.png").
+	//		ReturnHeader("Content-Type", "image/png").
+	//		ReturnBase64("iVBORw0KGgo=")
+	ReturnBase64(b64 string) Expectation
+	// ReturnCorruptGzip sets the Content-Encoding header to gzip and returns the compressed result, truncated, to test
+	// how the client handles a broken compression of the upstream.
+	//
+	//	Server.Expect(httpmock.MethodGet, "/path").
+	//		ReturnCorruptGzip("hello world!")
+	ReturnCorruptGzip(v any) Expectation
+	// ReturnWithCharset transcodes the result to the charset, for example, ISO-8859-1, and sets the charset parameter
+	// of the Content-Type header, to test the clients that handle non utf-8 upstreams. The Content-Type header of the
+	// expectation is kept, or text/plain is used, so ReturnHeader must be called before. It panics if the charset is
+	// not supported, or the result is not in the charset.
+	//
+	//	Server.Expect(httpmock.MethodGet, "/path").
+	//		ReturnHeader("Content-Type", "text/html").
+	//		ReturnWithCharset("<p>café</p>", "ISO-8859-1")
+	ReturnWithCharset(v any, charset string) Expectation
+	// ReturnChunks writes the result in the chunks, flushing every chunk and waiting for the gap between them, to test
+	// the incremental parsers and the read deadlines of the clients.
+	//
+	//	Server.Expect(httpmock.MethodGet, "/path").
+	//		ReturnChunks([][]byte{[]byte("hello "), []byte("world!")}, 100*time.Millisecond)
+	ReturnChunks(chunks [][]byte, gap time.Duration) Expectation
+	// AbortAfterBytes writes only the first n bytes of the response body, then resets the connection, to test the
+	// resumable downloads and the recovery from a partial read.
+	//
+	//	Server.Expect(httpmock.MethodGet, "/file").
+	//		ReturnFile("resources/fixtures/file.bin").
+	//		AbortAfterBytes(1024)
+	AbortAfterBytes(n int) Expectation
+	// ReturnWeighted picks one of the results randomly to return to client, the chance of a result is proportional to
+	// its weight. The results could be string, fmt.Stringer, or any other comparable types that Return accepts.
+	//
+	//	Server.Expect(httpmock.MethodGet, "/path").
+	//		ReturnWeighted(map[any]int{"hello": 3, "bye": 1}).
+	//		WithRandSeed(42)
+	ReturnWeighted(responses map[any]int) Expectation
+	// ReturnByUserAgent sets the results to return to client depending on its User-Agent header. The key is a substring
+	// of the User-Agent, the longest matching key wins, and the empty key is the default result.
+	//
+	//	Server.Expect(httpmock.MethodGet, "/path").
+	//		ReturnByUserAgent(map[string]any{
+	//			"MyApp/1.": `{"name": "john"}`,
+	//			"":         `{"first_name": "john"}`,
+	//		})
+	ReturnByUserAgent(responses map[string]any) Expectation
+	// WithRandSeed sets the seed of the random source of the expectation, so the random results are reproducible.
+	//
+	//	Server.Expect(httpmock.MethodGet, "/path").
+	//		ReturnWeighted(map[any]int{"hello": 3, "bye": 1}).
+	//		WithRandSeed(42)
+	WithRandSeed(seed int64) Expectation
+	// Run sets the handler to handle a given request. The handler can register new expectations to the server, for
+	// example, to expect the subsequent requests for a newly created resource.
 	//
 	//	   Server.Expect(httpmock.MethodGet, "/path").
 	//			Run(func(*http.Request) ([]byte, error) {
 	//				return []byte("hello world!"), nil
 	//			})
 	Run(handle func(r *http.Request) ([]byte, error)) Expectation
+	// RunHandler sets the http.Handler to handle a given request. The handler writes the response code and body by
+	// itself, the response headers of the expectation are set beforehand. The response writer implements http.Flusher
+	// and http.Hijacker, so the handler can stream the response, for example, for a long-poll or a chunked response.
+	//
+	//	Server.Expect(httpmock.MethodGet, "/events").
+	//		RunHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	//			for _, event := range events {
+	//				_, _ = w.Write(event)
+	//
+	//				w.(http.Flusher).Flush()
+	//			}
+	//		}))
+	RunHandler(h http.Handler) Expectation
+	// Wrap decorates the handler of the expectation, for example, to collect metrics or to inject a failure on a
+	// condition, without rewriting the handler. The first wrapper is the outermost one.
+	//
+	//	Server.Expect(httpmock.MethodGet, "/path").
+	//		Return("hello world!").
+	//		Wrap(func(next httpmock.ExpectationHandler) httpmock.ExpectationHandler {
+	//			return httpmock.ExpectationHandlerFunc(func(w http.ResponseWriter, r *http.Request, h map[string]string) error {
+	//				if r.Header.Get("X-Fail") != "" {
+	//					w.WriteHeader(httpmock.StatusServiceUnavailable)
+	//
+	//					return nil
+	//				}
+	//
+	//				return next.Handle(w, r, h)
+	//			})
+	//		})
+	Wrap(wrapper func(next ExpectationHandler) ExpectationHandler) Expectation
 
 	// Once indicates that the mock should only return the value once.
 	//
@@ -119,6 +310,26 @@ type Expectation interface {
 	//		Times(5)
 	Times(i uint) Expectation
 
+	// InScenario adds the expectation to the scenario, see Server.Scenario. The expectation is matched only when the
+	// scenario is in the state set by WhenScenarioStateIs, if any, and it moves the scenario to the state set by
+	// WillSetStateTo, if any, when it is matched.
+	//
+	//	Server.Expect(httpmock.MethodPost, "/checkout/pay").
+	//		InScenario("checkout").
+	//		WhenScenarioStateIs(httpmock.ScenarioStarted).
+	//		WillSetStateTo("paid")
+	//
+	//	Server.Expect(httpmock.MethodGet, "/checkout").
+	//		InScenario("checkout").
+	//		WhenScenarioStateIs("paid").
+	//		Return(`{"status":"paid"}`)
+	InScenario(name string) Expectation
+	// WhenScenarioStateIs expects the scenario of the expectation to be in the state, see InScenario.
+	WhenScenarioStateIs(state string) Expectation
+	// WillSetStateTo moves the scenario of the expectation to the state when the expectation is matched, see
+	// InScenario.
+	WillSetStateTo(state string) Expectation
+
 	// WaitUntil sets the channel that will block the mocked return until its closed
 	// or a message is received.
 	//
@@ -126,12 +337,84 @@ type Expectation interface {
 	//		WaitUntil(time.After(time.Second)).
 	//		Return("hello world!")
 	WaitUntil(w <-chan time.Time) Expectation
+	// WaitUntilContext blocks the mocked return until the context is done, so the response can be released by the test.
+	//
+	//	ctx, release := context.WithCancel(context.Background())
+	//
+	//	Server.Expect(http.MethodGet, "/path").
+	//		WaitUntilContext(ctx).
+	//		Return("hello world!")
+	//
+	//	// Do something, then release the response.
+	//	release()
+	WaitUntilContext(ctx context.Context) Expectation
+	// WaitFor blocks the mocked return until the condition is met. The condition is checked every poll interval. It
+	// panics if the poll interval is not positive.
+	//
+	//	Server.Expect(http.MethodGet, "/path").
+	//		WaitFor(func() bool { return db.HasUser(42) }, 10*time.Millisecond).
+	//		Return("hello world!")
+	WaitFor(condition func() bool, pollInterval time.Duration) Expectation
 	// After sets how long to block until the call returns.
 	//
 	//	Server.Expect(http.MethodGet, "/path").
 	//		After(time.Second).
 	//		Return("hello world!")
 	After(d time.Duration) Expectation
+	// AfterFunc sets how long to block until the call returns, depending on the number of the call, starting from 1.
+	//
+	//	Server.Expect(http.MethodGet, "/path").
+	//		AfterFunc(func(callN uint) time.Duration {
+	//			return time.Duration(callN) * 100 * time.Millisecond
+	//		}).
+	//		UnlimitedTimes().
+	//		Return("hello world!")
+	AfterFunc(fn func(callN uint) time.Duration) Expectation
+	// WithTimeout bounds the total time to handle the request, including the wait and the handler. When the time is up,
+	// the server responds http.StatusGatewayTimeout, or the response set by ReturnOnTimeout.
+	//
+	//	Server.Expect(http.MethodGet, "/path").
+	//		WithTimeout(time.Second).
+	//		Run(slowHandler)
+	WithTimeout(d time.Duration) Expectation
+	// ReturnOnTimeout sets the response code and body when the handling time is up, see WithTimeout.
+	//
+	//	Server.Expect(http.MethodGet, "/path").
+	//		WithTimeout(time.Second).
+	//		ReturnOnTimeout(httpmock.StatusServiceUnavailable, "try again later").
+	//		Run(slowHandler)
+	ReturnOnTimeout(code int, body any) Expectation
+
+	// ExpectWithin expects the request to arrive within the duration since the server was started, or since the last call
+	// of the expectation registered before it. The late or missing requests are reported by
+	// Server.ExpectationsWereMet.
+	//
+	//	Server.Expect(http.MethodGet, "/path").
+	//		ExpectWithin(time.Second)
+	ExpectWithin(d time.Duration) Expectation
+	// Tag adds the expectation to a group, so the group could be verified on its own with
+	// Server.ExpectationsWereMetFor. An expectation could have many tags.
+	//
+	//	Server.Expect(http.MethodPost, "/login").
+	//		Tag("login")
+	Tag(tag string) Expectation
+
+	// Clone returns an independent copy of the expectation, without its calls, to register a variation of it. If the
+	// expectation is registered with a server, the copy is registered with the same server.
+	//
+	//	Server.Expect(http.MethodGet, "/path").
+	//		Return("hello world!").
+	//		Clone().
+	//		ReturnCode(httpmock.StatusInternalServerError)
+	Clone() Expectation
+
+	// MarshalJSON describes the expectation in JSON, including its matchers and its response, see
+	// ExpectationDescription. The description of an expectation of exact or regular expression matchers and a static
+	// response could be read back by ReadExpectationSpecs.
+	MarshalJSON() ([]byte, error)
+	// String describes the expectation in a human-readable format, for example, to print the expectations of a failing
+	// test, see Server.Expectations.
+	String() string
 }
 
 // ExpectationHandler handles the expectation.
@@ -139,16 +422,50 @@ type ExpectationHandler interface {
 	Handle(w http.ResponseWriter, r *http.Request, defaultHeaders map[string]string) error
 }
 
+// ExpectationHandlerFunc is a function that handles the expectation.
+type ExpectationHandlerFunc func(w http.ResponseWriter, r *http.Request, defaultHeaders map[string]string) error
+
+// Handle calls the function.
+func (f ExpectationHandlerFunc) Handle(w http.ResponseWriter, r *http.Request, defaultHeaders map[string]string) error {
+	return f(w, r, defaultHeaders)
+}
+
+// PlannedExpectation is an expectation that can be planned by a planner.Planner and handled by the server.
+type PlannedExpectation interface {
+	Expectation
+	planner.Expectation
+	ExpectationHandler
+}
+
 var (
-	_ Expectation         = (*requestExpectation)(nil)
-	_ planner.Expectation = (*requestExpectation)(nil)
+	_ PlannedExpectation    = (*requestExpectation)(nil)
+	_ planner.TimesRestorer = (*requestExpectation)(nil)
 )
 
+// errHandleTimeout indicates that the time to handle the request is up.
+var errHandleTimeout = errors.New("handle timeout")
+
+// NewExpectation creates a new expectation that is not registered to any server. It is useful for the custom planners
+// that need to fabricate the expectations. Same as Server.Expect, the expectation is expected once by default.
+//
+//	httpmock.NewExpectation(httpmock.MethodGet, "/path").
+//		Return("hello world!")
+func NewExpectation(method string, requestURI any) PlannedExpectation {
+	e := newRequestExpectation(method, requestURI)
+
+	e.Once()
+
+	return e
+}
+
 // requestExpectation is an expectation.
 type requestExpectation struct {
 	locker sync.Locker
 	waiter wait.Waiter
 
+	// id is the identifier of the expectation in the server, 0 if it is not registered.
+	id int
+
 	// requestMethod is the expected HTTP requestMethod of the given request.
 	requestMethod string
 	// requestURIMatcher is the expected HTTP request URI of the given request.
@@ -158,16 +475,91 @@ type requestExpectation struct {
 	requestHeaderMatcher matcher.HeaderMatcher
 	// requestBodyMatcher is the expected body of the given request.
 	requestBodyMatcher *matcher.BodyMatcher
+	// requestHeaderNames are the expected header names, in the casing that the user wrote them.
+	requestHeaderNames []string
+	// requestMatchers match the whole request, for example, the order of the headers.
+	requestMatchers []requestMatcher
 
 	// responseCode is the response code when the request is handled.
 	responseCode int
 	// responseHeader is a list of response headers to be sent to client when the request is handled.
 	responseHeader Header
+	// cacheHeaders returns the caching headers of the response at the time the request is handled.
+	cacheHeaders func(now time.Time) Header
 
 	handle func(r *http.Request) ([]byte, error)
+	// example is the static response body, nil if the response is dynamic.
+	example []byte
+	// httpHandler handles the request and writes the response by itself, it takes precedence over handle.
+	httpHandler http.Handler
+	// timeout bounds the total time to handle the request, 0 if there is no limit.
+	timeout time.Duration
+	// timeoutCode and timeoutBody are the response when the handling time is up.
+	timeoutCode int
+	timeoutBody []byte
+	// random is the random source of the expectation, for example, to pick a weighted result.
+	random *rand.Rand
 
 	fulfilledTimes uint
 	repeatTimes    uint
+	// times is the number of times the expectation is expected, as it is set by Times, 0 if it is unlimited.
+	times uint
+
+	// firstCalledAt and lastCalledAt are the times when the expectation was fulfilled for the first and the last time.
+	firstCalledAt time.Time
+	lastCalledAt  time.Time
+	// calledAt contains the times when the expectation was fulfilled.
+	calledAt []time.Time
+	// handledTimes and handleDuration are the number of times and the total duration the requests were handled.
+	handledTimes   uint
+	handleDuration time.Duration
+	// inFlight contains the time ranges when the requests were in flight.
+	inFlight []inFlightRange
+	// wrappers decorate the handler of the expectation, see Wrap.
+	wrappers []func(next ExpectationHandler) ExpectationHandler
+	// abortAfter is the number of bytes of the body to write before resetting the connection, -1 if it is not reset.
+	abortAfter int
+
+	// headerMergeOf returns how the default response headers of the server that creates the expectation are merged.
+	headerMergeOf func() HeaderMergePolicy
+	// headerOrderCaptureOf enables the capture of the header order on the server that creates the expectation, see
+	// WithHeaderOrder.
+	headerOrderCaptureOf func()
+	// noDefaultHeaders indicates whether the default response headers are not written, see WithoutDefaultHeaders.
+	noDefaultHeaders bool
+
+	// within is the time that the request is expected to arrive within, 0 if there is no deadline, see ExpectWithin.
+	within time.Duration
+	// location is the file and the line where the expectation is declared, empty if it is not recorded.
+	location string
+	// tags are the groups of the expectation, see Tag.
+	tags []string
+
+	// register registers a clone of the expectation with the server that registers the expectation, nil if the
+	// expectation is standalone.
+	register func(e *requestExpectation)
+
+	// scenarioOf returns the scenario of the name from the server that creates the expectation.
+	scenarioOf func(name string) *Scenario
+	// scenario is the scenario of the expectation, nil if it is not in a scenario.
+	scenario *Scenario
+	// scenarioState is the expected state of the scenario, empty if any state is expected.
+	scenarioState string
+	// scenarioNextState is the state that the scenario moves to when the expectation is matched, empty if it does not
+	// move.
+	scenarioNextState string
+}
+
+// asRequestExpectation returns the request expectation of a planned expectation or a handler, unwrapping the views of
+// the planners, for example, the sessions of planner.PerSession.
+func asRequestExpectation(v any) (*requestExpectation, bool) {
+	if e, ok := v.(planner.Expectation); ok {
+		v = planner.Unwrap(e)
+	}
+
+	e, ok := v.(*requestExpectation)
+
+	return e, ok
 }
 
 func (e *requestExpectation) lock() {
@@ -222,6 +614,17 @@ func (e *requestExpectation) Fulfilled() {
 	}
 
 	e.fulfilledTimes++
+	e.lastCalledAt = time.Now()
+
+	if e.firstCalledAt.IsZero() {
+		e.firstCalledAt = e.lastCalledAt
+	}
+
+	e.calledAt = append(e.calledAt, e.lastCalledAt)
+
+	if e.scenario != nil && e.scenarioNextState != "" {
+		e.scenario.SetState(e.scenarioNextState)
+	}
 }
 
 func (e *requestExpectation) FulfilledTimes() uint {
@@ -231,7 +634,28 @@ func (e *requestExpectation) FulfilledTimes() uint {
 	return e.fulfilledTimes
 }
 
-// WithHeader sets an expected header of the given request.
+// RestoreTimes satisfies the planner.TimesRestorer interface.
+func (e *requestExpectation) RestoreTimes(remainTimes, fulfilledTimes uint) {
+	e.lock()
+	defer e.unlock()
+
+	e.repeatTimes = remainTimes
+	e.fulfilledTimes = fulfilledTimes
+
+	// Forget the calls after the restored ones.
+	if int(fulfilledTimes) < len(e.calledAt) {
+		e.calledAt = e.calledAt[:fulfilledTimes]
+
+		if len(e.calledAt) == 0 {
+			e.firstCalledAt, e.lastCalledAt = time.Time{}, time.Time{}
+		} else {
+			e.lastCalledAt = e.calledAt[len(e.calledAt)-1]
+		}
+	}
+}
+
+// WithHeader sets an expected header of the given request. The header name is case-insensitive, unless
+// WithStrictHeaderCase is used.
 //
 //	Server.Expect(httpmock.MethodGet, "/path").
 //		WithHeader("foo", "bar")
@@ -245,7 +669,8 @@ func (e *requestExpectation) WithHeader(header string, val any) Expectation {
 		e.requestHeaderMatcher = matcher.HeaderMatcher{}
 	}
 
-	e.requestHeaderMatcher[header] = matcher.Match(val)
+	e.requestHeaderMatcher[http.CanonicalHeaderKey(header)] = matcher.Match(val)
+	e.requestHeaderNames = appendHeaderName(e.requestHeaderNames, header)
 
 	return e
 }
@@ -262,11 +687,188 @@ func (e *requestExpectation) WithHeaders(headers map[string]any) Expectation {
 	return e
 }
 
-// WithBody sets the expected body of the given request. It could be []byte, string, fmt.Stringer, or a Matcher.
+// WithHeaderOrder sets the expected order of the headers of the given request. The headers must be sent in the same
+// relative order, the other headers could be anywhere. The header names are case-insensitive.
+//
+//	Server.Expect(httpmock.MethodGet, "/path").
+//		WithHeaderOrder("Host", "User-Agent", "Accept")
+func (e *requestExpectation) WithHeaderOrder(headers ...string) Expectation {
+	e.enableHeaderOrderCapture()
+
+	return e.withRequestMatcher(fmt.Sprintf("header order %q", headers), func(r *http.Request) error {
+		return matchHeaderOrder(headers, RequestHeaderOrder(r))
+	})
+}
+
+// WithStrictHeaderCase requires the headers set by WithHeader to be sent with the exact same casing, for the clients
+// that must send a specific casing to a picky server.
+//
+//	Server.Expect(httpmock.MethodGet, "/path").
+//		WithHeader("x-api-key", "secret").
+//		WithStrictHeaderCase()
+func (e *requestExpectation) WithStrictHeaderCase() Expectation {
+	e.enableHeaderOrderCapture()
+
+	return e.withRequestMatcher("strict header case", func(r *http.Request) error {
+		e.lock()
+		names := e.requestHeaderNames
+		e.unlock()
+
+		return matchHeaderCase(names, RequestHeaderOrder(r))
+	})
+}
+
+// enableHeaderOrderCapture enables the capture of the header order on the server that creates the expectation.
+func (e *requestExpectation) enableHeaderOrderCapture() {
+	e.lock()
+	enable := e.headerOrderCaptureOf
+	e.unlock()
+
+	if enable != nil {
+		enable()
+	}
+}
+
+// WithoutQuery expects the query parameter not to be sent, for example, a deprecated or forbidden one.
+//
+//	Server.Expect(httpmock.MethodGet, httpmock.RegexPattern(`^/users`)).
+//		WithoutQuery("api_key")
+func (e *requestExpectation) WithoutQuery(key string) Expectation {
+	return e.withRequestMatcher(fmt.Sprintf("without query %q", key), func(r *http.Request) error {
+		if v, ok := r.URL.Query()[key]; ok {
+			return fmt.Errorf("query %q is not expected, %q received", key, v) // nolint: goerr113
+		}
+
+		return nil
+	})
+}
+
+// WithProto expects the request to be sent with the protocol version, for example, HTTP/2.0 when the server is started
+// with WithHTTP2 and StartTLS.
+//
+//	Server.Expect(httpmock.MethodGet, "/path").
+//		WithProto("HTTP/2.0")
+func (e *requestExpectation) WithProto(proto string) Expectation {
+	return e.withRequestMatcher(fmt.Sprintf("proto %q", proto), func(r *http.Request) error {
+		if r.Proto != proto {
+			return fmt.Errorf("proto %q expected, %q received", proto, r.Proto) // nolint: goerr113
+		}
+
+		return nil
+	})
+}
+
+// WithScheme expects the target of the request to have the scheme, for example, http or https. The scheme of an
+// absolute-form request, for example, a request to a forward proxy, is taken from its request uri, otherwise, it is
+// https if the request is sent over TLS.
+//
+//	Server.Expect(httpmock.MethodGet, "/path").
+//		WithScheme("https")
+func (e *requestExpectation) WithScheme(scheme string) Expectation {
+	return e.withRequestMatcher(fmt.Sprintf("scheme %q", scheme), func(r *http.Request) error {
+		if actual := planner.TargetURL(r).Scheme; !strings.EqualFold(actual, scheme) {
+			return fmt.Errorf("scheme %q expected, %q received", scheme, actual) // nolint: goerr113
+		}
+
+		return nil
+	})
+}
+
+// WithHost expects the target of the request to have the host, without the default port of the scheme. The host of an
+// absolute-form request, for example, a request to a forward proxy, is taken from its request uri, otherwise, it is
+// the Host header. The host could be a string or a Matcher.
+//
+//	Server.Expect(httpmock.MethodGet, "/path").
+//		WithHost("api.example.com")
+func (e *requestExpectation) WithHost(host any) Expectation {
+	m := matcher.Match(host)
+
+	return e.withRequestMatcher(fmt.Sprintf("host %q", m.Expected()), func(r *http.Request) error {
+		actual := planner.TargetURL(r).Host
+
+		matched, err := m.Match(actual)
+		if err != nil {
+			return fmt.Errorf("could not match host: %w", err)
+		}
+
+		if !matched {
+			return fmt.Errorf("host %q expected, %q received", m.Expected(), actual) // nolint: goerr113
+		}
+
+		return nil
+	})
+}
+
+// WithRemoteAddr expects the request to be sent from the address, for example, to tell apart the simulated clients that
+// are bound to different local addresses. The address is the IP of the client, without the port, and it could be a
+// string or a Matcher.
+//
+//	Server.Expect(httpmock.MethodGet, "/path").
+//		WithRemoteAddr("127.0.0.2")
+func (e *requestExpectation) WithRemoteAddr(addr any) Expectation {
+	m := matcher.Match(addr)
+
+	return e.withRequestMatcher(fmt.Sprintf("remote address %q", m.Expected()), func(r *http.Request) error {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+
+		matched, err := m.Match(host)
+		if err != nil {
+			return fmt.Errorf("could not match remote address: %w", err)
+		}
+
+		if !matched {
+			return fmt.Errorf("remote address %q expected, %q received", m.Expected(), host) // nolint: goerr113
+		}
+
+		return nil
+	})
+}
+
+// requestMatcher matches the whole request.
+type requestMatcher struct {
+	// description describes the matcher, for example, in the output of String, empty if it is described elsewhere.
+	description string
+	match       func(r *http.Request) error
+}
+
+// withRequestMatcher adds a matcher of the whole request.
+func (e *requestExpectation) withRequestMatcher(description string, m func(r *http.Request) error) Expectation {
+	e.lock()
+	defer e.unlock()
+
+	e.requestMatchers = append(e.requestMatchers, requestMatcher{description: description, match: m})
+
+	return e
+}
+
+// MatchRequest satisfies the planner.RequestMatcher interface.
+func (e *requestExpectation) MatchRequest(actual *http.Request) error {
+	e.lock()
+	matchers := e.requestMatchers
+	e.unlock()
+
+	for _, m := range matchers {
+		if err := m.match(actual); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WithBody sets the expected body of the given request. It could be []byte, string, fmt.Stringer, a Matcher, or a
+// RequestMatcherFunc, see MatchRequest.
 //
 //	Server.Expect(httpmock.MethodGet, "/path").
 //		WithBody("hello world!")
 func (e *requestExpectation) WithBody(body any) Expectation {
+	if m, ok := body.(RequestMatcherFunc); ok {
+		return e.withRequestMatcher(requestMatcherDescription, m.matchRequest)
+	}
+
 	e.lock()
 	defer e.unlock()
 
@@ -298,6 +900,18 @@ func (e *requestExpectation) WithBodyJSON(v any) Expectation {
 	return e.WithBody(matcher.JSON(string(body)))
 }
 
+// WithBodyBase64 decodes the standard base64 string and uses it as the expected body of the given request, so a binary
+// payload can be embedded in the test. It panics if the string is not valid.
+//
+//	Server.Expect(httpmock.MethodPost, "/upload").
+//		WithBodyBase64("iVBORw0KGgo=")
+func (e *requestExpectation) WithBodyBase64(b64 string) Expectation {
+	body, err := base64.StdEncoding.DecodeString(b64)
+	must.NotFail(err)
+
+	return e.WithBody(body)
+}
+
 // ReturnCode sets the response code.
 //
 //	Server.Expect(httpmock.MethodGet, "/path").
@@ -341,6 +955,45 @@ func (e *requestExpectation) ReturnHeaders(headers Header) Expectation {
 	return e
 }
 
+// ReturnCacheable sets the Cache-Control, Expires and Age headers to let the clients cache the response for the
+// duration. The Expires header is computed when the request is handled. The headers set by ReturnHeader take precedence.
+//
+//	Server.Expect(httpmock.MethodGet, "/path").
+//		ReturnCacheable(time.Hour)
+func (e *requestExpectation) ReturnCacheable(maxAge time.Duration) Expectation {
+	return e.withCacheHeaders(func(now time.Time) Header {
+		return Header{
+			"Cache-Control": fmt.Sprintf("public, max-age=%d", int64(maxAge/time.Second)),
+			"Expires":       now.Add(maxAge).UTC().Format(http.TimeFormat),
+			"Age":           "0",
+		}
+	})
+}
+
+// ReturnNoCache sets the Cache-Control, Pragma and Expires headers to stop the clients from caching the response. The
+// headers set by ReturnHeader take precedence.
+//
+//	Server.Expect(httpmock.MethodGet, "/path").
+//		ReturnNoCache()
+func (e *requestExpectation) ReturnNoCache() Expectation {
+	return e.withCacheHeaders(func(time.Time) Header {
+		return Header{
+			"Cache-Control": "no-store, no-cache, must-revalidate, max-age=0",
+			"Pragma":        "no-cache",
+			"Expires":       time.Unix(0, 0).UTC().Format(http.TimeFormat),
+		}
+	})
+}
+
+func (e *requestExpectation) withCacheHeaders(headers func(now time.Time) Header) Expectation {
+	e.lock()
+	defer e.unlock()
+
+	e.cacheHeaders = headers
+
+	return e
+}
+
 // Return sets the result to return to client.
 //
 //	Server.Expect(httpmock.MethodGet, "/path").
@@ -348,9 +1001,11 @@ func (e *requestExpectation) ReturnHeaders(headers Header) Expectation {
 func (e *requestExpectation) Return(v any) Expectation {
 	body := []byte(value.String(v))
 
-	return e.Run(func(*http.Request) ([]byte, error) {
+	e.Run(func(*http.Request) ([]byte, error) {
 		return body, nil
 	})
+
+	return e.withExample(body)
 }
 
 // Returnf formats according to a format specifier and use it as the result to return to client.
@@ -366,9 +1021,23 @@ func (e *requestExpectation) Returnf(format string, args ...any) Expectation {
 //	Server.Expect(httpmock.MethodGet, "/path").
 //		ReturnJSON(map[string]string{"foo": "bar"})
 func (e *requestExpectation) ReturnJSON(body any) Expectation {
-	return e.Run(func(*http.Request) ([]byte, error) {
+	e.Run(func(*http.Request) ([]byte, error) {
 		return json.Marshal(body)
 	})
+
+	example, _ := json.Marshal(body) // nolint: errchkjson
+
+	return e.withExample(example)
+}
+
+// withExample sets the static response body, for example, to export the expectation as an OpenAPI document.
+func (e *requestExpectation) withExample(body []byte) Expectation {
+	e.lock()
+	defer e.unlock()
+
+	e.example = body
+
+	return e
 }
 
 // ReturnFile reads the file using ioutil.ReadFile and uses it as the result to return to client.
@@ -389,6 +1058,208 @@ func (e *requestExpectation) ReturnFile(filePath string) Expectation {
 	})
 }
 
+// ReturnBase64 decodes the standard base64 string and uses it as the result to return to client, so a binary payload
+// can be embedded in the test. It panics if the string is not valid.
+//
+//	Server.Expect(httpmock.MethodGet, "/logo.png").
+//		ReturnHeader("Content-Type", "image/png").
+//		ReturnBase64("iVBORw0KGgo=")
+func (e *requestExpectation) ReturnBase64(b64 string) Expectation {
+	body, err := base64.StdEncoding.DecodeString(b64)
+	must.NotFail(err)
+
+	return e.Return(body)
+}
+
+// ReturnCorruptGzip sets the Content-Encoding header to gzip and returns the compressed result, truncated, to test how
+// the client handles a broken compression of the upstream.
+//
+//	Server.Expect(httpmock.MethodGet, "/path").
+//		ReturnCorruptGzip("hello world!")
+func (e *requestExpectation) ReturnCorruptGzip(v any) Expectation {
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+
+	_, err := zw.Write([]byte(value.String(v)))
+	must.NotFail(err)
+	must.NotFail(zw.Close())
+
+	// Cut the compressed data in half, so the checksum and the size at the end of the stream are always missing.
+	body := buf.Bytes()[:buf.Len()/2]
+
+	e.ReturnHeader("Content-Encoding", "gzip")
+
+	return e.Run(func(*http.Request) ([]byte, error) {
+		return body, nil
+	})
+}
+
+// ReturnWithCharset transcodes the result to the charset, for example, ISO-8859-1, and sets the charset parameter of the
+// Content-Type header, to test the clients that handle non utf-8 upstreams. The Content-Type header of the expectation
+// is kept, or text/plain is used, so ReturnHeader must be called before. It panics if the charset is not supported, or
+// the result is not in the charset.
+//
+//	Server.Expect(httpmock.MethodGet, "/path").
+//		ReturnHeader("Content-Type", "text/html").
+//		ReturnWithCharset("<p>café</p>", "ISO-8859-1")
+func (e *requestExpectation) ReturnWithCharset(v any, charset string) Expectation {
+	body, err := encodeCharset(value.String(v), charset)
+	must.NotFail(err)
+
+	e.lock()
+
+	header, contentType := "Content-Type", ""
+
+	for k, v := range e.responseHeader {
+		if strings.EqualFold(k, header) {
+			header, contentType = k, v
+		}
+	}
+
+	e.unlock()
+
+	e.ReturnHeader(header, withCharset(contentType, charset))
+
+	return e.Run(func(*http.Request) ([]byte, error) {
+		return body, nil
+	})
+}
+
+// ReturnChunks writes the result in the chunks, flushing every chunk and waiting for the gap between them, to test the
+// incremental parsers and the read deadlines of the clients.
+//
+//	Server.Expect(httpmock.MethodGet, "/path").
+//		ReturnChunks([][]byte{[]byte("hello "), []byte("world!")}, 100*time.Millisecond)
+func (e *requestExpectation) ReturnChunks(chunks [][]byte, gap time.Duration) Expectation {
+	return e.RunHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e.lock()
+		code := e.responseCode
+		e.unlock()
+
+		w.WriteHeader(code)
+
+		flusher, _ := w.(http.Flusher) // nolint: errcheck
+
+		for i, chunk := range chunks {
+			if i > 0 && gap > 0 {
+				if err := wait.ForDuration(gap).Wait(r.Context()); err != nil {
+					return
+				}
+			}
+
+			if _, err := w.Write(chunk); err != nil {
+				return
+			}
+
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}))
+}
+
+// ReturnWeighted picks one of the results randomly to return to client, the chance of a result is proportional to its
+// weight.
+//
+//	Server.Expect(httpmock.MethodGet, "/path").
+//		ReturnWeighted(map[any]int{"hello": 3, "bye": 1})
+func (e *requestExpectation) ReturnWeighted(responses map[any]int) Expectation {
+	type weightedBody struct {
+		body   []byte
+		weight int
+	}
+
+	bodies := make([]weightedBody, 0, len(responses))
+	total := 0
+
+	for v, weight := range responses {
+		if weight <= 0 {
+			continue
+		}
+
+		bodies = append(bodies, weightedBody{body: []byte(value.String(v)), weight: weight})
+		total += weight
+	}
+
+	if total == 0 {
+		panic(errors.New("could not return weighted results: no result with positive weight")) // nolint: goerr113
+	}
+
+	// Sort the results so the picks only depend on the seed.
+	sort.Slice(bodies, func(i, j int) bool {
+		return bytes.Compare(bodies[i].body, bodies[j].body) < 0
+	})
+
+	return e.Run(func(*http.Request) ([]byte, error) {
+		e.lock()
+		n := e.random.Intn(total)
+		e.unlock()
+
+		for _, b := range bodies {
+			if n < b.weight {
+				return b.body, nil
+			}
+
+			n -= b.weight
+		}
+
+		return bodies[len(bodies)-1].body, nil
+	})
+}
+
+// ReturnByUserAgent sets the results to return to client depending on its User-Agent header. The key is a substring of
+// the User-Agent, the longest matching key wins, and the empty key is the default result.
+//
+//	Server.Expect(httpmock.MethodGet, "/path").
+//		ReturnByUserAgent(map[string]any{
+//			"MyApp/1.": `{"name": "john"}`,
+//			"":         `{"first_name": "john"}`,
+//		})
+func (e *requestExpectation) ReturnByUserAgent(responses map[string]any) Expectation {
+	bodies := make(map[string][]byte, len(responses))
+
+	for ua, v := range responses {
+		bodies[ua] = []byte(value.String(v))
+	}
+
+	return e.Run(func(r *http.Request) ([]byte, error) {
+		userAgent := r.UserAgent()
+		matched, found := "", false
+
+		for ua := range bodies {
+			if !strings.Contains(userAgent, ua) {
+				continue
+			}
+
+			// Prefer the longest key, then the smallest one, so the result does not depend on the map order.
+			if !found || len(ua) > len(matched) || (len(ua) == len(matched) && ua < matched) {
+				matched, found = ua, true
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("no response for user agent: %q", userAgent) // nolint: goerr113
+		}
+
+		return bodies[matched], nil
+	})
+}
+
+// WithRandSeed sets the seed of the random source of the expectation, so the random results are reproducible.
+//
+//	Server.Expect(httpmock.MethodGet, "/path").
+//		ReturnWeighted(map[any]int{"hello": 3, "bye": 1}).
+//		WithRandSeed(42)
+func (e *requestExpectation) WithRandSeed(seed int64) Expectation {
+	e.lock()
+	defer e.unlock()
+
+	e.random = rand.New(rand.NewSource(seed)) // nolint: gosec
+
+	return e
+}
+
 // Run sets the handler to handle a given request.
 //
 //	   Server.Expect(httpmock.MethodGet, "/path").
@@ -400,6 +1271,56 @@ func (e *requestExpectation) Run(handle func(r *http.Request) ([]byte, error)) E
 	defer e.unlock()
 
 	e.handle = handle
+	e.httpHandler = nil
+	e.example = nil
+
+	return e
+}
+
+// RunHandler sets the http.Handler to handle a given request. The handler writes the response code and body by itself,
+// the response headers of the expectation are set beforehand. The response writer implements http.Flusher and
+// http.Hijacker, so the handler can stream the response, for example, for a long-poll or a chunked response. With
+// WithTimeout, the context of the request is canceled when the time is up.
+//
+//	Server.Expect(httpmock.MethodGet, "/events").
+//		RunHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//			for _, event := range events {
+//				_, _ = w.Write(event)
+//
+//				w.(http.Flusher).Flush()
+//			}
+//		}))
+func (e *requestExpectation) RunHandler(h http.Handler) Expectation {
+	e.lock()
+	defer e.unlock()
+
+	e.httpHandler = h
+	e.example = nil
+
+	return e
+}
+
+// Wrap decorates the handler of the expectation, for example, to collect metrics or to inject a failure on a condition,
+// without rewriting the handler. The first wrapper is the outermost one.
+//
+//	Server.Expect(httpmock.MethodGet, "/path").
+//		Return("hello world!").
+//		Wrap(func(next httpmock.ExpectationHandler) httpmock.ExpectationHandler {
+//			return httpmock.ExpectationHandlerFunc(func(w http.ResponseWriter, r *http.Request, h map[string]string) error {
+//				if r.Header.Get("X-Fail") != "" {
+//					w.WriteHeader(httpmock.StatusServiceUnavailable)
+//
+//					return nil
+//				}
+//
+//				return next.Handle(w, r, h)
+//			})
+//		})
+func (e *requestExpectation) Wrap(wrapper func(next ExpectationHandler) ExpectationHandler) Expectation {
+	e.lock()
+	defer e.unlock()
+
+	e.wrappers = append(e.wrappers, wrapper)
 
 	return e
 }
@@ -442,6 +1363,7 @@ func (e *requestExpectation) Times(i uint) Expectation {
 	defer e.unlock()
 
 	e.repeatTimes = i
+	e.times = i
 
 	return e
 }
@@ -463,6 +1385,66 @@ func (e *requestExpectation) WaitUntil(w <-chan time.Time) Expectation {
 	return e
 }
 
+// WaitUntilContext blocks the mocked return until the context is done, so the response can be released by the test.
+//
+//	ctx, release := context.WithCancel(context.Background())
+//
+//	Server.Expect(http.MethodGet, "/path").
+//		WaitUntilContext(ctx).
+//		Return("hello world!")
+//
+//	// Do something, then release the response.
+//	release()
+func (e *requestExpectation) WaitUntilContext(ctx context.Context) Expectation {
+	e.lock()
+	defer e.unlock()
+
+	e.waiter = wait.Func(func(reqCtx context.Context) error {
+		select {
+		case <-reqCtx.Done():
+			return reqCtx.Err()
+
+		case <-ctx.Done():
+			return nil
+		}
+	})
+
+	return e
+}
+
+// WaitFor blocks the mocked return until the condition is met. The condition is checked every poll interval. It panics
+// if the poll interval is not positive.
+//
+//	Server.Expect(http.MethodGet, "/path").
+//		WaitFor(func() bool { return db.HasUser(42) }, 10*time.Millisecond).
+//		Return("hello world!")
+func (e *requestExpectation) WaitFor(condition func() bool, pollInterval time.Duration) Expectation {
+	if pollInterval <= 0 {
+		panic(fmt.Errorf("could not wait for condition: poll interval must be positive, got %s", pollInterval)) // nolint: goerr113
+	}
+
+	e.lock()
+	defer e.unlock()
+
+	e.waiter = wait.Func(func(ctx context.Context) error {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+
+		for !condition() {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+
+			case <-ticker.C:
+			}
+		}
+
+		return nil
+	})
+
+	return e
+}
+
 // After sets how long to block until the call returns.
 //
 //	Server.Expect(http.MethodGet, "/path").
@@ -479,35 +1461,324 @@ func (e *requestExpectation) After(d time.Duration) Expectation {
 	return e
 }
 
+// AfterFunc sets how long to block until the call returns, depending on the number of the call, starting from 1.
+//
+//	Server.Expect(http.MethodGet, "/path").
+//		AfterFunc(func(callN uint) time.Duration {
+//			return time.Duration(callN) * 100 * time.Millisecond
+//		}).
+//		UnlimitedTimes().
+//		Return("hello world!")
+func (e *requestExpectation) AfterFunc(fn func(callN uint) time.Duration) Expectation {
+	e.lock()
+	defer e.unlock()
+
+	var calls uint32
+
+	e.waiter = wait.Func(func(ctx context.Context) error {
+		callN := atomic.AddUint32(&calls, 1)
+
+		return wait.ForDuration(fn(uint(callN))).Wait(ctx)
+	})
+
+	return e
+}
+
+// WithTimeout bounds the total time to handle the request, including the wait and the handler. When the time is up,
+// the server responds http.StatusGatewayTimeout, or the response set by ReturnOnTimeout.
+//
+//	Server.Expect(http.MethodGet, "/path").
+//		WithTimeout(time.Second).
+//		Run(slowHandler)
+func (e *requestExpectation) WithTimeout(d time.Duration) Expectation {
+	e.lock()
+	defer e.unlock()
+
+	e.timeout = d
+
+	return e
+}
+
+// ReturnOnTimeout sets the response code and body when the handling time is up, see WithTimeout.
+//
+//	Server.Expect(http.MethodGet, "/path").
+//		WithTimeout(time.Second).
+//		ReturnOnTimeout(httpmock.StatusServiceUnavailable, "try again later").
+//		Run(slowHandler)
+func (e *requestExpectation) ReturnOnTimeout(code int, body any) Expectation {
+	e.lock()
+	defer e.unlock()
+
+	e.timeoutCode = code
+	e.timeoutBody = []byte(value.String(body))
+
+	return e
+}
+
+// hasDelay checks whether the expectation has its own delay.
+func (e *requestExpectation) hasDelay() bool {
+	e.lock()
+	defer e.unlock()
+
+	return e.waiter != wait.NoWait
+}
+
 // Handle handles the HTTP request.
 func (e *requestExpectation) Handle(w http.ResponseWriter, req *http.Request, defaultHeaders map[string]string) error {
 	e.lock()
-	defer e.unlock()
+	wrappers := e.wrappers
+	headerMergeOf := e.headerMergeOf
+	e.unlock()
 
-	if err := e.waiter.Wait(req.Context()); err != nil {
-		return err
+	// The policy is read before locking the expectation, because the server locks the expectations while holding its
+	// own lock.
+	policy := HeaderMergeExpectationWins
+
+	if headerMergeOf != nil {
+		policy = headerMergeOf()
+	}
+
+	var h ExpectationHandler = ExpectationHandlerFunc(func(w http.ResponseWriter, r *http.Request, defaultHeaders map[string]string) error {
+		return e.handleRequest(w, r, defaultHeaders, policy)
+	})
+
+	for i := len(wrappers) - 1; i >= 0; i-- {
+		h = wrappers[i](h)
+	}
+
+	return h.Handle(w, req, defaultHeaders)
+}
+
+// handleSettings are the settings of the expectation to handle a request, copied while holding the lock, so the lock is
+// not held while waiting and handling, and the handlers could register new expectations, or the server could plan the
+// other requests meanwhile.
+type handleSettings struct {
+	waiter         wait.Waiter
+	handle         func(r *http.Request) ([]byte, error)
+	httpHandler    http.Handler
+	uri            matcher.Matcher
+	responseCode   int
+	responseHeader Header
+	cacheHeaders   func(now time.Time) Header
+	timeout        time.Duration
+	timeoutCode    int
+	timeoutBody    []byte
+	abortAfter     int
+}
+
+// handleSettings copies the settings to handle a request. The caller must hold the lock.
+func (e *requestExpectation) handleSettings() handleSettings {
+	return handleSettings{
+		waiter:         e.waiter,
+		handle:         e.handle,
+		httpHandler:    e.httpHandler,
+		uri:            e.requestURIMatcher,
+		responseCode:   e.responseCode,
+		responseHeader: cloneMap(e.responseHeader),
+		cacheHeaders:   e.cacheHeaders,
+		timeout:        e.timeout,
+		timeoutCode:    e.timeoutCode,
+		timeoutBody:    e.timeoutBody,
+		abortAfter:     e.abortAfter,
+	}
+}
+
+// handleRequest writes the response of the expectation.
+func (e *requestExpectation) handleRequest(w http.ResponseWriter, req *http.Request, defaultHeaders map[string]string, policy HeaderMergePolicy) (err error) {
+	e.lock()
+	c := e.handleSettings()
+	calls := e.fulfilledTimes
+
+	if e.noDefaultHeaders {
+		defaultHeaders = nil
+	}
+	e.unlock()
+
+	defer func(start time.Time) {
+		e.lock()
+		defer e.unlock()
+
+		e.handledTimes++
+		e.handleDuration += time.Since(start)
+	}(time.Now())
+
+	if c.abortAfter >= 0 {
+		aw := &abortResponseWriter{ResponseWriter: w, remain: c.abortAfter}
+		w = aw
+
+		defer func() {
+			if aw.aborted {
+				err = nil
+			}
+		}()
+	}
+
+	req = withCallNumber(withPathParams(req, c.uri), calls)
+
+	if c.httpHandler != nil {
+		return c.serveHTTPHandler(w, req, defaultHeaders, policy)
+	}
+
+	body, handled, err := c.run(req)
+	if errors.Is(err, errHandleTimeout) {
+		return c.writeTimeout(w, defaultHeaders)
 	}
 
-	body, err := e.handle(req)
 	if err != nil {
-		_ = FailResponse(w, err.Error()) //nolint: errcheck,govet
+		if handled {
+			_ = FailResponse(w, "%s", err.Error()) //nolint: errcheck
+		}
 
 		return err
 	}
 
-	for key, val := range mergeHeaders(e.responseHeader, defaultHeaders) {
-		w.Header().Set(key, val)
+	// The client is gone, there is no need to write the response.
+	if err := req.Context().Err(); err != nil {
+		return err
+	}
+
+	if len(c.responseHeader) > 0 || len(defaultHeaders) > 0 {
+		writeHeaders(w.Header(), c.responseHeader, defaultHeaders, policy)
 	}
 
-	w.WriteHeader(e.responseCode)
+	if c.cacheHeaders != nil {
+		writeCacheHeaders(w.Header(), c.cacheHeaders(time.Now()), c.responseHeader)
+	}
+
+	w.WriteHeader(c.responseCode)
 
 	_, err = w.Write(body)
 
 	return err
 }
 
+// serveHTTPHandler waits and calls the http.Handler.
+func (c handleSettings) serveHTTPHandler(w http.ResponseWriter, req *http.Request, defaultHeaders map[string]string, policy HeaderMergePolicy) error {
+	parent := req.Context()
+
+	if c.timeout > 0 {
+		ctx, cancel := context.WithTimeout(parent, c.timeout)
+		defer cancel()
+
+		req = req.WithContext(ctx)
+	}
+
+	if err := c.waiter.Wait(req.Context()); err != nil {
+		if c.timeout > 0 && errors.Is(err, context.DeadlineExceeded) && parent.Err() == nil {
+			return c.writeTimeout(w, defaultHeaders)
+		}
+
+		return err
+	}
+
+	if len(c.responseHeader) > 0 || len(defaultHeaders) > 0 {
+		writeHeaders(w.Header(), c.responseHeader, defaultHeaders, policy)
+	}
+
+	c.httpHandler.ServeHTTP(w, req)
+
+	return nil
+}
+
+// handleResult is the result of the wait and the handler.
+type handleResult struct {
+	body    []byte
+	handled bool
+	err     error
+}
+
+// run waits and calls the handler, within the timeout if any. The handled result indicates whether the handler was
+// called.
+func (c handleSettings) run(req *http.Request) ([]byte, bool, error) {
+	if c.timeout <= 0 {
+		r := runHandler(req, c.waiter, c.handle)
+
+		return r.body, r.handled, r.err
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), c.timeout)
+	defer cancel()
+
+	// The handler may still be running when the time is up, after the request is released, so it reads a private copy
+	// of the body.
+	r := withBodyCopy(req).WithContext(ctx)
+	results := make(chan handleResult, 1)
+
+	go func() {
+		results <- runHandler(r, c.waiter, c.handle)
+	}()
+
+	select {
+	case r := <-results:
+		if !r.handled && errors.Is(r.err, context.DeadlineExceeded) && req.Context().Err() == nil {
+			return nil, false, errHandleTimeout
+		}
+
+		return r.body, r.handled, r.err
+
+	case <-ctx.Done():
+		if err := req.Context().Err(); err != nil {
+			return nil, false, err
+		}
+
+		return nil, false, errHandleTimeout
+	}
+}
+
+func (c handleSettings) writeTimeout(w http.ResponseWriter, defaultHeaders map[string]string) error {
+	if len(defaultHeaders) > 0 {
+		writeHeaders(w.Header(), nil, defaultHeaders, HeaderMergeExpectationWins)
+	}
+
+	code, body := c.timeoutCode, c.timeoutBody
+
+	if code == 0 {
+		code = http.StatusGatewayTimeout
+		body = []byte(http.StatusText(http.StatusGatewayTimeout))
+	}
+
+	w.WriteHeader(code)
+
+	_, err := w.Write(body)
+
+	return err
+}
+
+// withBodyCopy returns a shallow copy of the request with a copy of its body, which is not released with the request.
+func withBodyCopy(req *http.Request) *http.Request {
+	body, err := value.GetBody(req)
+
+	c := *req
+
+	if err != nil {
+		c.Body = io.NopCloser(iotest.ErrReader(err))
+	} else {
+		c.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	return &c
+}
+
+func runHandler(req *http.Request, waiter wait.Waiter, handle func(r *http.Request) ([]byte, error)) handleResult {
+	if err := waiter.Wait(req.Context()); err != nil {
+		return handleResult{err: err}
+	}
+
+	body, err := runSafely(handle, req)
+
+	return handleResult{body: body, handled: true, err: err}
+}
+
 // newRequestExpectation creates a new request expectation.
 func newRequestExpectation(method string, requestURI any) *requestExpectation {
+	if m, ok := requestURI.(RequestMatcherFunc); ok {
+		e := newRequestExpectation(method, matchAnyURI())
+
+		e.requestMatchers = append(e.requestMatchers, requestMatcher{description: requestMatcherDescription, match: m.matchRequest})
+
+		return e
+	}
+
 	return &requestExpectation{
 		locker:            &sync.Mutex{},
 		requestMethod:     method,
@@ -515,6 +1786,8 @@ func newRequestExpectation(method string, requestURI any) *requestExpectation {
 		requestURIMatcher: matcher.Match(requestURI),
 		repeatTimes:       0,
 		waiter:            wait.NoWait,
+		abortAfter:        -1,
+		random:            rand.New(rand.NewSource(time.Now().UnixNano())), // nolint: gosec
 		handle: func(*http.Request) ([]byte, error) {
 			return nil, nil
 		},
@@ -529,21 +1802,38 @@ func matchBody(v any) *matcher.BodyMatcher {
 		return matcher.Body(v)
 	}
 
+	if matcher.HasValueMatcher(v) {
+		return matcher.Body(v)
+	}
+
 	return matcher.Body(value.String(v))
 }
 
-// mergeHeaders merges a list of headers with some defaults. If a default header appears in the given headers, it
-// will not be merged, no matter what the value is.
-func mergeHeaders(headers, defaultHeaders Header) Header {
-	result := make(Header, len(headers)+len(defaultHeaders))
+// writeHeaders writes a list of headers with some defaults. If a default header appears in the given headers, the
+// values are merged by the policy.
+func writeHeaders(w http.Header, headers, defaultHeaders Header, policy HeaderMergePolicy) {
+	if policy == HeaderMergeServerWins {
+		headers, defaultHeaders = defaultHeaders, headers
+	}
 
 	for header, val := range defaultHeaders {
-		result[textproto.CanonicalMIMEHeaderKey(header)] = val
+		w.Set(header, val)
 	}
 
 	for header, val := range headers {
-		result[textproto.CanonicalMIMEHeaderKey(header)] = val
+		if policy == HeaderMergeAppend && hasHeader(defaultHeaders, header) {
+			w.Add(header, val)
+		} else {
+			w.Set(header, val)
+		}
 	}
+}
 
-	return result
+// writeCacheHeaders writes the caching headers, except the ones that are set explicitly in the given headers.
+func writeCacheHeaders(w http.Header, cacheHeaders, headers Header) {
+	for header, val := range cacheHeaders {
+		if !hasHeader(headers, header) {
+			w.Set(header, val)
+		}
+	}
 }
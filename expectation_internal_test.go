@@ -1,7 +1,10 @@
 package httpmock
 
 import (
+	"context"
 	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
 	"regexp"
 	"sync"
 	"testing"
@@ -19,11 +22,11 @@ func TestRequestExpectation_WithHeader(t *testing.T) {
 	r := &requestExpectation{locker: &sync.Mutex{}, requestHeaderMatcher: matcher.HeaderMatcher{}}
 	r.WithHeader("foo", "bar")
 
-	assert.Equal(t, matcher.HeaderMatcher{"foo": matcher.Exact("bar")}, r.requestHeaderMatcher)
+	assert.Equal(t, matcher.HeaderMatcher{"Foo": matcher.Exact("bar")}, r.requestHeaderMatcher)
 
 	r.WithHeader("john", "doe")
 
-	assert.Equal(t, matcher.HeaderMatcher{"foo": matcher.Exact("bar"), "john": matcher.Exact("doe")}, r.requestHeaderMatcher)
+	assert.Equal(t, matcher.HeaderMatcher{"Foo": matcher.Exact("bar"), "John": matcher.Exact("doe")}, r.requestHeaderMatcher)
 }
 
 func TestRequestExpectation_WithHeaders(t *testing.T) {
@@ -32,11 +35,17 @@ func TestRequestExpectation_WithHeaders(t *testing.T) {
 	e := newRequestExpectation(MethodGet, "/")
 	e.WithHeaders(map[string]any{"foo": "bar"})
 
-	assert.Equal(t, matcher.HeaderMatcher{"foo": matcher.Exact("bar")}, e.requestHeaderMatcher)
+	assert.Equal(t, matcher.HeaderMatcher{"Foo": matcher.Exact("bar")}, e.requestHeaderMatcher)
 
 	e.WithHeader("john", "doe")
 
-	assert.Equal(t, matcher.HeaderMatcher{"foo": matcher.Exact("bar"), "john": matcher.Exact("doe")}, e.requestHeaderMatcher)
+	assert.Equal(t, matcher.HeaderMatcher{"Foo": matcher.Exact("bar"), "John": matcher.Exact("doe")}, e.requestHeaderMatcher)
+
+	// The header is replaced, no matter the casing.
+	e.WithHeader("FOO", "baz")
+
+	assert.Equal(t, matcher.HeaderMatcher{"Foo": matcher.Exact("baz"), "John": matcher.Exact("doe")}, e.requestHeaderMatcher)
+	assert.Equal(t, []string{"FOO", "john"}, e.requestHeaderNames)
 }
 
 func TestRequestExpectation_WithBody(t *testing.T) {
@@ -356,6 +365,118 @@ func TestRequestExpectation_ReturnFile(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestRequestExpectation_ReturnWeighted(t *testing.T) {
+	t.Parallel()
+
+	pick := func(seed int64) []string {
+		e := newRequestExpectation(MethodGet, "/")
+
+		e.ReturnWeighted(map[any]int{"hello": 3, "bye": 1, "never": 0}).
+			WithRandSeed(seed)
+
+		result := make([]string, 1000)
+
+		for i := range result {
+			body, err := e.handle(http.BuildRequest().Build())
+
+			assert.NoError(t, err)
+
+			result[i] = string(body)
+		}
+
+		return result
+	}
+
+	result := pick(42)
+	counts := make(map[string]int)
+
+	for _, body := range result {
+		counts[body]++
+	}
+
+	assert.Len(t, counts, 2)
+	assert.InDelta(t, 750, counts["hello"], 75)
+	assert.InDelta(t, 250, counts["bye"], 75)
+
+	// Same seed, same results.
+	assert.Equal(t, result, pick(42))
+}
+
+func TestRequestExpectation_ReturnWeighted_Panic(t *testing.T) {
+	t.Parallel()
+
+	e := newRequestExpectation(MethodGet, "/")
+
+	assert.PanicsWithError(t, "could not return weighted results: no result with positive weight", func() {
+		e.ReturnWeighted(map[any]int{"hello": 0})
+	})
+}
+
+func TestRequestExpectation_ReturnByUserAgent(t *testing.T) {
+	t.Parallel()
+
+	e := newRequestExpectation(MethodGet, "/")
+
+	e.ReturnByUserAgent(map[string]any{
+		"MyApp/":   "v0",
+		"MyApp/1.": "v1",
+		"MyApp/2.": []byte("v2"),
+		"":         "default",
+	})
+
+	testCases := []struct {
+		scenario     string
+		userAgent    string
+		expectedBody string
+	}{
+		{
+			scenario:     "longest key",
+			userAgent:    "MyApp/1.2.3 (Linux)",
+			expectedBody: "v1",
+		},
+		{
+			scenario:     "another version",
+			userAgent:    "MyApp/2.0.0",
+			expectedBody: "v2",
+		},
+		{
+			scenario:     "shorter key",
+			userAgent:    "MyApp/3.0.0",
+			expectedBody: "v0",
+		},
+		{
+			scenario:     "default",
+			userAgent:    "curl/8.0.1",
+			expectedBody: "default",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			body, err := e.handle(http.BuildRequest().WithHeader("User-Agent", tc.userAgent).Build())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedBody, string(body))
+		})
+	}
+}
+
+func TestRequestExpectation_ReturnByUserAgent_NoDefault(t *testing.T) {
+	t.Parallel()
+
+	e := newRequestExpectation(MethodGet, "/")
+
+	e.ReturnByUserAgent(map[string]any{"MyApp/": "v0"})
+
+	body, err := e.handle(http.BuildRequest().WithHeader("User-Agent", "curl/8.0.1").Build())
+
+	assert.Nil(t, body)
+	assert.EqualError(t, err, `no response for user agent: "curl/8.0.1"`)
+}
+
 func TestRequestExpectation_Handle_Success(t *testing.T) {
 	t.Parallel()
 
@@ -491,6 +612,26 @@ func TestRequestExpectation_Wait(t *testing.T) {
 				r.After(duration)
 			},
 		},
+		{
+			scenario: "context",
+			mock: func(r *requestExpectation) {
+				ctx, cancel := context.WithTimeout(context.Background(), duration)
+
+				time.AfterFunc(2*duration, cancel)
+
+				r.WaitUntilContext(ctx)
+			},
+		},
+		{
+			scenario: "condition",
+			mock: func(r *requestExpectation) {
+				readyAt := time.Now().Add(duration)
+
+				r.WaitFor(func() bool {
+					return !time.Now().Before(readyAt)
+				}, time.Millisecond)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -521,23 +662,338 @@ func TestRequestExpectation_Wait(t *testing.T) {
 	}
 }
 
-func TestMergeHeaders(t *testing.T) {
+func TestRequestExpectation_AfterFunc(t *testing.T) {
 	t.Parallel()
 
-	headers := Header{
-		"Authorization": "Bearer token",
+	var calls []uint
+
+	e := newRequestExpectation(MethodGet, "/")
+
+	e.UnlimitedTimes().
+		AfterFunc(func(callN uint) time.Duration {
+			calls = append(calls, callN)
+
+			return time.Duration(callN) * 10 * time.Millisecond
+		})
+
+	for i := 1; i <= 3; i++ {
+		w := http.MockResponseWriter(func(w *http.ResponseWriter) {
+			w.On("WriteHeader", 200)
+
+			w.On("Write", []byte(nil)).
+				Return(0, nil)
+		})(t)
+
+		startTime := time.Now()
+
+		err := e.Handle(w, http.BuildRequest().Build(), nil)
+
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(startTime), time.Duration(i)*10*time.Millisecond)
 	}
 
-	defaultHeaders := Header{
-		"Authorization": "Bearer foobar",
-		"Content-Type":  "application/json",
+	assert.Equal(t, []uint{1, 2, 3}, calls)
+}
+
+func TestRequestExpectation_RunHandler(t *testing.T) {
+	t.Parallel()
+
+	header := nethttp.Header{}
+
+	w := http.MockResponseWriter(func(w *http.ResponseWriter) {
+		w.On("Header").Return(header)
+		w.On("WriteHeader", nethttp.StatusAccepted).Once()
+		w.On("Write", []byte("hello")).Return(5, nil).Once()
+		w.On("Flush").Once()
+		w.On("Hijack").Return(nil, nil, errors.New("hijack error")).Once()
+	})(t)
+
+	e := newRequestExpectation(MethodGet, "/")
+
+	e.ReturnHeader("Content-Type", "text/plain").
+		RunHandler(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, _ *nethttp.Request) {
+			w.WriteHeader(nethttp.StatusAccepted)
+
+			_, _ = w.Write([]byte("hello")) //nolint: errcheck
+
+			w.(nethttp.Flusher).Flush()
+
+			_, _, err := w.(nethttp.Hijacker).Hijack()
+
+			assert.EqualError(t, err, "hijack error")
+		}))
+
+	err := e.Handle(w, http.BuildRequest().Build(), map[string]string{"X-Default": "1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, nethttp.Header{"Content-Type": {"text/plain"}, "X-Default": {"1"}}, header)
+}
+
+func TestRequestExpectation_RunHandler_Timeout(t *testing.T) {
+	t.Parallel()
+
+	e := newRequestExpectation(MethodGet, "/")
+
+	e.WithTimeout(10 * time.Millisecond).
+		After(time.Hour).
+		RunHandler(nethttp.NotFoundHandler())
+
+	w := httptest.NewRecorder()
+
+	err := e.Handle(w, http.BuildRequest().Build(), nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, nethttp.StatusGatewayTimeout, w.Code)
+}
+
+func TestRequestExpectation_Run_ReplacesRunHandler(t *testing.T) {
+	t.Parallel()
+
+	e := newRequestExpectation(MethodGet, "/")
+
+	e.RunHandler(nethttp.NotFoundHandler()).
+		Return("hello")
+
+	w := httptest.NewRecorder()
+
+	err := e.Handle(w, http.BuildRequest().Build(), nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, nethttp.StatusOK, w.Code)
+	assert.Equal(t, "hello", w.Body.String())
+}
+
+func TestRequestExpectation_WithTimeout(t *testing.T) {
+	t.Parallel()
+
+	const timeout = 20 * time.Millisecond
+
+	slow := func(r *nethttp.Request) ([]byte, error) {
+		<-r.Context().Done()
+
+		time.Sleep(timeout)
+
+		return []byte("slow"), nil
 	}
 
-	actual := mergeHeaders(headers, defaultHeaders)
-	expected := Header{
-		"Authorization": "Bearer token",
-		"Content-Type":  "application/json",
+	testCases := []struct {
+		scenario     string
+		mock         func(e *requestExpectation)
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			scenario: "in time",
+			mock: func(e *requestExpectation) {
+				e.Return("hello")
+			},
+			expectedCode: nethttp.StatusOK,
+			expectedBody: "hello",
+		},
+		{
+			scenario: "slow handler",
+			mock: func(e *requestExpectation) {
+				e.Run(slow)
+			},
+			expectedCode: nethttp.StatusGatewayTimeout,
+			expectedBody: "Gateway Timeout",
+		},
+		{
+			scenario: "slow wait",
+			mock: func(e *requestExpectation) {
+				e.After(time.Hour).Return("hello")
+			},
+			expectedCode: nethttp.StatusGatewayTimeout,
+			expectedBody: "Gateway Timeout",
+		},
+		{
+			scenario: "custom response",
+			mock: func(e *requestExpectation) {
+				e.ReturnOnTimeout(nethttp.StatusServiceUnavailable, "try again later").
+					Run(slow)
+			},
+			expectedCode: nethttp.StatusServiceUnavailable,
+			expectedBody: "try again later",
+		},
 	}
 
-	assert.Equal(t, expected, actual)
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			e := newRequestExpectation(MethodGet, "/")
+
+			e.WithTimeout(timeout)
+			tc.mock(e)
+
+			w := httptest.NewRecorder()
+
+			err := e.Handle(w, http.BuildRequest().Build(), map[string]string{"Content-Type": "text/plain"})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedCode, w.Code)
+			assert.Equal(t, tc.expectedBody, w.Body.String())
+			assert.Equal(t, "text/plain", w.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestRequestExpectation_WithTimeout_ReturnWeighted(t *testing.T) {
+	t.Parallel()
+
+	e := newRequestExpectation(MethodGet, "/")
+
+	e.WithTimeout(time.Second).
+		ReturnWeighted(map[any]int{"hello": 1, "bye": 1})
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			assert.NoError(t, e.Handle(httptest.NewRecorder(), http.BuildRequest().Build(), nil))
+		}()
+
+		go func(seed int64) {
+			defer wg.Done()
+
+			e.WithRandSeed(seed)
+		}(int64(i))
+	}
+
+	wg.Wait()
+}
+
+func TestRequestExpectation_WaitFor_InvalidPollInterval(t *testing.T) {
+	t.Parallel()
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		e := newRequestExpectation(MethodGet, "/")
+
+		assert.Panics(t, func() {
+			e.WaitFor(func() bool { return true }, interval)
+		})
+	}
+}
+
+func TestRequestExpectation_WithTimeout_RequestCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	e := newRequestExpectation(MethodGet, "/")
+
+	e.WithTimeout(time.Hour).
+		After(time.Hour)
+
+	err := e.Handle(http.MockResponseWriter()(t), http.BuildRequest().Build().WithContext(ctx), nil)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRequestExpectation_Wait_RequestCanceled(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		mock     func(e *requestExpectation)
+	}{
+		{
+			scenario: "context",
+			mock: func(r *requestExpectation) {
+				r.WaitUntilContext(context.Background())
+			},
+		},
+		{
+			scenario: "condition",
+			mock: func(r *requestExpectation) {
+				r.WaitFor(func() bool { return false }, time.Millisecond)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+
+			e := newRequestExpectation(MethodGet, "/")
+
+			tc.mock(e)
+
+			err := e.Handle(http.MockResponseWriter()(t), http.BuildRequest().Build().WithContext(ctx), nil)
+
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+		})
+	}
+}
+
+func TestWriteHeaders(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		policy   HeaderMergePolicy
+		expected nethttp.Header
+	}{
+		{
+			scenario: "expectation wins",
+			policy:   HeaderMergeExpectationWins,
+			expected: nethttp.Header{
+				"Authorization": {"Bearer token"},
+				"Content-Type":  {"application/json"},
+				"X-Id":          {"42"},
+			},
+		},
+		{
+			scenario: "server wins",
+			policy:   HeaderMergeServerWins,
+			expected: nethttp.Header{
+				"Authorization": {"Bearer foobar"},
+				"Content-Type":  {"application/json"},
+				"X-Id":          {"42"},
+			},
+		},
+		{
+			scenario: "append",
+			policy:   HeaderMergeAppend,
+			expected: nethttp.Header{
+				"Authorization": {"Bearer foobar", "Bearer token"},
+				"Content-Type":  {"application/json"},
+				"X-Id":          {"42"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			headers := Header{
+				"authorization": "Bearer token",
+				"X-ID":          "42",
+			}
+
+			defaultHeaders := Header{
+				"Authorization": "Bearer foobar",
+				"Content-Type":  "application/json",
+			}
+
+			actual := nethttp.Header{}
+
+			writeHeaders(actual, headers, defaultHeaders, tc.policy)
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
 }
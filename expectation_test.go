@@ -0,0 +1,121 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/planner"
+)
+
+// fabricatePlanner is a planner that creates an expectation for every request.
+type fabricatePlanner struct{}
+
+func (fabricatePlanner) IsEmpty() bool { return false }
+
+func (fabricatePlanner) Expect(planner.Expectation) {}
+
+func (fabricatePlanner) Plan(req *http.Request) (planner.Expectation, error) {
+	e := httpmock.NewExpectation(req.Method, req.URL.Path)
+
+	e.ReturnCode(httpmock.StatusAccepted).
+		Returnf("%s %s", req.Method, req.URL.Path)
+
+	return e, nil
+}
+
+func (fabricatePlanner) Remain() []planner.Expectation { return nil }
+
+func (fabricatePlanner) Reset() {}
+
+func TestNewExpectation(t *testing.T) {
+	t.Parallel()
+
+	e := httpmock.NewExpectation(httpmock.MethodPost, "/users")
+
+	e.WithHeader("Authorization", "Bearer token").
+		ReturnHeader("Content-Type", "application/json").
+		ReturnCode(httpmock.StatusCreated).
+		Return(`{"id":42}`)
+
+	assert.Equal(t, httpmock.MethodPost, e.Method())
+	assert.Equal(t, "/users", e.URIMatcher().Expected())
+	assert.Equal(t, "Bearer token", e.HeaderMatcher()["Authorization"].Expected())
+	assert.Equal(t, uint(1), e.RemainTimes())
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(httpmock.MethodPost, "/users", nil)
+
+	err := e.Handle(w, r, map[string]string{"X-Default": "default"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, httpmock.StatusCreated, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "default", w.Header().Get("X-Default"))
+	assert.Equal(t, `{"id":42}`, w.Body.String())
+}
+
+func TestNewExpectation_CustomPlanner(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer().WithPlanner(fabricatePlanner{})
+
+	defer s.Close()
+
+	code, _, body, _ := doRequest(t, s.URL(), http.MethodGet, "/anything", nil, nil, 0)
+
+	assert.Equal(t, httpmock.StatusAccepted, code)
+	assert.Equal(t, "GET /anything", string(body))
+}
+
+func TestExpectation_Wrap(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+
+	record := func(name string) func(next httpmock.ExpectationHandler) httpmock.ExpectationHandler {
+		return func(next httpmock.ExpectationHandler) httpmock.ExpectationHandler {
+			return httpmock.ExpectationHandlerFunc(func(w http.ResponseWriter, r *http.Request, h map[string]string) error {
+				calls = append(calls, name)
+
+				return next.Handle(w, r, h)
+			})
+		}
+	}
+
+	s := httpmock.NewServer()
+	defer s.Close()
+
+	s.ExpectGet("/").
+		Return("hello world!").
+		Wrap(record("outer")).
+		Wrap(record("inner")).
+		Wrap(func(next httpmock.ExpectationHandler) httpmock.ExpectationHandler {
+			return httpmock.ExpectationHandlerFunc(func(w http.ResponseWriter, r *http.Request, h map[string]string) error {
+				if r.Header.Get("X-Fail") != "" {
+					w.WriteHeader(httpmock.StatusServiceUnavailable)
+
+					return nil
+				}
+
+				return next.Handle(w, r, h)
+			})
+		}).
+		Twice()
+
+	code, _, body, _ := httpmock.DoRequest(t, httpmock.MethodGet, s.URL()+"/", httpmock.Header{"X-Fail": "1"}, nil)
+
+	assert.Equal(t, httpmock.StatusServiceUnavailable, code)
+	assert.Empty(t, body)
+
+	code, _, body, _ = httpmock.DoRequest(t, httpmock.MethodGet, s.URL()+"/", nil, nil)
+
+	assert.Equal(t, httpmock.StatusOK, code)
+	assert.Equal(t, "hello world!", string(body))
+
+	assert.Equal(t, []string{"outer", "inner", "outer", "inner"}, calls)
+	assert.NoError(t, s.ExpectationsWereMet())
+}
@@ -0,0 +1,88 @@
+package httpmock
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FailureLogger is a test that the interactions are logged to when it fails, see Server.LogOnFailure. It is satisfied
+// by *testing.T.
+type FailureLogger interface {
+	Cleanup(f func())
+	Failed() bool
+	Log(args ...any)
+}
+
+// failureLog buffers the interactions of a test, see Server.LogOnFailure.
+type failureLog struct {
+	interactions []string
+}
+
+// LogOnFailure buffers the requests received by the server and their responses while the test runs, and logs them with
+// t.Log when the test finishes, only if it failed, so the output of the passing tests stays clean. The server could be
+// shared by many tests, each of them gets the interactions received while it runs.
+//
+// The failure is checked in a cleanup of the test, so the failures reported by the cleanups that run after it are not
+// detected. With httpmock.New, call it in the mock function to detect the unmet expectations too.
+//
+//	srv := httpmock.New(func(s *httpmock.Server) {
+//		s.LogOnFailure(t)
+//
+//		s.ExpectGet("/users")
+//	})(t)
+func (s *Server) LogOnFailure(t FailureLogger) *Server {
+	l := &failureLog{}
+
+	s.mu.Lock()
+	s.failureLogs = append(s.failureLogs, l)
+	s.mu.Unlock()
+
+	t.Cleanup(func() {
+		s.mu.Lock()
+
+		for i, other := range s.failureLogs {
+			if other == l {
+				s.failureLogs = append(s.failureLogs[:i], s.failureLogs[i+1:]...)
+
+				break
+			}
+		}
+
+		interactions := l.interactions
+
+		s.mu.Unlock()
+
+		if !t.Failed() {
+			return
+		}
+
+		if len(interactions) == 0 {
+			t.Log("httpmock: no request was received")
+
+			return
+		}
+
+		t.Log("httpmock: interactions:\n" + strings.Join(interactions, "\n"))
+	})
+
+	return s
+}
+
+// logInteraction adds the request and the response to the failure logs.
+func (s *Server) logInteraction(seq int, req, resp []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.failureLogs) == 0 {
+		return
+	}
+
+	interaction := fmt.Sprintf("#%d\n%s\n\n%s\n", seq,
+		strings.ReplaceAll(strings.TrimRight(string(req), "\r\n"), "\r\n", "\n"),
+		strings.ReplaceAll(strings.TrimRight(string(resp), "\r\n"), "\r\n", "\n"),
+	)
+
+	for _, l := range s.failureLogs {
+		l.interactions = append(l.interactions, interaction)
+	}
+}
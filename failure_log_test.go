@@ -0,0 +1,87 @@
+package httpmock_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+)
+
+type failureLoggerT struct {
+	failed   bool
+	logs     []string
+	cleanups []func()
+}
+
+func (t *failureLoggerT) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
+
+func (t *failureLoggerT) Failed() bool {
+	return t.failed
+}
+
+func (t *failureLoggerT) Log(args ...any) {
+	t.logs = append(t.logs, fmt.Sprint(args...))
+}
+
+func (t *failureLoggerT) finish() {
+	for i := len(t.cleanups) - 1; i >= 0; i-- {
+		t.cleanups[i]()
+	}
+}
+
+func TestServer_LogOnFailure(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+	defer s.Close()
+
+	s.ExpectPost("/users").
+		ReturnCode(httpmock.StatusCreated).
+		Return(`{"id":42}`).
+		UnlimitedTimes()
+
+	passed := &failureLoggerT{}
+	failed := &failureLoggerT{failed: true}
+
+	s.LogOnFailure(passed)
+
+	doRequest(t, s.URL(), http.MethodPost, "/users", nil, []byte(`{"name":"john"}`), 0)
+
+	s.LogOnFailure(failed)
+
+	doRequest(t, s.URL(), http.MethodPost, "/users", nil, []byte(`{"name":"jane"}`), 0)
+
+	passed.finish()
+	failed.finish()
+
+	// The interactions after the tests finished are not logged.
+	doRequest(t, s.URL(), http.MethodPost, "/users", nil, []byte(`{"name":"jim"}`), 0)
+
+	expected := "httpmock: interactions:\n" +
+		"#2\n" +
+		"POST /users HTTP/1.1\nAccept-Encoding: gzip\nContent-Length: 15\nUser-Agent: Go-http-client/1.1\n\n{\"name\":\"jane\"}\n" +
+		"\n" +
+		"HTTP/1.1 201 Created\n\n{\"id\":42}\n"
+
+	assert.Empty(t, passed.logs)
+	assert.Equal(t, []string{expected}, failed.logs)
+}
+
+func TestServer_LogOnFailure_NoRequest(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+	defer s.Close()
+
+	failed := &failureLoggerT{failed: true}
+
+	s.LogOnFailure(failed)
+	failed.finish()
+
+	assert.Equal(t, []string{"httpmock: no request was received"}, failed.logs)
+}
@@ -0,0 +1,40 @@
+package httpmock
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// Fingerprint returns a stable hash of the registered expectations, including the background ones, in hex, to detect
+// whether a shared setup is changed, for example, by another test. The hash is computed from the descriptions of the
+// expectations, see Expectation.MarshalJSON, without their call counts, so it does not change when the requests are
+// received. The custom matchers and the dynamic responses are opaque, so the changes of their logic are not detected.
+//
+//	fp := srv.Fingerprint()
+//
+//	// Your tests.
+//
+//	assert.Equal(t, fp, srv.Fingerprint(), "the expectations were changed")
+func (s *Server) Fingerprint() string {
+	s.mu.Lock()
+
+	descriptions := make([]ExpectationDescription, 0, len(s.expectations)+len(s.background))
+
+	for _, expectations := range [][]*requestExpectation{s.expectations, s.background} {
+		for _, e := range expectations {
+			d := e.describe()
+
+			d.Fulfilled, d.Remaining = 0, 0
+
+			descriptions = append(descriptions, d)
+		}
+	}
+
+	s.mu.Unlock()
+
+	b, _ := json.Marshal(descriptions) // nolint: errchkjson // The descriptions are always marshaled.
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:])
+}
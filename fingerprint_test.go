@@ -0,0 +1,61 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestServer_Fingerprint(t *testing.T) {
+	t.Parallel()
+
+	setup := func(s *httpmock.Server) {
+		s.ExpectGet("/users/42").
+			WithHeader("Authorization", "Bearer token").
+			Return(`{"id":42}`).
+			Twice()
+
+		s.ExpectBackground(httpmock.MethodGet, "/healthz").
+			Return(`{"status":"ok"}`)
+	}
+
+	srv := httpmock.MockServer(setup)
+	other := httpmock.MockServer(setup)
+
+	defer srv.Close()
+	defer other.Close()
+
+	fp := srv.Fingerprint()
+
+	assert.Len(t, fp, 64)
+	assert.Equal(t, fp, other.Fingerprint())
+
+	// The requests do not change the fingerprint.
+	doRequest(t, srv.URL(), http.MethodGet, "/users/42", Header{"Authorization": "Bearer token"}, nil, 0)
+	doRequest(t, srv.URL(), http.MethodGet, "/healthz", nil, nil, 0)
+
+	assert.Equal(t, fp, srv.Fingerprint())
+
+	// A new expectation changes the fingerprint.
+	other.ExpectGet("/users/43")
+
+	assert.NotEqual(t, fp, other.Fingerprint())
+
+	// A changed background expectation changes the fingerprint.
+	changed := httpmock.MockServer(func(s *httpmock.Server) {
+		s.ExpectGet("/users/42").
+			WithHeader("Authorization", "Bearer token").
+			Return(`{"id":42}`).
+			Twice()
+
+		s.ExpectBackground(httpmock.MethodGet, "/healthz").
+			Return(`{"status":"down"}`)
+	})
+
+	defer changed.Close()
+
+	assert.NotEqual(t, fp, changed.Fingerprint())
+}
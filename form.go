@@ -0,0 +1,123 @@
+package httpmock
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	"go.nhat.io/httpmock/matcher"
+	"go.nhat.io/httpmock/value"
+)
+
+// WithFormField expects the url-encoded body of the request to have the form field. A number or a boolean is compared
+// with the parsed value of the field, so 2 matches "2" and "02", and true matches "true" and "1". Otherwise, the value
+// could be a string, a []byte, or a Matcher.
+//
+//	Server.Expect(httpmock.MethodPost, "/search").
+//		WithFormField("page", 2).
+//		WithFormField("exact", true)
+func (e *requestExpectation) WithFormField(field string, expected any) Expectation {
+	match := formValueMatcher(expected)
+
+	return e.withRequestMatcher(fmt.Sprintf("form field %q with %s", field, describeFormValue(expected)), func(r *http.Request) error {
+		body, err := value.PeekBody(r)
+		if err != nil {
+			return fmt.Errorf("could not read form: %w", err)
+		}
+
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return fmt.Errorf("could not parse form: %w", err)
+		}
+
+		values, ok := form[field]
+		if !ok {
+			return fmt.Errorf("form field %q expected, not received", field) // nolint: goerr113
+		}
+
+		return match(field, values[0])
+	})
+}
+
+// formValueMatcher returns a function that matches the value of a form field with the expected value, coerced to the
+// type of the expectation.
+func formValueMatcher(expected any) func(field, actual string) error {
+	v := reflect.ValueOf(expected)
+
+	//nolint: exhaustive
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return typedFormValueMatcher(expected, "an integer", func(actual string) (bool, error) {
+			i, err := strconv.ParseInt(actual, 10, 64)
+
+			return i == v.Int(), err
+		})
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return typedFormValueMatcher(expected, "an unsigned integer", func(actual string) (bool, error) {
+			u, err := strconv.ParseUint(actual, 10, 64)
+
+			return u == v.Uint(), err
+		})
+
+	case reflect.Float32, reflect.Float64:
+		return typedFormValueMatcher(expected, "a number", func(actual string) (bool, error) {
+			f, err := strconv.ParseFloat(actual, 64)
+
+			return f == v.Float(), err
+		})
+
+	case reflect.Bool:
+		return typedFormValueMatcher(expected, "a boolean", func(actual string) (bool, error) {
+			b, err := strconv.ParseBool(actual)
+
+			return b == v.Bool(), err
+		})
+	}
+
+	m := matcher.Match(expected)
+
+	return func(field, actual string) error {
+		matched, err := m.Match(actual)
+		if err != nil {
+			return fmt.Errorf("could not match form field %q: %w", field, err)
+		}
+
+		if !matched {
+			return fmt.Errorf("form field %q with value %q expected, %q received", field, m.Expected(), actual) // nolint: goerr113
+		}
+
+		return nil
+	}
+}
+
+func typedFormValueMatcher(expected any, typeName string, match func(actual string) (bool, error)) func(field, actual string) error {
+	return func(field, actual string) error {
+		matched, err := match(actual)
+		if err != nil {
+			return fmt.Errorf("form field %q with %T value %v expected, %q received is not %s", field, expected, expected, actual, typeName) // nolint: goerr113
+		}
+
+		if !matched {
+			return fmt.Errorf("form field %q with %T value %v expected, %q received", field, expected, expected, actual) // nolint: goerr113
+		}
+
+		return nil
+	}
+}
+
+// describeFormValue describes the expected value of a form field, in the same way as the errors of the matcher.
+func describeFormValue(expected any) string {
+	//nolint: exhaustive
+	switch reflect.ValueOf(expected).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64,
+		reflect.Bool:
+		return fmt.Sprintf("%T value %v", expected, expected)
+	}
+
+	return fmt.Sprintf("value %q", matcher.Match(expected).Expected())
+}
@@ -0,0 +1,142 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestServer_WithFormField(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		field         string
+		expected      any
+		body          string
+		expectedError string
+	}{
+		{
+			scenario: "int",
+			field:    "page",
+			expected: 2,
+			body:     "page=02&size=10",
+		},
+		{
+			scenario:      "int mismatched",
+			field:         "page",
+			expected:      2,
+			body:          "page=3",
+			expectedError: `Error: form field "page" with int value 2 expected, "3" received`,
+		},
+		{
+			scenario:      "not an int",
+			field:         "page",
+			expected:      2,
+			body:          "page=two",
+			expectedError: `Error: form field "page" with int value 2 expected, "two" received is not an integer`,
+		},
+		{
+			scenario: "uint",
+			field:    "size",
+			expected: uint8(10),
+			body:     "page=2&size=10",
+		},
+		{
+			scenario:      "not an uint",
+			field:         "size",
+			expected:      uint(10),
+			body:          "size=-10",
+			expectedError: `Error: form field "size" with uint value 10 expected, "-10" received is not an unsigned integer`,
+		},
+		{
+			scenario: "float",
+			field:    "price",
+			expected: 9.5,
+			body:     "price=9.50",
+		},
+		{
+			scenario:      "float mismatched",
+			field:         "price",
+			expected:      9.5,
+			body:          "price=9.49",
+			expectedError: `Error: form field "price" with float64 value 9.5 expected, "9.49" received`,
+		},
+		{
+			scenario: "bool",
+			field:    "exact",
+			expected: true,
+			body:     "exact=1",
+		},
+		{
+			scenario:      "not a bool",
+			field:         "exact",
+			expected:      false,
+			body:          "exact=no",
+			expectedError: `Error: form field "exact" with bool value false expected, "no" received is not a boolean`,
+		},
+		{
+			scenario: "string",
+			field:    "q",
+			expected: "john doe",
+			body:     "q=john+doe",
+		},
+		{
+			scenario:      "string mismatched",
+			field:         "q",
+			expected:      "john doe",
+			body:          "q=jane",
+			expectedError: `Error: form field "q" with value "john doe" expected, "jane" received`,
+		},
+		{
+			scenario: "matcher",
+			field:    "q",
+			expected: httpmock.RegexPattern(`^john`),
+			body:     "q=john+doe",
+		},
+		{
+			scenario:      "missing",
+			field:         "page",
+			expected:      1,
+			body:          "size=10",
+			expectedError: `Error: form field "page" expected, not received`,
+		},
+		{
+			scenario:      "invalid form",
+			field:         "page",
+			expected:      1,
+			body:          "page=1;size=10",
+			expectedError: `Error: could not parse form: invalid semicolon separator in query`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			testingT := T()
+
+			s := httpmock.NewServer().WithTest(testingT)
+			defer s.Close()
+
+			s.ExpectPost("/search").
+				WithFormField(tc.field, tc.expected).
+				ReturnCode(http.StatusNoContent)
+
+			header := httpmock.Header{"Content-Type": "application/x-www-form-urlencoded"}
+			code, _, _, _ := doRequest(t, s.URL(), http.MethodPost, "/search", header, []byte(tc.body), 0)
+
+			if tc.expectedError == "" {
+				assert.Equal(t, http.StatusNoContent, code)
+				assert.Empty(t, testingT.String())
+			} else {
+				assert.Equal(t, http.StatusInternalServerError, code)
+				assert.Contains(t, testingT.String(), tc.expectedError)
+			}
+		})
+	}
+}
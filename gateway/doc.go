@@ -0,0 +1,3 @@
+// Package gateway provides functionalities for mocking the JSON-over-HTTP services generated from protobuf, for example,
+// by grpc-gateway.
+package gateway
@@ -0,0 +1,49 @@
+package gateway
+
+import (
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"go.nhat.io/httpmock/matcher"
+	"go.nhat.io/httpmock/must"
+	"go.nhat.io/httpmock/value"
+)
+
+var _ matcher.Matcher = (*MessageMatcher)(nil)
+
+// MessageMatcher matches a protojson body with a message. The bodies are unmarshalled before comparing, so the order
+// of the fields, the default values, and the json names or the proto names of the fields do not matter.
+type MessageMatcher struct {
+	expected proto.Message
+	json     string
+}
+
+// Expected returns the expected message in protojson.
+func (m MessageMatcher) Expected() string {
+	return m.json
+}
+
+// Match determines if the actual body is the same message as the expected one.
+func (m MessageMatcher) Match(actual any) (bool, error) {
+	msg := m.expected.ProtoReflect().New().Interface()
+
+	if err := protojson.Unmarshal([]byte(value.String(actual)), msg); err != nil {
+		return false, err
+	}
+
+	return proto.Equal(m.expected, msg), nil
+}
+
+// Message creates a new MessageMatcher.
+//
+//	Server.Expect(httpmock.MethodPost, "/v1/users").
+//		WithBody(gateway.Message(&userv1.CreateUserRequest{Name: "john"}))
+func Message(expected proto.Message) *MessageMatcher {
+	json, err := protojson.Marshal(expected)
+	must.NotFail(err)
+
+	return &MessageMatcher{
+		expected: expected,
+		json:     string(json),
+	}
+}
@@ -0,0 +1,128 @@
+package gateway
+
+import (
+	"fmt"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/must"
+)
+
+// Service mocks the methods of a protobuf service that are served as JSON over HTTP. The request and the response
+// messages are marshaled with protojson, like grpc-gateway does.
+type Service struct {
+	server *httpmock.Server
+	desc   protoreflect.ServiceDescriptor
+
+	mu     sync.Mutex
+	routes map[protoreflect.Name]route
+}
+
+type route struct {
+	method string
+	path   string
+}
+
+// NewService creates a new service on the server. The methods are routed to POST /<package>.<Service>/<Method> by
+// default, like the unbound methods of grpc-gateway, see WithRoute.
+//
+//	srv := httpmock.NewServer()
+//	svc := gateway.NewService(srv, greeterv1.File_greeter_v1_greeter_proto.Services().ByName("Greeter"))
+func NewService(s *httpmock.Server, desc protoreflect.ServiceDescriptor) *Service {
+	return &Service{
+		server: s,
+		desc:   desc,
+		routes: make(map[protoreflect.Name]route),
+	}
+}
+
+// WithRoute routes the method of the service to the http method and the path, for example, the ones of the
+// google.api.http annotation. It panics if the service does not have the method.
+//
+//	svc.WithRoute("GetUser", httpmock.MethodGet, "/v1/users/42")
+func (s *Service) WithRoute(rpc, method, path string) *Service {
+	md := s.method(rpc)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.routes[md.Name()] = route{method: method, path: path}
+
+	return s
+}
+
+// Expect expects a call of the method with the request message. The request body is not matched if the message is nil,
+// for example, when the method is routed to GET. It panics if the service does not have the method, or the message is
+// not the input of the method.
+//
+//	svc.Expect("SayHello", &greeterv1.HelloRequest{Name: "john"}).
+//		ReturnMessage(&greeterv1.HelloReply{Message: "hello john"})
+func (s *Service) Expect(rpc string, req proto.Message) *Expectation {
+	md := s.method(rpc)
+	r := s.route(md)
+
+	e := s.server.Expect(r.method, r.path)
+
+	if req != nil {
+		mustBeMessage(req, md.Input())
+
+		e.WithBody(Message(req))
+	}
+
+	return &Expectation{Expectation: e, desc: md}
+}
+
+func (s *Service) method(rpc string) protoreflect.MethodDescriptor {
+	md := s.desc.Methods().ByName(protoreflect.Name(rpc))
+	if md == nil {
+		panic(fmt.Errorf("could not find method %q of service %q", rpc, s.desc.FullName())) // nolint: goerr113
+	}
+
+	return md
+}
+
+func (s *Service) route(md protoreflect.MethodDescriptor) route {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.routes[md.Name()]; ok {
+		return r
+	}
+
+	return route{
+		method: httpmock.MethodPost,
+		path:   fmt.Sprintf("/%s/%s", s.desc.FullName(), md.Name()),
+	}
+}
+
+// Expectation is an expectation of a method of a service.
+type Expectation struct {
+	httpmock.Expectation
+
+	desc protoreflect.MethodDescriptor
+}
+
+// ReturnMessage marshals the response message with protojson and uses it as the result to return to client, with the
+// application/json content type. It panics if the message is not the output of the method.
+//
+//	svc.Expect("SayHello", &greeterv1.HelloRequest{Name: "john"}).
+//		ReturnMessage(&greeterv1.HelloReply{Message: "hello john"})
+func (e *Expectation) ReturnMessage(resp proto.Message) httpmock.Expectation {
+	mustBeMessage(resp, e.desc.Output())
+
+	body, err := protojson.Marshal(resp)
+	must.NotFail(err)
+
+	return e.ReturnHeader("Content-Type", "application/json").
+		Return(body)
+}
+
+func mustBeMessage(m proto.Message, desc protoreflect.MessageDescriptor) {
+	if actual := m.ProtoReflect().Descriptor().FullName(); actual != desc.FullName() {
+		panic(fmt.Errorf("message %q expected, %q received", desc.FullName(), actual)) // nolint: goerr113
+	}
+}
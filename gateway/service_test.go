@@ -0,0 +1,214 @@
+package gateway_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/gateway"
+)
+
+func greeterFile(t *testing.T) protoreflect.FileDescriptor {
+	t.Helper()
+
+	stringField := func(name, jsonName string, number int32) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(jsonName),
+			Number:   proto.Int32(number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		}
+	}
+
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("greeter/v1/greeter.proto"),
+		Package: proto.String("greeter.v1"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name:  proto.String("HelloRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{stringField("first_name", "firstName", 1)},
+			},
+			{
+				Name:  proto.String("HelloReply"),
+				Field: []*descriptorpb.FieldDescriptorProto{stringField("message", "message", 1)},
+			},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{
+			{
+				Name: proto.String("Greeter"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					{
+						Name:       proto.String("SayHello"),
+						InputType:  proto.String(".greeter.v1.HelloRequest"),
+						OutputType: proto.String(".greeter.v1.HelloReply"),
+					},
+					{
+						Name:       proto.String("GetGreeting"),
+						InputType:  proto.String(".greeter.v1.HelloRequest"),
+						OutputType: proto.String(".greeter.v1.HelloReply"),
+					},
+				},
+			},
+		},
+	}, new(protoregistry.Files))
+	require.NoError(t, err)
+
+	return fd
+}
+
+func newMessage(fd protoreflect.FileDescriptor, name protoreflect.Name, field protoreflect.Name, value string) proto.Message {
+	md := fd.Messages().ByName(name)
+	msg := dynamicpb.NewMessage(md)
+
+	if value != "" {
+		msg.Set(md.Fields().ByName(field), protoreflect.ValueOfString(value))
+	}
+
+	return msg
+}
+
+func TestService_Expect(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario       string
+		body           string
+		expectedCode   int
+		expectedBody   string
+		expectedHeader httpmock.Header
+	}{
+		{
+			scenario:       "json name",
+			body:           `{"firstName":"john"}`,
+			expectedCode:   http.StatusOK,
+			expectedBody:   `{"message":"hello john"}`,
+			expectedHeader: httpmock.Header{"Content-Type": "application/json"},
+		},
+		{
+			scenario:       "proto name",
+			body:           `{"first_name": "john"}`,
+			expectedCode:   http.StatusOK,
+			expectedBody:   `{"message":"hello john"}`,
+			expectedHeader: httpmock.Header{"Content-Type": "application/json"},
+		},
+		{
+			scenario:     "mismatched",
+			body:         `{"firstName":"jane"}`,
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			scenario:     "invalid json",
+			body:         `{"firstName":`,
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			fd := greeterFile(t)
+
+			srv := httpmock.NewServer()
+			defer srv.Close()
+
+			gateway.NewService(srv, fd.Services().ByName("Greeter")).
+				Expect("SayHello", newMessage(fd, "HelloRequest", "first_name", "john")).
+				ReturnMessage(newMessage(fd, "HelloReply", "message", "hello john"))
+
+			code, headers, body, _ := httpmock.DoRequest(t, httpmock.MethodPost, srv.URL()+"/greeter.v1.Greeter/SayHello", nil, []byte(tc.body))
+
+			assert.Equal(t, tc.expectedCode, code)
+
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, string(body))
+			}
+
+			httpmock.AssertHeaderContains(t, headers, tc.expectedHeader)
+		})
+	}
+}
+
+func TestService_WithRoute(t *testing.T) {
+	t.Parallel()
+
+	fd := greeterFile(t)
+
+	srv := httpmock.NewServer()
+	defer srv.Close()
+
+	gateway.NewService(srv, fd.Services().ByName("Greeter")).
+		WithRoute("GetGreeting", httpmock.MethodGet, "/v1/greeting").
+		Expect("GetGreeting", nil).
+		ReturnMessage(newMessage(fd, "HelloReply", "message", "hello"))
+
+	code, _, body, _ := httpmock.DoRequest(t, httpmock.MethodGet, srv.URL()+"/v1/greeting", nil, nil)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"message":"hello"}`, string(body))
+	assert.NoError(t, srv.ExpectationsWereMet())
+}
+
+func TestService_Panic(t *testing.T) {
+	t.Parallel()
+
+	fd := greeterFile(t)
+
+	srv := httpmock.NewServer()
+	defer srv.Close()
+
+	svc := gateway.NewService(srv, fd.Services().ByName("Greeter"))
+
+	assert.PanicsWithError(t, `could not find method "Unknown" of service "greeter.v1.Greeter"`, func() {
+		svc.Expect("Unknown", nil)
+	})
+
+	assert.PanicsWithError(t, `could not find method "Unknown" of service "greeter.v1.Greeter"`, func() {
+		svc.WithRoute("Unknown", httpmock.MethodGet, "/")
+	})
+
+	assert.PanicsWithError(t, `message "greeter.v1.HelloRequest" expected, "greeter.v1.HelloReply" received`, func() {
+		svc.Expect("SayHello", newMessage(fd, "HelloReply", "message", "hello"))
+	})
+
+	assert.PanicsWithError(t, `message "greeter.v1.HelloReply" expected, "greeter.v1.HelloRequest" received`, func() {
+		svc.Expect("GetGreeting", nil).
+			ReturnMessage(newMessage(fd, "HelloRequest", "first_name", "john"))
+	})
+}
+
+func TestMessageMatcher(t *testing.T) {
+	t.Parallel()
+
+	fd := greeterFile(t)
+	m := gateway.Message(newMessage(fd, "HelloRequest", "first_name", "john"))
+
+	assert.JSONEq(t, `{"firstName":"john"}`, m.Expected())
+
+	matched, err := m.Match(`{"first_name":"john"}`)
+
+	assert.True(t, matched)
+	assert.NoError(t, err)
+
+	matched, err = m.Match([]byte(`{"firstName":"jane"}`))
+
+	assert.False(t, matched)
+	assert.NoError(t, err)
+
+	matched, err = m.Match(`{"unknown":"john"}`)
+
+	assert.False(t, matched)
+	assert.Error(t, err)
+}
@@ -6,6 +6,7 @@ require (
 	github.com/stretchr/testify v1.10.0
 	go.nhat.io/matcher/v2 v2.0.0
 	go.nhat.io/wait v0.1.0
+	google.golang.org/protobuf v1.33.0
 )
 
 require (
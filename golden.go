@@ -0,0 +1,103 @@
+package httpmock
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock/test"
+)
+
+// goldenRequest is a request in a golden file.
+type goldenRequest struct {
+	Method     string      `json:"method"`
+	RequestURI string      `json:"uri"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// AssertRequestsMatchGolden asserts that the requests received by the server, in order, match the golden file, so the
+// outbound requests of a client can be snapshot-tested. The ignored headers, for example, the ones that change on every
+// run, are not recorded.
+//
+// When the test binary has an -update flag and it is set, the golden file is written instead.
+//
+//	var _ = flag.Bool("update", false, "update the golden files")
+//
+//	srv.AssertRequestsMatchGolden(t, "testdata/requests.golden.json", "User-Agent")
+func (s *Server) AssertRequestsMatchGolden(t test.T, path string, ignoreHeaders ...string) bool {
+	return s.assertRequestsMatchGolden(t, path, shouldUpdateGolden(), ignoreHeaders...)
+}
+
+func (s *Server) assertRequestsMatchGolden(t test.T, path string, update bool, ignoreHeaders ...string) bool {
+	actual, err := json.MarshalIndent(goldenRequests(s.Journal(), ignoreHeaders), "", "    ")
+	if !assert.NoError(t, err, "could not encode requests") {
+		return false
+	}
+
+	path = filepath.Clean(path)
+
+	if update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); !assert.NoError(t, err, "could not create golden file directory") { // nolint: gosec
+			return false
+		}
+
+		return assert.NoError(t, os.WriteFile(path, append(actual, '\n'), 0o600), "could not write golden file")
+	}
+
+	expected, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Errorf("golden file %q does not exist, run the test with -update to create it", path)
+
+		return false
+	}
+
+	if !assert.NoError(t, err, "could not read golden file") {
+		return false
+	}
+
+	return assert.JSONEq(t, string(expected), string(actual), "requests do not match golden file %q", path)
+}
+
+func goldenRequests(journal []JournalEntry, ignoreHeaders []string) []goldenRequest {
+	result := make([]goldenRequest, 0, len(journal))
+
+	for _, entry := range journal {
+		header := entry.Header.Clone()
+
+		for _, h := range ignoreHeaders {
+			header.Del(h)
+		}
+
+		if len(header) == 0 {
+			header = nil
+		}
+
+		result = append(result, goldenRequest{
+			Method:     entry.Method,
+			RequestURI: entry.RequestURI,
+			Header:     header,
+			Body:       entry.Body,
+		})
+	}
+
+	return result
+}
+
+// shouldUpdateGolden checks whether the -update flag of the test binary is set.
+func shouldUpdateGolden() bool {
+	f := flag.Lookup("update")
+	if f == nil {
+		return false
+	}
+
+	update, err := strconv.ParseBool(f.Value.String())
+
+	return err == nil && update
+}
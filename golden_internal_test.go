@@ -0,0 +1,73 @@
+package httpmock
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_AssertRequestsMatchGolden_Update(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer()
+
+	defer s.Close()
+
+	s.ExpectPost("/users").WithBody(`{"name":"john"}`)
+	s.ExpectGet("/users/42")
+
+	req, err := http.NewRequest(http.MethodPost, s.URL()+"/users", strings.NewReader(`{"name":"john"}`)) //nolint: noctx
+	require.NoError(t, err)
+
+	req.Header.Set("Authorization", "Bearer token")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	_ = resp.Body.Close() //nolint: errcheck
+
+	resp, err = http.Get(s.URL() + "/users/42") //nolint: noctx
+	require.NoError(t, err)
+
+	_ = resp.Body.Close() //nolint: errcheck
+
+	path := filepath.Join(t.TempDir(), "testdata", "requests.golden.json")
+
+	assert.True(t, s.assertRequestsMatchGolden(t, path, true, "User-Agent", "Accept-Encoding", "Content-Length"))
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	require.NoError(t, err)
+
+	expected := `[
+    {
+        "method": "POST",
+        "uri": "/users",
+        "header": {
+            "Authorization": [
+                "Bearer token"
+            ]
+        },
+        "body": "{\"name\":\"john\"}"
+    },
+    {
+        "method": "GET",
+        "uri": "/users/42"
+    }
+]
+`
+
+	assert.Equal(t, expected, string(data))
+	assert.True(t, s.assertRequestsMatchGolden(t, path, false, "User-Agent", "Accept-Encoding", "Content-Length"))
+}
+
+func TestShouldUpdateGolden(t *testing.T) {
+	t.Parallel()
+
+	// The test binary does not have the -update flag.
+	assert.False(t, shouldUpdateGolden())
+}
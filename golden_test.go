@@ -0,0 +1,54 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestServer_AssertRequestsMatchGolden(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "requests.golden.json")
+
+	err := os.WriteFile(path, []byte(`[{"method": "GET", "uri": "/users/42"}]`), 0o600)
+	require.NoError(t, err)
+
+	s := httpmock.NewServer()
+
+	defer s.Close()
+
+	s.ExpectGet("/users/42")
+
+	doRequest(t, s.URL(), http.MethodGet, "/users/42", nil, nil, 0)
+
+	testingT := T()
+
+	assert.True(t, s.AssertRequestsMatchGolden(testingT, path, "User-Agent", "Accept-Encoding"))
+	assert.Empty(t, testingT.String())
+
+	// The header is not ignored.
+	assert.False(t, s.AssertRequestsMatchGolden(testingT, path, "Accept-Encoding"))
+	assert.Contains(t, testingT.String(), "requests do not match golden file")
+}
+
+func TestServer_AssertRequestsMatchGolden_NotFound(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "requests.golden.json")
+
+	s := httpmock.NewServer()
+
+	defer s.Close()
+
+	testingT := T()
+
+	assert.False(t, s.AssertRequestsMatchGolden(testingT, path))
+	assert.Equal(t, `golden file "`+path+`" does not exist, run the test with -update to create it`, testingT.String())
+}
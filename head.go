@@ -0,0 +1,115 @@
+package httpmock
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.nhat.io/httpmock/planner"
+	"go.nhat.io/httpmock/value"
+)
+
+var _ ExpectationHandler = (*headHandler)(nil)
+
+// headHandler answers a HEAD request with the headers of a GET expectation but no body.
+type headHandler struct {
+	expectation *requestExpectation
+}
+
+func (h headHandler) Handle(w http.ResponseWriter, r *http.Request, defaultHeaders map[string]string) error {
+	return h.expectation.handleHead(w, r, defaultHeaders)
+}
+
+func (h headHandler) hasDelay() bool {
+	return h.expectation.hasDelay()
+}
+
+// handleHead writes the status code and the headers of the response without calling the handlers of the expectation,
+// so a HEAD request does not run the Run closures, draw a weighted response, or count a call. The Content-Length is only
+// written if the response body is static, for example, with Return or ReturnJSON.
+func (e *requestExpectation) handleHead(w http.ResponseWriter, r *http.Request, defaultHeaders map[string]string) error {
+	e.lock()
+	c := e.handleSettings()
+	example := e.example
+	headerMergeOf := e.headerMergeOf
+
+	if e.noDefaultHeaders {
+		defaultHeaders = nil
+	}
+	e.unlock()
+
+	policy := HeaderMergeExpectationWins
+
+	if headerMergeOf != nil {
+		policy = headerMergeOf()
+	}
+
+	if err := c.waiter.Wait(r.Context()); err != nil {
+		return err
+	}
+
+	if len(c.responseHeader) > 0 || len(defaultHeaders) > 0 {
+		writeHeaders(w.Header(), c.responseHeader, defaultHeaders, policy)
+	}
+
+	if c.cacheHeaders != nil {
+		writeCacheHeaders(w.Header(), c.cacheHeaders(time.Now()), c.responseHeader)
+	}
+
+	if example != nil {
+		w.Header().Set("Content-Length", strconv.Itoa(len(example)))
+	}
+
+	w.WriteHeader(c.responseCode)
+
+	return nil
+}
+
+// WithAutoHead answers the HEAD requests that do not have any expectation by using the matching GET expectation. The
+// response has the status code and the headers of the GET expectation but no body, and the Content-Length if the body
+// is static. The handlers of the GET expectation, for example, the Run closures, are not called, and the GET expectation
+// is not fulfilled by the HEAD requests.
+//
+//	Server.WithAutoHead()
+func (s *Server) WithAutoHead() *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.autoHead = true
+
+	return s
+}
+
+// findHeadHandler finds a GET expectation for a HEAD request. The caller must hold the lock.
+func (s *Server) findHeadHandler(r *http.Request) ExpectationHandler {
+	if !s.autoHead || r.Method != http.MethodHead {
+		return nil
+	}
+
+	get := asGetRequest(r)
+
+	for _, expected := range s.planner.Remain() {
+		if planner.MatchRequest(expected, get) != nil {
+			continue
+		}
+
+		if e, ok := asRequestExpectation(expected); ok {
+			return headHandler{expectation: e}
+		}
+	}
+
+	return nil
+}
+
+// asGetRequest clones the request as a GET request, the body of the request can still be read.
+func asGetRequest(r *http.Request) *http.Request {
+	body, _ := value.PeekBody(r) //nolint: errcheck
+
+	get := r.Clone(r.Context())
+	get.Method = http.MethodGet
+	get.Body = io.NopCloser(bytes.NewReader(body))
+
+	return get
+}
@@ -0,0 +1,123 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestServer_WithAutoHead(t *testing.T) {
+	t.Parallel()
+
+	testingT := T()
+
+	s := httpmock.MockServer(func(s *httpmock.Server) {
+		s.WithTest(testingT).
+			WithAutoHead().
+			WithDefaultResponseHeaders(httpmock.Header{"X-Default": "default"})
+
+		s.ExpectGet("/file").
+			ReturnHeader("Content-Type", "text/plain").
+			Return("hello world!")
+	})
+
+	defer s.Close()
+
+	code, headers, body, _ := doRequest(t, s.URL(), http.MethodHead, "/file", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "text/plain", headers["Content-Type"])
+	assert.Equal(t, "default", headers["X-Default"])
+	assert.Equal(t, "12", headers["Content-Length"])
+	assert.Empty(t, body)
+
+	// The GET expectation is not fulfilled by the HEAD request.
+	assert.Error(t, s.ExpectationsWereMet())
+
+	code, _, body, _ = doRequest(t, s.URL(), http.MethodGet, "/file", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "hello world!", string(body))
+	assert.NoError(t, s.ExpectationsWereMet())
+	assert.Empty(t, testingT.String())
+}
+
+func TestServer_WithAutoHead_NoSideEffect(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+
+	s := httpmock.New(func(s *httpmock.Server) {
+		s.WithAutoHead()
+
+		s.ExpectGet("/file").
+			ReturnCode(httpmock.StatusAccepted).
+			ReturnHeader("Content-Type", "text/plain").
+			Run(func(*http.Request) ([]byte, error) {
+				atomic.AddInt32(&calls, 1)
+
+				return []byte("hello world!"), nil
+			})
+	})(t)
+
+	code, headers, body, _ := doRequest(t, s.URL(), http.MethodHead, "/file", nil, nil, 0)
+
+	assert.Equal(t, http.StatusAccepted, code)
+	assert.Equal(t, "text/plain", headers["Content-Type"])
+	assert.Empty(t, headers["Content-Length"])
+	assert.Empty(t, body)
+
+	// The handler is not called by the HEAD request.
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+
+	code, _, body, _ = doRequest(t, s.URL(), http.MethodGet, "/file", nil, nil, 0)
+
+	assert.Equal(t, http.StatusAccepted, code)
+	assert.Equal(t, "hello world!", string(body))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestServer_WithAutoHead_NoGetExpectation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario   string
+		mockServer func(s *httpmock.Server)
+	}{
+		{
+			scenario: "auto head is disabled",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet("/file")
+			},
+		},
+		{
+			scenario: "no get expectation",
+			mockServer: func(s *httpmock.Server) {
+				s.WithAutoHead()
+				s.ExpectGet("/other")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			testingT := T()
+
+			s := httpmock.MockServer(tc.mockServer).WithTest(testingT)
+
+			defer s.Close()
+
+			code, _, _, _ := doRequest(t, s.URL(), http.MethodHead, "/file", nil, nil, 0)
+
+			assert.Equal(t, http.StatusInternalServerError, code)
+			assert.Contains(t, testingT.String(), "Actual: HEAD /file")
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package httpmock
+
+import "net/http"
+
+// HeaderMergePolicy is how the default response headers of the server are merged with the response headers of an
+// expectation, see Server.WithHeaderMergePolicy.
+type HeaderMergePolicy int
+
+const (
+	// HeaderMergeExpectationWins writes the header of the expectation instead of the default one. This is the default
+	// policy.
+	HeaderMergeExpectationWins HeaderMergePolicy = iota
+	// HeaderMergeServerWins writes the default header instead of the one of the expectation, for example, to enforce the
+	// security headers.
+	HeaderMergeServerWins
+	// HeaderMergeAppend writes both the default header and the header of the expectation, in that order.
+	HeaderMergeAppend
+)
+
+// WithHeaderMergePolicy sets how the default response headers are merged with the response headers of the
+// expectations, when both of them have the same header. By default, the header of the expectation wins. See also
+// Expectation.WithoutDefaultHeaders.
+//
+//	Server.WithDefaultResponseHeaders(map[string]string{"X-Frame-Options": "DENY"}).
+//		WithHeaderMergePolicy(httpmock.HeaderMergeServerWins)
+func (s *Server) WithHeaderMergePolicy(policy HeaderMergePolicy) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.headerMerge = policy
+
+	return s
+}
+
+func (s *Server) headerMergePolicy() HeaderMergePolicy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.headerMerge
+}
+
+// WithoutDefaultHeaders does not write the default response headers of the server in the response of the expectation,
+// for example, to test a misconfigured endpoint.
+//
+//	Server.Expect(httpmock.MethodGet, "/legacy").
+//		WithoutDefaultHeaders()
+func (e *requestExpectation) WithoutDefaultHeaders() Expectation {
+	e.lock()
+	defer e.unlock()
+
+	e.noDefaultHeaders = true
+
+	return e
+}
+
+// hasHeader checks whether the headers have the header, case-insensitively.
+func hasHeader(headers Header, header string) bool {
+	header = http.CanonicalHeaderKey(header)
+
+	for k := range headers {
+		if http.CanonicalHeaderKey(k) == header {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,375 @@
+package httpmock
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// maxHeaderLineSize is the maximum size of a header line to capture, the capture stops if a line is longer.
+const maxHeaderLineSize = 1 << 20
+
+type (
+	headerOrderConnKey struct{}
+	headerOrderKey     struct{}
+)
+
+// WithHeaderOrderCapture captures the header names of the HTTP/1.x requests, in the order and the casing that the client
+// sent them, see RequestHeaderOrder and JournalEntry.HeaderOrder. The capture costs some allocations per request, so it
+// is disabled by default, and it is enabled by the expectations with WithHeaderOrder or WithStrictHeaderCase. Only the
+// connections that are accepted after the capture is enabled are captured.
+//
+//	Server.WithHeaderOrderCapture()
+func (s *Server) WithHeaderOrderCapture() *Server {
+	s.enableHeaderOrderCapture()
+
+	return s
+}
+
+func (s *Server) enableHeaderOrderCapture() {
+	atomic.StoreInt32(&s.headerOrderCapture, 1)
+}
+
+func (s *Server) capturesHeaderOrder() bool {
+	return atomic.LoadInt32(&s.headerOrderCapture) == 1
+}
+
+// headerOrderListener captures the raw header order of the requests of its connections, if the capture is enabled when
+// they are accepted.
+type headerOrderListener struct {
+	net.Listener
+
+	capture func() bool
+}
+
+func (l *headerOrderListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	if !l.capture() {
+		return c, nil
+	}
+
+	return &headerOrderConn{Conn: c}, nil
+}
+
+// headerOrderConn parses the raw HTTP/1.x requests while they are read by the server, to capture the header names in
+// the order and the casing that the client sent them. The capture stops if the stream is not HTTP/1.x, for example, a
+// TLS handshake.
+type headerOrderConn struct {
+	net.Conn
+
+	mu     sync.Mutex
+	parser headerOrderParser
+	orders [][]string
+}
+
+func (c *headerOrderConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+
+	if n > 0 {
+		c.mu.Lock()
+		c.orders = append(c.orders, c.parser.write(b[:n])...)
+		c.mu.Unlock()
+	}
+
+	return n, err
+}
+
+// next returns the header order of the next request of the connection, nil if it is not captured.
+func (c *headerOrderConn) next() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.orders) == 0 {
+		return nil
+	}
+
+	order := c.orders[0]
+	c.orders = c.orders[1:]
+
+	return order
+}
+
+type headerOrderState int
+
+const (
+	stateRequestLine headerOrderState = iota
+	stateHeader
+	stateBody
+	stateChunkSize
+	stateChunkData
+	stateChunkEnd
+	stateTrailer
+	stateStopped
+)
+
+// headerOrderParser is a minimal HTTP/1.x request parser that only keeps the header names, and skips the bodies.
+type headerOrderParser struct {
+	state   headerOrderState
+	line    []byte
+	order   []string
+	length  int64
+	chunked bool
+}
+
+// write parses the data and returns the header orders of the requests whose header is complete.
+func (p *headerOrderParser) write(data []byte) [][]string {
+	var orders [][]string
+
+	for len(data) > 0 && p.state != stateStopped {
+		if p.state == stateBody || p.state == stateChunkData {
+			n := int64(len(data))
+			if n > p.length {
+				n = p.length
+			}
+
+			data = data[n:]
+			p.length -= n
+
+			if p.length == 0 {
+				p.endData()
+			}
+
+			continue
+		}
+
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			p.appendLine(data)
+
+			break
+		}
+
+		p.appendLine(data[:i])
+		data = data[i+1:]
+
+		if order, ok := p.parseLine(strings.TrimSuffix(string(p.line), "\r")); ok {
+			orders = append(orders, order)
+		}
+
+		p.line = p.line[:0]
+	}
+
+	return orders
+}
+
+func (p *headerOrderParser) appendLine(data []byte) {
+	if len(p.line)+len(data) > maxHeaderLineSize {
+		p.state = stateStopped
+
+		return
+	}
+
+	p.line = append(p.line, data...)
+}
+
+// parseLine parses a complete line and returns the header order if it is the end of a request header.
+func (p *headerOrderParser) parseLine(line string) ([]string, bool) {
+	switch p.state {
+	case stateRequestLine:
+		// The empty lines before a request are ignored.
+		if line == "" {
+			return nil, false
+		}
+
+		if !strings.HasSuffix(line, "HTTP/1.1") && !strings.HasSuffix(line, "HTTP/1.0") {
+			p.state = stateStopped
+
+			return nil, false
+		}
+
+		p.state = stateHeader
+		p.order = nil
+		p.length = 0
+		p.chunked = false
+
+	case stateHeader:
+		if line == "" {
+			order := p.order
+
+			p.endHeader()
+
+			return order, true
+		}
+
+		p.parseHeader(line)
+
+	case stateChunkSize:
+		size := line
+		if i := strings.IndexByte(size, ';'); i >= 0 {
+			size = size[:i]
+		}
+
+		n, err := strconv.ParseInt(strings.TrimSpace(size), 16, 64)
+		if err != nil || n < 0 {
+			p.state = stateStopped
+
+			return nil, false
+		}
+
+		p.length = n
+
+		if n == 0 {
+			p.state = stateTrailer
+		} else {
+			p.state = stateChunkData
+		}
+
+	case stateChunkEnd:
+		p.state = stateChunkSize
+
+	case stateTrailer:
+		if line == "" {
+			p.state = stateRequestLine
+		}
+	}
+
+	return nil, false
+}
+
+func (p *headerOrderParser) parseHeader(line string) {
+	// The obsolete line folding continues the value of the previous header.
+	if line[0] == ' ' || line[0] == '\t' {
+		return
+	}
+
+	i := strings.IndexByte(line, ':')
+	if i <= 0 {
+		return
+	}
+
+	name, value := line[:i], strings.TrimSpace(line[i+1:])
+
+	p.order = append(p.order, name)
+
+	switch http.CanonicalHeaderKey(name) {
+	case "Content-Length":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			p.length = n
+		}
+
+	case "Transfer-Encoding":
+		p.chunked = strings.EqualFold(value, "chunked")
+	}
+}
+
+func (p *headerOrderParser) endHeader() {
+	switch {
+	case p.chunked:
+		p.state = stateChunkSize
+
+	case p.length > 0:
+		p.state = stateBody
+
+	default:
+		p.state = stateRequestLine
+	}
+}
+
+func (p *headerOrderParser) endData() {
+	if p.state == stateChunkData {
+		p.state = stateChunkEnd
+
+		return
+	}
+
+	p.state = stateRequestLine
+}
+
+// withHeaderOrderConn puts the connection to the context, so the header order of its requests can be found.
+func withHeaderOrderConn(ctx context.Context, c net.Conn) context.Context {
+	if hc, ok := c.(*headerOrderConn); ok {
+		return context.WithValue(ctx, headerOrderConnKey{}, hc)
+	}
+
+	return ctx
+}
+
+// withHeaderOrder takes the captured header order of the request from its connection and puts it to the request.
+func withHeaderOrder(r *http.Request) *http.Request {
+	c, ok := r.Context().Value(headerOrderConnKey{}).(*headerOrderConn)
+	if !ok {
+		return r
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), headerOrderKey{}, c.next()))
+}
+
+// RequestHeaderOrder returns the header names of a request received by the server, in the order and the casing that the
+// client sent them, nil if they are not captured, for example, when the request is sent over TLS or HTTP/2, or the
+// capture is not enabled, see Server.WithHeaderOrderCapture.
+func RequestHeaderOrder(r *http.Request) []string {
+	order, _ := r.Context().Value(headerOrderKey{}).([]string) // nolint: errcheck
+
+	return order
+}
+
+func matchHeaderOrder(expected, actual []string) error {
+	if actual == nil {
+		return fmt.Errorf("header order %q expected, but it is not captured", expected) // nolint: goerr113
+	}
+
+	i := 0
+
+	for _, h := range actual {
+		if i < len(expected) && strings.EqualFold(h, expected[i]) {
+			i++
+		}
+	}
+
+	if i < len(expected) {
+		return fmt.Errorf("header order %q expected, %q received", expected, actual) // nolint: goerr113
+	}
+
+	return nil
+}
+
+func matchHeaderCase(expected, actual []string) error {
+	if actual == nil {
+		return fmt.Errorf("header case %q expected, but it is not captured", expected) // nolint: goerr113
+	}
+
+	for _, h := range expected {
+		found := ""
+
+		for _, a := range actual {
+			if a == h {
+				found = a
+
+				break
+			}
+
+			if strings.EqualFold(a, h) {
+				found = a
+			}
+		}
+
+		if found != h {
+			return fmt.Errorf("header %q expected, %q received", h, found) // nolint: goerr113
+		}
+	}
+
+	return nil
+}
+
+// appendHeaderName appends the header name if it is not in the list, or replaces the one in another casing.
+func appendHeaderName(names []string, name string) []string {
+	for i, n := range names {
+		if strings.EqualFold(n, name) {
+			names[i] = name
+
+			return names
+		}
+	}
+
+	return append(names, name)
+}
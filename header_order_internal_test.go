@@ -0,0 +1,92 @@
+package httpmock
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaderOrderParser(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		data     []string
+		expected [][]string
+	}{
+		{
+			scenario: "no body",
+			data:     []string{"GET / HTTP/1.1\r\nHost: localhost\r\nx-lower: 1\r\nAccept: */*\r\n\r\n"},
+			expected: [][]string{{"Host", "x-lower", "Accept"}},
+		},
+		{
+			scenario: "split in many reads",
+			data:     []string{"GET / HT", "TP/1.1\r\nHo", "st: localhost\r", "\nAccept: */*\r\n", "\r\n"},
+			expected: [][]string{{"Host", "Accept"}},
+		},
+		{
+			scenario: "pipelined requests with content length",
+			data: []string{
+				"POST / HTTP/1.1\r\nHost: localhost\r\nContent-Length: 13\r\n\r\nhello\r\n\r\nworld",
+				"GET / HTTP/1.1\r\nAccept: */*\r\nHost: localhost\r\n\r\n",
+			},
+			expected: [][]string{{"Host", "Content-Length"}, {"Accept", "Host"}},
+		},
+		{
+			scenario: "chunked body with trailer",
+			data: []string{
+				"POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\nHost: localhost\r\n\r\n",
+				"5;ext=1\r\nhe\r\n\r\r\n",
+				"0\r\nX-Checksum: 1\r\n\r\n",
+				"GET / HTTP/1.1\r\nHost: localhost\r\n\r\n",
+			},
+			expected: [][]string{{"Transfer-Encoding", "Host"}, {"Host"}},
+		},
+		{
+			scenario: "not http",
+			data:     []string{"\x16\x03\x01\x02\x00\x01\x00\x01\xfc\x03\x03\n", "GET / HTTP/1.1\r\nHost: localhost\r\n\r\n"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				p      headerOrderParser
+				actual [][]string
+			)
+
+			for _, d := range tc.data {
+				actual = append(actual, p.write([]byte(d))...)
+			}
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestMatchHeaderOrder(t *testing.T) {
+	t.Parallel()
+
+	actual := []string{"Host", "user-agent", "Accept", "Accept-Encoding"}
+
+	assert.NoError(t, matchHeaderOrder([]string{"Host", "Accept"}, actual))
+	assert.NoError(t, matchHeaderOrder([]string{"User-Agent", "Accept-Encoding"}, actual))
+	assert.EqualError(t, matchHeaderOrder([]string{"Accept", "Host"}, actual),
+		`header order ["Accept" "Host"] expected, ["Host" "user-agent" "Accept" "Accept-Encoding"] received`,
+	)
+	assert.EqualError(t, matchHeaderOrder([]string{"Host"}, nil), `header order ["Host"] expected, but it is not captured`)
+}
+
+func TestMatchHeaderCase(t *testing.T) {
+	t.Parallel()
+
+	actual := []string{"Host", "x-api-key", "Accept"}
+
+	assert.NoError(t, matchHeaderCase([]string{"x-api-key", "Host"}, actual))
+	assert.EqualError(t, matchHeaderCase([]string{"X-Api-Key"}, actual), `header "X-Api-Key" expected, "x-api-key" received`)
+	assert.EqualError(t, matchHeaderCase([]string{"Authorization"}, actual), `header "Authorization" expected, "" received`)
+	assert.EqualError(t, matchHeaderCase([]string{"Host"}, nil), `header case ["Host"] expected, but it is not captured`)
+}
@@ -0,0 +1,171 @@
+package httpmock_test
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestServer_WithHeaderOrder(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+
+	defer s.Close()
+
+	s.ExpectGet("/").
+		WithHeaderOrder("host", "X-Legacy-Token", "Accept").
+		ReturnCode(http.StatusNoContent)
+
+	s.ExpectGet("/").
+		WithHeaderOrder("Accept", "X-Legacy-Token").
+		ReturnCode(http.StatusAccepted)
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(s.URL(), "http://"))
+	require.NoError(t, err)
+
+	defer conn.Close() // nolint: errcheck
+
+	r := bufio.NewReader(conn)
+
+	send := func(request string) int {
+		_, err := conn.Write([]byte(request))
+		require.NoError(t, err)
+
+		resp, err := http.ReadResponse(r, nil)
+		require.NoError(t, err)
+
+		defer resp.Body.Close() // nolint: errcheck
+
+		return resp.StatusCode
+	}
+
+	code := send("GET / HTTP/1.1\r\nHost: localhost\r\nX-LEGACY-TOKEN: 42\r\nAccept: */*\r\n\r\n")
+	assert.Equal(t, http.StatusNoContent, code)
+
+	code = send("GET / HTTP/1.1\r\nAccept: */*\r\nHost: localhost\r\nX-Legacy-Token: 42\r\n\r\n")
+	assert.Equal(t, http.StatusAccepted, code)
+
+	journal := s.Journal()
+
+	require.Len(t, journal, 2)
+
+	assert.Equal(t, []string{"Host", "X-LEGACY-TOKEN", "Accept"}, journal[0].HeaderOrder)
+	assert.Equal(t, []string{"Accept", "Host", "X-Legacy-Token"}, journal[1].HeaderOrder)
+	assert.NoError(t, s.ExpectationsWereMet())
+}
+
+func TestServer_WithoutHeaderOrderCapture(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet("/").
+			Run(func(r *http.Request) ([]byte, error) {
+				assert.Nil(t, httpmock.RequestHeaderOrder(r))
+
+				return nil, nil
+			})
+	})(t)
+
+	code, _, _, _ := doRequest(t, s.URL(), http.MethodGet, "/", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+
+	// The header order is not captured if no expectation needs it.
+	journal := s.Journal()
+
+	require.Len(t, journal, 1)
+	assert.Nil(t, journal[0].HeaderOrder)
+}
+
+func TestServer_WithHeaderOrderCapture(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.New(func(s *httpmock.Server) {
+		s.WithHeaderOrderCapture()
+
+		s.ExpectGet("/")
+	})(t)
+
+	code, _, _, _ := doRequest(t, s.URL(), http.MethodGet, "/", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+
+	journal := s.Journal()
+
+	require.Len(t, journal, 1)
+	assert.Equal(t, []string{"Host", "User-Agent", "Accept-Encoding"}, journal[0].HeaderOrder)
+}
+
+func TestServer_WithHeaderOrder_Mismatched(t *testing.T) {
+	t.Parallel()
+
+	testingT := T()
+
+	s := httpmock.NewServer().WithTest(testingT)
+
+	defer s.Close()
+
+	s.ExpectGet("/").
+		WithHeaderOrder("Accept", "Host")
+
+	code, _, _, _ := doRequest(t, s.URL(), http.MethodGet, "/", httpmock.Header{"Accept": "*/*"}, nil, 0)
+
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Contains(t, testingT.String(), `Error: header order ["Accept" "Host"] expected, ["Host" "User-Agent" "Accept" "Accept-Encoding"] received`)
+}
+
+func TestServer_WithStrictHeaderCase(t *testing.T) {
+	t.Parallel()
+
+	testingT := T()
+
+	s := httpmock.NewServer().WithTest(testingT)
+
+	defer s.Close()
+
+	s.ExpectGet("/").
+		WithHeader("x-api-key", "secret").
+		WithStrictHeaderCase().
+		ReturnCode(http.StatusNoContent)
+
+	// The header in another casing is matched, without the strict casing.
+	s.ExpectGet("/").
+		WithHeader("x-api-key", "secret").
+		ReturnCode(http.StatusAccepted)
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(s.URL(), "http://"))
+	require.NoError(t, err)
+
+	defer conn.Close() // nolint: errcheck
+
+	r := bufio.NewReader(conn)
+
+	send := func(request string) int {
+		_, err := conn.Write([]byte(request))
+		require.NoError(t, err)
+
+		resp, err := http.ReadResponse(r, nil)
+		require.NoError(t, err)
+
+		defer resp.Body.Close() // nolint: errcheck
+
+		return resp.StatusCode
+	}
+
+	code := send("GET / HTTP/1.1\r\nHost: localhost\r\nx-api-key: secret\r\n\r\n")
+	assert.Equal(t, http.StatusNoContent, code)
+
+	code = send("GET / HTTP/1.1\r\nHost: localhost\r\nX-Api-Key: secret\r\n\r\n")
+	assert.Equal(t, http.StatusAccepted, code)
+
+	assert.Empty(t, testingT.String())
+	assert.NoError(t, s.ExpectationsWereMet())
+}
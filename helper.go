@@ -1,10 +1,16 @@
 package httpmock
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -39,6 +45,23 @@ func DoRequestWithTimeout(
 ) (int, map[string]string, []byte, time.Duration) {
 	tb.Helper()
 
+	req := newRequest(tb, method, requestURI, headers, body)
+	client := http.Client{Timeout: timeout}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	elapsed := time.Since(start)
+
+	require.NoError(tb, err, "could not make a request to mocked server")
+
+	respCode, respHeaders, respBody := readResponse(tb, resp)
+
+	return respCode, respHeaders, respBody, elapsed
+}
+
+func newRequest(tb testing.TB, method, requestURI string, headers Header, body []byte) *http.Request {
+	tb.Helper()
+
 	var reqBody io.Reader
 
 	if body != nil {
@@ -52,15 +75,12 @@ func DoRequestWithTimeout(
 		req.Header.Set(header, value)
 	}
 
-	client := http.Client{Timeout: timeout}
-
-	start := time.Now()
-	resp, err := client.Do(req)
-	elapsed := time.Since(start)
+	return req
+}
 
-	require.NoError(tb, err, "could not make a request to mocked server")
+func readResponse(tb testing.TB, resp *http.Response) (int, map[string]string, []byte) {
+	tb.Helper()
 
-	respCode := resp.StatusCode
 	respHeaders := map[string]string(nil)
 
 	if len(resp.Header) > 0 {
@@ -77,7 +97,69 @@ func DoRequestWithTimeout(
 	err = resp.Body.Close()
 	require.NoError(tb, err, "could not close response body")
 
-	return respCode, respHeaders, respBody, elapsed
+	return resp.StatusCode, respHeaders, respBody
+}
+
+// DoMultipartRequest sends a multipart/form-data request with 1 second timeout and returns the status code, response
+// headers and response body along with the total execution time. The files map the form field names to the paths of
+// the files to upload.
+//
+//	code, headers, body, _ = DoMultipartRequest(t, http.MethodPost, srv.URL()+"/upload",
+//		map[string]string{"name": "avatar"},
+//		map[string]string{"file": "resources/fixtures/avatar.png"},
+//	)
+func DoMultipartRequest(
+	tb testing.TB,
+	method, requestURI string,
+	fields map[string]string,
+	files map[string]string,
+) (int, map[string]string, []byte, time.Duration) {
+	tb.Helper()
+
+	var buf bytes.Buffer
+
+	w := multipart.NewWriter(&buf)
+
+	for _, field := range sortedKeys(fields) {
+		err := w.WriteField(field, fields[field])
+		require.NoError(tb, err, "could not write field %q", field)
+	}
+
+	for _, field := range sortedKeys(files) {
+		writeMultipartFile(tb, w, field, files[field])
+	}
+
+	err := w.Close()
+	require.NoError(tb, err, "could not close multipart writer")
+
+	return DoRequestWithTimeout(tb, method, requestURI, Header{"Content-Type": w.FormDataContentType()}, buf.Bytes(), time.Second)
+}
+
+func writeMultipartFile(tb testing.TB, w *multipart.Writer, field, path string) {
+	tb.Helper()
+
+	f, err := os.Open(filepath.Clean(path))
+	require.NoError(tb, err, "could not open file %q", path)
+
+	defer f.Close() // nolint: errcheck
+
+	part, err := w.CreateFormFile(field, filepath.Base(path))
+	require.NoError(tb, err, "could not create form file %q", field)
+
+	_, err = io.Copy(part, f)
+	require.NoError(tb, err, "could not write file %q", path)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
 }
 
 // FailResponse responds a failure to client.
@@ -109,3 +191,51 @@ func AssertHeaderContains(t test.T, headers, contains Header) bool {
 
 	return assert.Equal(t, expectedHeaders, actualHeaders)
 }
+
+// AssertHeaderNotContains asserts that the HTTP headers do not contain any of the given headers.
+func AssertHeaderNotContains(t test.T, headers Header, keys ...string) bool {
+	var unexpected []string
+
+	for _, key := range keys {
+		headerKey := http.CanonicalHeaderKey(key)
+
+		if _, ok := headers[headerKey]; ok {
+			unexpected = append(unexpected, headerKey)
+		}
+	}
+
+	return assert.Empty(t, unexpected, "unexpected headers in response")
+}
+
+// AssertStatus asserts that the HTTP status code is the expected one.
+func AssertStatus(t test.T, code, want int) bool {
+	return assert.Equal(t, want, code, "expected status %d %s, got %d %s",
+		want, http.StatusText(want), code, http.StatusText(code),
+	)
+}
+
+// AssertResponseJSONEqual asserts that the response body is equivalent to the expected JSON. The expected JSON could be
+// []byte, string, or any value that will be marshaled to JSON.
+//
+//	AssertResponseJSONEqual(t, body, map[string]any{"id": 42})
+func AssertResponseJSONEqual(t test.T, body []byte, expected any) bool {
+	var expectedJSON string
+
+	switch v := expected.(type) {
+	case []byte:
+		expectedJSON = string(v)
+
+	case string:
+		expectedJSON = v
+
+	default:
+		b, err := json.Marshal(v)
+		if !assert.NoError(t, err, "could not marshal expected json") {
+			return false
+		}
+
+		expectedJSON = string(b)
+	}
+
+	return assert.JSONEq(t, expectedJSON, string(body))
+}
@@ -0,0 +1,127 @@
+package httpmock_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestDoMultipartRequest(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+
+	defer s.Close()
+
+	s.ExpectPost("/upload").
+		WithHeader("Content-Type", httpmock.RegexPattern(`^multipart/form-data; boundary=`)).
+		Run(func(r *http.Request) ([]byte, error) {
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				return nil, err
+			}
+
+			f, h, err := r.FormFile("file")
+			if err != nil {
+				return nil, err
+			}
+
+			defer f.Close() // nolint: errcheck
+
+			content, err := io.ReadAll(f)
+			if err != nil {
+				return nil, err
+			}
+
+			return []byte(fmt.Sprintf("%s %s %s", r.FormValue("name"), h.Filename, content)), nil
+		})
+
+	code, _, body, _ := httpmock.DoMultipartRequest(t, http.MethodPost, s.URL()+"/upload",
+		map[string]string{"name": "fixture"},
+		map[string]string{"file": "resources/fixtures/response.txt"},
+	)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "fixture response.txt hello world!\n", string(body))
+}
+
+func TestAssertHeaderNotContains(t *testing.T) {
+	t.Parallel()
+
+	headers := httpmock.Header{"Content-Type": "application/json", "X-Request-Id": "42"}
+
+	testingT := T()
+
+	assert.True(t, httpmock.AssertHeaderNotContains(testingT, headers, "Authorization", "set-cookie"))
+	assert.Empty(t, testingT.String())
+
+	assert.False(t, httpmock.AssertHeaderNotContains(testingT, headers, "Authorization", "x-request-id"))
+	assert.Contains(t, testingT.String(), "X-Request-Id")
+	assert.Contains(t, testingT.String(), "unexpected headers in response")
+}
+
+func TestAssertStatus(t *testing.T) {
+	t.Parallel()
+
+	testingT := T()
+
+	assert.True(t, httpmock.AssertStatus(testingT, http.StatusOK, http.StatusOK))
+	assert.Empty(t, testingT.String())
+
+	assert.False(t, httpmock.AssertStatus(testingT, http.StatusNotFound, http.StatusOK))
+	assert.Contains(t, testingT.String(), "expected status 200 OK, got 404 Not Found")
+}
+
+func TestAssertResponseJSONEqual(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"id": 42, "name": "john"}`)
+
+	testCases := []struct {
+		scenario       string
+		expected       any
+		expectedResult bool
+	}{
+		{
+			scenario:       "string",
+			expected:       `{"name":"john","id":42}`,
+			expectedResult: true,
+		},
+		{
+			scenario:       "bytes",
+			expected:       []byte(`{"name":"john","id":42}`),
+			expectedResult: true,
+		},
+		{
+			scenario:       "value",
+			expected:       map[string]any{"id": 42, "name": "john"},
+			expectedResult: true,
+		},
+		{
+			scenario: "mismatched",
+			expected: map[string]any{"id": 43},
+		},
+		{
+			scenario: "could not marshal",
+			expected: make(chan struct{}),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			testingT := T()
+
+			result := httpmock.AssertResponseJSONEqual(testingT, body, tc.expected)
+
+			assert.Equal(t, tc.expectedResult, result)
+			assert.Equal(t, tc.expectedResult, testingT.String() == "")
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package http3 serves the expectations of a httpmock.Server over HTTP/3 with quic-go, to test the clients that
+// negotiate h3 via Alt-Svc. It is a separate module, so httpmock does not depend on a QUIC implementation.
+package http3
@@ -0,0 +1,11 @@
+module go.nhat.io/httpmock/http3
+
+go 1.22
+
+require (
+	github.com/quic-go/quic-go v0.48.2
+	github.com/stretchr/testify v1.10.0
+	go.nhat.io/httpmock v0.0.0-00010101000000-000000000000
+)
+
+replace go.nhat.io/httpmock => ../
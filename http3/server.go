@@ -0,0 +1,117 @@
+package http3
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	quichttp3 "github.com/quic-go/quic-go/http3"
+
+	"go.nhat.io/httpmock"
+)
+
+// ErrNotTLS indicates that the server is not started with TLS, HTTP/3 always requires TLS.
+var ErrNotTLS = errors.New("server is not started with TLS")
+
+// Server serves the expectations of a httpmock.Server over HTTP/3. The requests are handled by the same server, so they
+// are matched by the same expectations and recorded in the same journal, with the protocol HTTP/3.0.
+type Server struct {
+	mock   *httpmock.Server
+	server *quichttp3.Server
+	conn   net.PacketConn
+	done   chan struct{}
+}
+
+// Serve serves the expectations of the server over HTTP/3 on a random UDP port of the loopback interface, with the
+// certificate of the server. The server must be started with TLS.
+//
+//	srv := httpmock.NewTLSServer()
+//	defer srv.Close()
+//
+//	h3, err := http3.Serve(srv)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//
+//	defer h3.Close() // nolint: errcheck
+//
+//	srv.ExpectGet("/").
+//		ReturnHeader("Alt-Svc", h3.AltSvc()).
+//		Return("hello world!")
+func Serve(s *httpmock.Server) (*Server, error) {
+	return ServeOn(s, "127.0.0.1:0")
+}
+
+// ServeOn serves the expectations of the server over HTTP/3 on the UDP address, see Serve.
+func ServeOn(s *httpmock.Server, addr string) (*Server, error) {
+	tlsConfig := s.TLSConfig()
+	if tlsConfig == nil {
+		return nil, fmt.Errorf("could not serve over http/3: %w", ErrNotTLS)
+	}
+
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("could not serve over http/3: %w", err)
+	}
+
+	h3 := &Server{
+		mock: s,
+		server: &quichttp3.Server{
+			Handler:   s,
+			TLSConfig: quichttp3.ConfigureTLSConfig(tlsConfig),
+		},
+		conn: conn,
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(h3.done)
+
+		_ = h3.server.Serve(conn) // nolint: errcheck
+	}()
+
+	return h3, nil
+}
+
+// Addr returns the UDP address of the server, for example, 127.0.0.1:54321.
+func (s *Server) Addr() string {
+	return s.conn.LocalAddr().String()
+}
+
+// URL returns the base URL of the server, for example, https://127.0.0.1:54321.
+func (s *Server) URL() string {
+	return "https://" + s.Addr()
+}
+
+// AltSvc returns the value of the Alt-Svc header that advertises the server, for example, h3=":54321", so a client of
+// the TLS server could switch to HTTP/3.
+func (s *Server) AltSvc() string {
+	return fmt.Sprintf(`h3=":%d"`, s.conn.LocalAddr().(*net.UDPAddr).Port) // nolint: forcetypeassert
+}
+
+// Client returns a HTTP/3 client that trusts the certificate of the server. The transport of the client should be
+// closed when it is not used anymore.
+func (s *Server) Client() *http.Client {
+	pool := x509.NewCertPool()
+	pool.AddCert(s.mock.Certificate())
+
+	return &http.Client{
+		Transport: &quichttp3.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool}, // nolint: gosec
+		},
+	}
+}
+
+// Close stops serving over HTTP/3. It does not close the httpmock.Server.
+func (s *Server) Close() error {
+	err := s.server.Close()
+
+	_ = s.conn.Close() // nolint: errcheck
+
+	<-s.done
+
+	return err
+}
@@ -0,0 +1,73 @@
+package http3_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	quichttp3 "github.com/quic-go/quic-go/http3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/http3"
+)
+
+func TestServe(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.NewTLSServer()
+
+	defer srv.Close()
+
+	h3, err := http3.Serve(srv)
+	require.NoError(t, err)
+
+	defer h3.Close() // nolint: errcheck
+
+	srv.ExpectGet("/").
+		ReturnHeader("Alt-Svc", h3.AltSvc()).
+		Return("hello world!")
+
+	srv.ExpectGet("/").
+		WithProto("HTTP/3.0").
+		Return("hello h3!")
+
+	// The client of the TLS server discovers the HTTP/3 server.
+	resp, err := srv.Client().Get(srv.URL() + "/") //nolint: noctx
+	require.NoError(t, err)
+
+	defer resp.Body.Close() // nolint: errcheck
+
+	assert.Equal(t, h3.AltSvc(), resp.Header.Get("Alt-Svc"))
+
+	client := h3.Client()
+
+	defer client.Transport.(*quichttp3.Transport).Close() // nolint: errcheck,forcetypeassert
+
+	resp, err = client.Get(h3.URL() + "/") //nolint: noctx
+	require.NoError(t, err)
+
+	defer resp.Body.Close() // nolint: errcheck
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "HTTP/3.0", resp.Proto)
+	assert.Equal(t, "hello h3!", string(body))
+	assert.NoError(t, srv.ExpectationsWereMet())
+}
+
+func TestServe_NotTLS(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.NewServer()
+
+	defer srv.Close()
+
+	h3, err := http3.Serve(srv)
+
+	assert.Nil(t, h3)
+	assert.ErrorIs(t, err, http3.ErrNotTLS)
+}
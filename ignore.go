@@ -0,0 +1,37 @@
+package httpmock
+
+import (
+	"net/http"
+
+	"go.nhat.io/httpmock/planner"
+)
+
+// Ignore silently answers the matching requests with 204 No Content, for example, the background telemetry of the SDKs
+// that the tests do not assert on. The ignored requests are neither matched nor unmatched, they are not recorded in the
+// journal, and they do not fulfill any expectation.
+//
+//	Server.Ignore(httpmock.MethodPost, "/v1/telemetry")
+//	Server.Ignore(httpmock.MethodPost, matcher.RegexPattern(`^/analytics/`))
+func (s *Server) Ignore(method string, requestURI any) *Server {
+	ignored := newRequestExpectation(method, requestURI)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ignored = append(s.ignored, ignored)
+
+	return s
+}
+
+func (s *Server) isIgnoredRequest(r *http.Request) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, ignored := range s.ignored {
+		if planner.MatchRequest(ignored, r) == nil {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,54 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/matcher"
+)
+
+func TestServer_Ignore(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer().
+		WithTest(T()).
+		WithNoExtraInteractions().
+		Ignore(httpmock.MethodPost, "/v1/telemetry").
+		Ignore(httpmock.MethodPost, matcher.RegexPattern(`^/analytics/`))
+
+	defer s.Close()
+
+	s.ExpectGet("/users/42").
+		Return(`{"id":42}`)
+
+	for _, uri := range []string{"/v1/telemetry", "/analytics/events", "/analytics/pageviews"} {
+		code, _, body, _ := doRequest(t, s.URL(), http.MethodPost, uri, nil, []byte(`{"event":"start"}`), 0)
+
+		assert.Equal(t, http.StatusNoContent, code)
+		assert.Empty(t, body)
+	}
+
+	code, _, _, _ := doRequest(t, s.URL(), http.MethodGet, "/users/42", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+
+	// The ignored requests are not extra interactions.
+	code, _, _, _ = doRequest(t, s.URL(), http.MethodPost, "/v1/telemetry", nil, nil, 0)
+
+	assert.Equal(t, http.StatusNoContent, code)
+
+	// The method is not ignored.
+	code, _, _, _ = doRequest(t, s.URL(), http.MethodGet, "/v1/telemetry", nil, nil, 0)
+
+	assert.Equal(t, http.StatusInternalServerError, code)
+
+	journal := s.Journal()
+
+	assert.Len(t, journal, 2)
+	assert.Equal(t, "/users/42", journal[0].RequestURI)
+	assert.Equal(t, "/v1/telemetry", journal[1].RequestURI)
+	assert.False(t, journal[1].Matched)
+}
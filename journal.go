@@ -0,0 +1,119 @@
+package httpmock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// JournalEntry is a record of a request received by the server.
+type JournalEntry struct {
+	// Time is when the request was received.
+	Time time.Time `json:"time"`
+	// Method is the HTTP method of the request.
+	Method string `json:"method"`
+	// RequestURI is the request URI.
+	RequestURI string `json:"uri"`
+	// QueryValues are the query parameters of the request URI, see Query.
+	QueryValues url.Values `json:"query,omitempty"`
+	// RemoteAddr is the network address of the client, for example, 127.0.0.1:54321.
+	RemoteAddr string `json:"remoteAddr,omitempty"`
+	// Header is the request header.
+	Header http.Header `json:"header,omitempty"`
+	// HeaderOrder is the header names in the order and the casing that the client sent them, see RequestHeaderOrder.
+	HeaderOrder []string `json:"headerOrder,omitempty"`
+	// Body is the request body.
+	Body string `json:"body,omitempty"`
+	// TLS is the state of the TLS connection, nil if the request is not sent over TLS.
+	TLS *JournalTLS `json:"tls,omitempty"`
+	// Matched indicates whether the request matched an expectation.
+	Matched bool `json:"matched"`
+	// ClientCancelled indicates whether the client cancelled the request before the response was written, for example, it
+	// disconnected while the response was delayed.
+	ClientCancelled bool `json:"clientCancelled,omitempty"`
+	// Error is the reason why the request did not match any expectation.
+	Error string `json:"error,omitempty"`
+}
+
+func newJournalEntry(r *http.Request) JournalEntry {
+	var query url.Values
+
+	if r.URL.RawQuery != "" {
+		query = r.URL.Query()
+	}
+
+	return JournalEntry{
+		Time:        time.Now(),
+		Method:      r.Method,
+		RequestURI:  r.RequestURI,
+		QueryValues: query,
+		RemoteAddr:  r.RemoteAddr,
+		Header:      r.Header.Clone(),
+		HeaderOrder: RequestHeaderOrder(r),
+		TLS:         newJournalTLS(r.TLS),
+	}
+}
+
+// Journal returns all the requests received by the server.
+func (s *Server) Journal() []JournalEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]JournalEntry, len(s.journal))
+
+	copy(result, s.journal)
+
+	return result
+}
+
+// Query returns the first value of the query parameter of the request, or an empty string if it is not sent.
+func (e JournalEntry) Query(key string) string {
+	return e.QueryValues.Get(key)
+}
+
+// LastRequest returns the last request received by the server, or an empty entry if no request is received.
+//
+//	// Your requests.
+//
+//	assert.Equal(t, "2", srv.LastRequest().Query("page"))
+func (s *Server) LastRequest() JournalEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.journal) == 0 {
+		return JournalEntry{}
+	}
+
+	return s.journal[len(s.journal)-1]
+}
+
+// SaveJournal writes all the requests received by the server to a file in JSON, so it can be attached to a failed CI
+// run and be inspected later with LoadJournal.
+func (s *Server) SaveJournal(path string) error {
+	data, err := json.MarshalIndent(s.Journal(), "", "    ")
+	if err != nil {
+		return fmt.Errorf("could not encode journal: %w", err)
+	}
+
+	return os.WriteFile(filepath.Clean(path), data, 0o600)
+}
+
+// LoadJournal reads a journal saved by Server.SaveJournal.
+func LoadJournal(path string) ([]JournalEntry, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	var journal []JournalEntry
+
+	if err := json.Unmarshal(data, &journal); err != nil {
+		return nil, fmt.Errorf("could not decode journal: %w", err)
+	}
+
+	return journal, nil
+}
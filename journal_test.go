@@ -0,0 +1,225 @@
+package httpmock_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestServer_Journal(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.MockServer(func(s *httpmock.Server) {
+		s.ExpectPost("/users").
+			WithBody(`{"name":"John Doe"}`)
+	})
+
+	defer s.Close()
+
+	doRequest(t, s.URL(), http.MethodPost, "/users", Header{"Authorization": "Bearer token"}, []byte(`{"name":"John Doe"}`), 0)
+	doRequest(t, s.URL(), http.MethodGet, "/unknown", nil, nil, 0)
+
+	journal := s.Journal()
+
+	assert.Len(t, journal, 2)
+
+	assert.Equal(t, http.MethodPost, journal[0].Method)
+	assert.Equal(t, "/users", journal[0].RequestURI)
+	assert.Regexp(t, `^127\.0\.0\.1:\d+$`, journal[0].RemoteAddr)
+	assert.Equal(t, "Bearer token", journal[0].Header.Get("Authorization"))
+	assert.Equal(t, `{"name":"John Doe"}`, journal[0].Body)
+	assert.True(t, journal[0].Matched)
+	assert.Empty(t, journal[0].Error)
+	assert.False(t, journal[0].Time.IsZero())
+
+	assert.Equal(t, http.MethodGet, journal[1].Method)
+	assert.Equal(t, "/unknown", journal[1].RequestURI)
+	assert.False(t, journal[1].Matched)
+	assert.Equal(t, "unexpected request received: GET /unknown", journal[1].Error)
+}
+
+func TestServer_WithoutBodyCapture(t *testing.T) {
+	t.Parallel()
+
+	testingT := T()
+
+	s := httpmock.MockServer(func(s *httpmock.Server) {
+		s.WithTest(testingT).
+			WithoutBodyCapture()
+
+		s.ExpectPost("/users").
+			WithBody(`{"name":"John Doe"}`).
+			Return(`{"id":42}`)
+	})
+
+	defer s.Close()
+
+	// The body matcher still works.
+	code, _, body, _ := doRequest(t, s.URL(), http.MethodPost, "/users", nil, []byte(`{"name":"John Doe"}`), 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `{"id":42}`, string(body))
+
+	code, _, _, _ = doRequest(t, s.URL(), http.MethodPost, "/unknown", nil, []byte(`{"name":"Jane Doe"}`), 0)
+
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Equal(t, "unexpected request received: POST /unknown", testingT.String())
+
+	journal := s.Journal()
+
+	assert.Len(t, journal, 2)
+	assert.Empty(t, journal[0].Body)
+	assert.Empty(t, journal[1].Body)
+}
+
+func TestServer_Journal_BodyReadByHandler(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.MockServer(func(s *httpmock.Server) {
+		s.ExpectPost("/upload").
+			Run(func(r *http.Request) ([]byte, error) {
+				return io.ReadAll(r.Body)
+			})
+	})
+
+	defer s.Close()
+
+	code, _, body, _ := doRequest(t, s.URL(), http.MethodPost, "/upload", nil, []byte(`hello world`), 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `hello world`, string(body))
+
+	journal := s.Journal()
+
+	assert.Len(t, journal, 1)
+	assert.Equal(t, `hello world`, journal[0].Body)
+}
+
+func TestServer_WithoutBodyCapture_BodyNotRead(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewUnstartedServer().WithoutBodyCapture()
+
+	s.ExpectPost("/upload")
+
+	body := &countingReader{Reader: strings.NewReader("large upload")}
+	w := httptest.NewRecorder()
+
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/upload", body))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 0, body.reads)
+}
+
+type countingReader struct {
+	io.Reader
+
+	reads int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	r.reads++
+
+	return r.Reader.Read(p)
+}
+
+func TestServer_LastRequest(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.MockServer(func(s *httpmock.Server) {
+		s.ExpectGet(httpmock.RegexPattern(`^/users`)).Twice()
+	})
+
+	defer s.Close()
+
+	assert.Equal(t, httpmock.JournalEntry{}, s.LastRequest())
+	assert.Empty(t, s.LastRequest().Query("page"))
+
+	doRequest(t, s.URL(), http.MethodGet, "/users?page=2&tag=a&tag=b", nil, nil, 0)
+
+	last := s.LastRequest()
+
+	assert.Equal(t, "2", last.Query("page"))
+	assert.Equal(t, "a", last.Query("tag"))
+	assert.Equal(t, []string{"a", "b"}, last.QueryValues["tag"])
+	assert.Empty(t, last.Query("size"))
+
+	doRequest(t, s.URL(), http.MethodGet, "/users", nil, nil, 0)
+
+	last = s.LastRequest()
+
+	assert.Equal(t, "/users", last.RequestURI)
+	assert.Nil(t, last.QueryValues)
+	assert.Empty(t, last.Query("page"))
+}
+
+func TestServer_SaveJournal(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.MockServer(func(s *httpmock.Server) {
+		s.ExpectPost("/users")
+	})
+
+	defer s.Close()
+
+	doRequest(t, s.URL(), http.MethodPost, "/users", nil, []byte(`{"name":"John Doe"}`), 0)
+	doRequest(t, s.URL(), http.MethodGet, "/unknown?page=2", nil, nil, 0)
+
+	path := filepath.Join(t.TempDir(), "journal.json")
+
+	require.NoError(t, s.SaveJournal(path))
+
+	journal, err := httpmock.LoadJournal(path)
+	require.NoError(t, err)
+
+	expected := s.Journal()
+
+	assert.Len(t, journal, len(expected))
+
+	for i := range expected {
+		assert.True(t, expected[i].Time.Equal(journal[i].Time))
+
+		journal[i].Time = expected[i].Time
+	}
+
+	assert.Equal(t, expected, journal)
+}
+
+func TestServer_SaveJournal_Error(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+
+	defer s.Close()
+
+	err := s.SaveJournal(filepath.Join(t.TempDir(), "not-found", "journal.json"))
+
+	assert.Error(t, err)
+}
+
+func TestLoadJournal_Error(t *testing.T) {
+	t.Parallel()
+
+	journal, err := httpmock.LoadJournal("resources/fixtures/not-found.json")
+
+	assert.Nil(t, journal)
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "journal.json")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{`), 0o600))
+
+	journal, err = httpmock.LoadJournal(path)
+
+	assert.Nil(t, journal)
+	assert.EqualError(t, err, "could not decode journal: unexpected end of JSON input")
+}
@@ -0,0 +1,73 @@
+package httpmock
+
+import (
+	"errors"
+	"math"
+	mathrand "math/rand"
+	"time"
+
+	"go.nhat.io/httpmock/must"
+)
+
+const (
+	// z95 and z99 are the 95th and the 99th percentiles of the standard normal distribution.
+	z95 = 1.6448536269514722
+	z99 = 2.3263478740408408
+	// maxLatencyQuantile caps the tail of the latency profile, so a sample is never unreasonably long.
+	maxLatencyQuantile = 0.9999
+)
+
+// latencyProfile is a log-normal distribution whose 50th and 95th percentiles are p50 and p95. The tail after the 95th
+// percentile is stretched to reach p99 at the 99th percentile.
+type latencyProfile struct {
+	p50, p95    time.Duration
+	sigma, tail float64
+}
+
+func newLatencyProfile(p50, p95, p99 time.Duration) (*latencyProfile, error) {
+	if p50 <= 0 || p95 < p50 || p99 < p95 {
+		return nil, errors.New("could not create latency profile: 0 < p50 <= p95 <= p99 expected") // nolint: goerr113
+	}
+
+	return &latencyProfile{
+		p50:   p50,
+		p95:   p95,
+		sigma: math.Log(float64(p95)/float64(p50)) / z95,
+		tail:  math.Log(float64(p99)/float64(p95)) / (z99 - z95),
+	}, nil
+}
+
+// quantile returns the latency at the quantile q, in [0, 1).
+func (p *latencyProfile) quantile(q float64) time.Duration {
+	q = math.Max(1-maxLatencyQuantile, math.Min(q, maxLatencyQuantile))
+	z := math.Sqrt2 * math.Erfinv(2*q-1)
+
+	if z <= z95 {
+		return time.Duration(float64(p.p50) * math.Exp(p.sigma*z))
+	}
+
+	return time.Duration(float64(p.p95) * math.Exp(p.tail*(z-z95)))
+}
+
+func (p *latencyProfile) sample(random *mathrand.Rand) time.Duration {
+	return p.quantile(random.Float64())
+}
+
+// WithLatencyProfile delays the responses of all the expectations that do not have their own delay, by a random
+// duration from a distribution whose 50th, 95th and 99th percentiles are p50, p95 and p99, so the timeouts and the
+// latency budgets of the clients can be tested against a realistic latency shape. The samples are capped at the
+// 99.99th percentile. It takes precedence over WithDefaultDelay and WithDefaultJitter, and it panics if the percentiles
+// are not in order. See also WithRandSource.
+//
+//	Server.WithLatencyProfile(20*time.Millisecond, 80*time.Millisecond, 250*time.Millisecond)
+func (s *Server) WithLatencyProfile(p50, p95, p99 time.Duration) *Server {
+	profile, err := newLatencyProfile(p50, p95, p99)
+	must.NotFail(err)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latencyProfile = profile
+
+	return s
+}
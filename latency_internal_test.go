@@ -0,0 +1,78 @@
+package httpmock
+
+import (
+	mathrand "math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyProfile_Quantile(t *testing.T) {
+	t.Parallel()
+
+	p, err := newLatencyProfile(20*time.Millisecond, 80*time.Millisecond, 250*time.Millisecond)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 20*time.Millisecond, p.quantile(0.5), float64(time.Microsecond))
+	assert.InDelta(t, 80*time.Millisecond, p.quantile(0.95), float64(time.Microsecond))
+	assert.InDelta(t, 250*time.Millisecond, p.quantile(0.99), float64(time.Microsecond))
+
+	// The tail is capped.
+	assert.Equal(t, p.quantile(maxLatencyQuantile), p.quantile(0.999999))
+	assert.Equal(t, p.quantile(1-maxLatencyQuantile), p.quantile(0))
+	assert.Greater(t, p.quantile(0), time.Duration(0))
+}
+
+func TestLatencyProfile_Sample(t *testing.T) {
+	t.Parallel()
+
+	const samples = 100000
+
+	p, err := newLatencyProfile(20*time.Millisecond, 80*time.Millisecond, 250*time.Millisecond)
+	require.NoError(t, err)
+
+	random := mathrand.New(mathrand.NewSource(42)) // nolint: gosec
+	actual := make([]time.Duration, samples)
+
+	for i := range actual {
+		actual[i] = p.sample(random)
+	}
+
+	sort.Slice(actual, func(i, j int) bool {
+		return actual[i] < actual[j]
+	})
+
+	assert.InEpsilon(t, 20*time.Millisecond, actual[samples*50/100], 0.05)
+	assert.InEpsilon(t, 80*time.Millisecond, actual[samples*95/100], 0.05)
+	assert.InEpsilon(t, 250*time.Millisecond, actual[samples*99/100], 0.05)
+}
+
+func TestNewLatencyProfile_Error(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		p50      time.Duration
+		p95      time.Duration
+		p99      time.Duration
+	}{
+		{scenario: "zero p50", p95: time.Second, p99: time.Second},
+		{scenario: "p95 less than p50", p50: time.Second, p95: time.Millisecond, p99: time.Second},
+		{scenario: "p99 less than p95", p50: time.Millisecond, p95: time.Second, p99: time.Millisecond},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := newLatencyProfile(tc.p50, tc.p95, tc.p99)
+
+			assert.Nil(t, p)
+			assert.EqualError(t, err, "could not create latency profile: 0 < p50 <= p95 <= p99 expected")
+		})
+	}
+}
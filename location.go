@@ -0,0 +1,60 @@
+package httpmock
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+const packagePath = "go.nhat.io/httpmock"
+
+// WithExpectationLocation records the file and the line where every new expectation is declared, and prints it in the
+// mismatch errors and the errors of the unmet expectations, to find the offending expectation in a large suite. The
+// helpers of this module, for example, the presets, are skipped, so the location is the caller of the helpers.
+//
+//	srv := httpmock.NewServer().WithExpectationLocation()
+func (s *Server) WithExpectationLocation() *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expectationLocation = true
+
+	return s
+}
+
+// Location returns the file and the line where the expectation is declared, empty if it is not recorded, see
+// Server.WithExpectationLocation.
+func (e *requestExpectation) Location() string {
+	e.lock()
+	defer e.unlock()
+
+	return e.location
+}
+
+// callerLocation returns the file and the line of the first caller outside this module, or in a test file.
+func callerLocation() string {
+	pc := make([]uintptr, 32)
+	n := runtime.Callers(2, pc)
+	frames := runtime.CallersFrames(pc[:n])
+
+	for {
+		frame, more := frames.Next()
+
+		if !isModuleFrame(frame) {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+
+		if !more {
+			return ""
+		}
+	}
+}
+
+// isModuleFrame checks whether the frame is in the code of this module, not in its tests.
+func isModuleFrame(frame runtime.Frame) bool {
+	if strings.HasSuffix(frame.File, "_test.go") {
+		return false
+	}
+
+	return strings.HasPrefix(frame.Function, packagePath+".") || strings.HasPrefix(frame.Function, packagePath+"/")
+}
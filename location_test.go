@@ -0,0 +1,39 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestServer_WithExpectationLocation(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer().WithExpectationLocation()
+	defer s.Close()
+
+	s.ExpectGet("/users").
+		WithHeader("Authorization", "Bearer token")
+
+	location := regexp.MustCompile(`    declared at .+/location_test\.go:\d+\n`)
+
+	_, _, body, _ := doRequest(t, s.URL(), http.MethodGet, "/users", nil, nil, 0)
+
+	assert.Regexp(t, location, string(body))
+	assert.Regexp(t, location, s.ExpectationsWereMet().Error())
+}
+
+func TestServer_WithoutExpectationLocation(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+	defer s.Close()
+
+	s.ExpectGet("/users")
+
+	assert.NotContains(t, s.ExpectationsWereMet().Error(), "declared at")
+}
@@ -10,12 +10,15 @@ type Matcher = matcher.Matcher
 // ExactMatcher matches by exact string.
 type ExactMatcher = matcher.ExactMatcher
 
+// RegexMatcher matches by a regular expression.
+type RegexMatcher = matcher.RegexMatcher
+
+// JSONMatcher matches two json strings with <ignore-diff> support.
+type JSONMatcher = matcher.JSONMatcher
+
 // Callback matches by calling a function.
 type Callback = matcher.Callback
 
-// Match returns a matcher according to its type.
-var Match = matcher.Match
-
 // JSON matches two json strings with <ignore-diff> support.
 var JSON = matcher.JSON
 
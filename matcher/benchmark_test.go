@@ -0,0 +1,44 @@
+package matcher_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.nhat.io/httpmock/matcher"
+)
+
+func BenchmarkBodyMatcher_Match(b *testing.B) {
+	m := matcher.Body(`{"name":"john"}`)
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"john"}`))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if matched, err := m.Match(req); err != nil || !matched {
+			b.Fatal("body does not match")
+		}
+	}
+}
+
+func BenchmarkHeaderMatcher_Match(b *testing.B) {
+	m := matcher.HeaderMatcher{
+		"Authorization": matcher.Match("Bearer token"),
+		"Content-Type":  matcher.Match("application/json"),
+	}
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer token")
+	header.Set("Content-Type", "application/json")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := m.Match(header); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -27,7 +27,15 @@ func (m *BodyMatcher) Matcher() matcher.Matcher {
 func (m *BodyMatcher) Match(in any) (bool, error) {
 	m.actual = initActual
 
-	actual, err := value.GetBody(in.(*http.Request)) //nolint: errcheck
+	if h, ok := m.matcher.(*HashMatcher); ok {
+		actual, matched, err := h.matchRequest(in.(*http.Request)) //nolint: forcetypeassert
+
+		m.actual = actual
+
+		return matched, err
+	}
+
+	actual, err := value.PeekBody(in.(*http.Request)) //nolint: errcheck
 	if err != nil {
 		return false, err
 	}
@@ -50,6 +58,6 @@ func (m BodyMatcher) Expected() string {
 // Body initiates a new body matcher.
 func Body(v any) *BodyMatcher {
 	return &BodyMatcher{
-		matcher: matcher.Match(v),
+		matcher: Match(v),
 	}
 }
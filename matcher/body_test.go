@@ -1,6 +1,7 @@
 package matcher_test
 
 import (
+	"crypto/sha256"
 	"errors"
 	"io"
 	"regexp"
@@ -99,3 +100,113 @@ func TestBodyMatcher_Expected(t *testing.T) {
 
 	assert.Equal(t, expected, m.Expected())
 }
+
+func TestBodyMatcher_Match_Hash(t *testing.T) {
+	t.Parallel()
+
+	const digest = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+
+	testCases := []struct {
+		scenario       string
+		request        *http.Request
+		expectedResult bool
+		expectedActual string
+		expectedError  error
+	}{
+		{
+			scenario: "read error",
+			request: http.BuildRequest().
+				WithBodyReadError(errors.New("read error")).
+				Build(),
+			expectedActual: `<could not decode>`,
+			expectedError:  errors.New(`read error`),
+		},
+		{
+			scenario: "mismatched",
+			request: http.BuildRequest().
+				WithBody("hello").
+				Build(),
+			expectedActual: `sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824`,
+		},
+		{
+			scenario: "matched",
+			request: http.BuildRequest().
+				WithBody("hello world").
+				Build(),
+			expectedResult: true,
+			expectedActual: `sha256:` + digest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			m := matcher.Body(matcher.BodyHash("sha256", sha256.New, digest))
+			matched, err := m.Match(tc.request)
+
+			assert.Equal(t, tc.expectedResult, matched)
+			assert.Equal(t, tc.expectedActual, m.Actual())
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, `sha256:`+digest, m.Expected())
+		})
+	}
+}
+
+func TestHashMatcher_Match(t *testing.T) {
+	t.Parallel()
+
+	m := matcher.BodyHash("sha256", sha256.New, "B94D27B9934D3E08A52E52D7DA7DABFAC484EFE37A5380EE9088F7ACE2EFCDE9")
+
+	matched, err := m.Match("hello world")
+
+	assert.True(t, matched)
+	assert.NoError(t, err)
+
+	matched, err = m.Match([]byte("hello"))
+
+	assert.False(t, matched)
+	assert.NoError(t, err)
+}
+
+func TestBodyChecksums(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		matcher  *matcher.HashMatcher
+		expected string
+	}{
+		{
+			scenario: "sha256",
+			matcher:  matcher.BodySHA256("b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"),
+			expected: "sha256:b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9",
+		},
+		{
+			scenario: "md5",
+			matcher:  matcher.BodyMD5("5eb63bbbe01eeed093cb22bb8f5acdc3"),
+			expected: "md5:5eb63bbbe01eeed093cb22bb8f5acdc3",
+		},
+		{
+			scenario: "crc32",
+			matcher:  matcher.BodyCRC32("0d4a1185"),
+			expected: "crc32:0d4a1185",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			m := matcher.Body(tc.matcher)
+			matched, err := m.Match(http.BuildRequest().WithBody("hello world").Build())
+
+			assert.True(t, matched)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, m.Expected())
+			assert.Equal(t, tc.expected, m.Actual())
+		})
+	}
+}
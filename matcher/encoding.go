@@ -0,0 +1,95 @@
+package matcher
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.nhat.io/matcher/v2"
+
+	"go.nhat.io/httpmock/value"
+)
+
+var _ matcher.Matcher = (*EncodingMatcher)(nil)
+
+// EncodingMatcher matches an Accept-Encoding header that accepts all the expected encodings.
+type EncodingMatcher struct {
+	encodings []string
+}
+
+// Expected returns the expected encodings, separated by commas.
+func (m EncodingMatcher) Expected() string {
+	return strings.Join(m.encodings, ", ")
+}
+
+// Match checks whether the Accept-Encoding header accepts all the expected encodings. An encoding is accepted if it is
+// listed, or covered by *, with a non-zero quality. The identity encoding is accepted unless it is excluded.
+func (m EncodingMatcher) Match(actual any) (bool, error) {
+	accepted, err := parseAcceptEncoding(value.String(actual))
+	if err != nil {
+		return false, err
+	}
+
+	for _, encoding := range m.encodings {
+		if !acceptsEncoding(accepted, encoding) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// AcceptsEncoding creates a new EncodingMatcher that matches an Accept-Encoding header that accepts all the encodings.
+//
+//	Server.Expect(httpmock.MethodGet, "/path").
+//		WithHeader("Accept-Encoding", matcher.AcceptsEncoding("gzip", "br"))
+func AcceptsEncoding(encodings ...string) *EncodingMatcher {
+	return &EncodingMatcher{encodings: encodings}
+}
+
+// parseAcceptEncoding parses the Accept-Encoding header into the qualities of the encodings, in lower case.
+func parseAcceptEncoding(header string) (map[string]float64, error) {
+	accepted := make(map[string]float64)
+
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		encoding, params, _ := strings.Cut(part, ";")
+		quality := 1.0
+
+		for _, param := range strings.Split(params, ";") {
+			k, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(k), "q") {
+				continue
+			}
+
+			q, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil || q < 0 || q > 1 {
+				return nil, fmt.Errorf("could not parse accept-encoding %q: invalid quality %q", header, v) // nolint: goerr113
+			}
+
+			quality = q
+		}
+
+		accepted[strings.ToLower(strings.TrimSpace(encoding))] = quality
+	}
+
+	return accepted, nil
+}
+
+func acceptsEncoding(accepted map[string]float64, encoding string) bool {
+	encoding = strings.ToLower(encoding)
+
+	if q, ok := accepted[encoding]; ok {
+		return q > 0
+	}
+
+	if q, ok := accepted["*"]; ok {
+		return q > 0
+	}
+
+	return encoding == "identity"
+}
@@ -0,0 +1,96 @@
+package matcher_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock/matcher"
+)
+
+func TestAcceptsEncoding(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario       string
+		encodings      []string
+		header         string
+		expectedResult bool
+		expectedError  string
+	}{
+		{
+			scenario:       "listed",
+			encodings:      []string{"gzip"},
+			header:         "gzip",
+			expectedResult: true,
+		},
+		{
+			scenario:       "all listed",
+			encodings:      []string{"gzip", "BR"},
+			header:         "br;q=0.8, deflate, GZIP",
+			expectedResult: true,
+		},
+		{
+			scenario:  "not listed",
+			encodings: []string{"gzip", "br"},
+			header:    "gzip, deflate",
+		},
+		{
+			scenario:  "zero quality",
+			encodings: []string{"gzip"},
+			header:    "gzip;q=0, br",
+		},
+		{
+			scenario:       "wildcard",
+			encodings:      []string{"br"},
+			header:         "gzip, *;q=0.1",
+			expectedResult: true,
+		},
+		{
+			scenario:  "wildcard excluded",
+			encodings: []string{"br"},
+			header:    "gzip, br;q=0, *",
+		},
+		{
+			scenario:       "identity",
+			encodings:      []string{"identity"},
+			header:         "",
+			expectedResult: true,
+		},
+		{
+			scenario:  "identity excluded",
+			encodings: []string{"identity"},
+			header:    "gzip, *;q=0",
+		},
+		{
+			scenario:      "invalid quality",
+			encodings:     []string{"gzip"},
+			header:        "gzip;q=high",
+			expectedError: `could not parse accept-encoding "gzip;q=high": invalid quality "high"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			m := matcher.AcceptsEncoding(tc.encodings...)
+			matched, err := m.Match(tc.header)
+
+			assert.Equal(t, tc.expectedResult, matched)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestAcceptsEncoding_Expected(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "gzip, br", matcher.AcceptsEncoding("gzip", "br").Expected())
+}
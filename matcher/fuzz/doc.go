@@ -0,0 +1,3 @@
+// Package fuzz provides the fuzzing helpers for the body matchers, so the test binaries link the testing package, not
+// the servers.
+package fuzz
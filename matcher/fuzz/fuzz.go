@@ -0,0 +1,93 @@
+package fuzz
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go.nhat.io/httpmock/matcher"
+)
+
+// Corpus returns the malformed bodies to seed the fuzzing of the body matchers, for example, a truncated or a deeply
+// nested JSON, an invalid UTF-8 sequence, or an unbalanced XML. A new copy is returned on every call.
+func Corpus() [][]byte {
+	corpus := []string{
+		"",
+		" ",
+		"null",
+		"{",
+		"}",
+		"[",
+		"]",
+		`{"`,
+		`{"id":}`,
+		`{"id":42,}`,
+		`{"id":42}{"id":43}`,
+		`[1,2`,
+		strings.Repeat("[", 1000),
+		strings.Repeat(`{"a":`, 1000),
+		`1e999999`,
+		`-`,
+		`"\u00"`,
+		"\xff\xfe\xfd",
+		"\xef\xbb\xbf{}",
+		"\x00",
+		"{\"id\":\"\x00\"}",
+		"<",
+		"<a>",
+		"</a>",
+		"<a></b>",
+		"<a><b></a></b>",
+		`<?xml version="1.0"?>`,
+		`<?xml version="1.0" encoding="unknown"?><a/>`,
+		`<!DOCTYPE a [<!ENTITY x "x">]><a>&x;</a>`,
+		`<a>&unknown;</a>`,
+		`<a xmlns:b=""><b:c/></a>`,
+		`<a b="1" b="2"/>`,
+		strings.Repeat("<a>", 1000),
+	}
+
+	result := make([][]byte, len(corpus))
+
+	for i, body := range corpus {
+		result[i] = []byte(body)
+	}
+
+	return result
+}
+
+// Matcher fuzzes the matcher as a body matcher, with the bodies of Corpus and the seeds, so the malformed bodies
+// do not make the matcher panic while a server is handling the requests. The matcher could return an error instead.
+//
+//	func FuzzUserMatcher(f *testing.F) {
+//		fuzz.Matcher(f, newUserMatcher(), []byte(`{"id":42}`))
+//	}
+func Matcher(f *testing.F, m matcher.Matcher, seeds ...[]byte) {
+	f.Helper()
+
+	for _, body := range Corpus() {
+		f.Add(body)
+	}
+
+	for _, body := range seeds {
+		f.Add(body)
+	}
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		t.Helper()
+
+		req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader(body)) // nolint: noctx
+		if err != nil {
+			t.Fatalf("could not create request: %s", err.Error())
+		}
+
+		defer func() {
+			if p := recover(); p != nil {
+				t.Fatalf("matcher %q panicked with body %q: %v", m.Expected(), body, p)
+			}
+		}()
+
+		_, _ = matcher.Body(m).Match(req) // nolint: errcheck
+	})
+}
@@ -0,0 +1,31 @@
+package fuzz_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock/matcher"
+	"go.nhat.io/httpmock/matcher/fuzz"
+)
+
+func FuzzJSONMatcher(f *testing.F) {
+	fuzz.Matcher(f, matcher.JSON(`{"id":42,"name":"<ignore-diff>"}`), []byte(`{"id":42,"name":"john"}`))
+}
+
+func FuzzXMLMatcher(f *testing.F) {
+	fuzz.Matcher(f, matcher.XML(`<user id="42"><name>john</name></user>`), []byte(`<user id="42"><name>john</name></user>`))
+}
+
+func FuzzXPathMatcher(f *testing.F) {
+	fuzz.Matcher(f, matcher.XPath(`/user/@id`, "42"), []byte(`<user id="42"/>`))
+}
+
+func TestCorpus(t *testing.T) {
+	t.Parallel()
+
+	corpus := fuzz.Corpus()
+	corpus[0] = append(corpus[0], 'x')
+
+	assert.Empty(t, fuzz.Corpus()[0], "the corpus is shared")
+}
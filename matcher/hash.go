@@ -0,0 +1,93 @@
+package matcher
+
+import (
+	"crypto/md5" // nolint: gosec
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"net/http"
+	"strings"
+
+	"go.nhat.io/matcher/v2"
+
+	"go.nhat.io/httpmock/value"
+)
+
+var _ matcher.Matcher = (*HashMatcher)(nil)
+
+// HashMatcher matches the digest of a body. When it is used as a body matcher, the body is hashed incrementally while it
+// is read from the request, without being buffered if the server does not capture the bodies, so a large or chunked
+// upload can be asserted without storing a fixture.
+type HashMatcher struct {
+	name     string
+	newHash  func() hash.Hash
+	expected string
+}
+
+// Expected returns the expected digest, prefixed by the name of the hash, for example, sha256:2cf24dba...
+func (m HashMatcher) Expected() string {
+	return fmt.Sprintf("%s:%s", m.name, m.expected)
+}
+
+// Match hashes a string or a []byte and compares its digest.
+func (m HashMatcher) Match(actual any) (bool, error) {
+	h := m.newHash()
+
+	_, _ = h.Write([]byte(value.String(actual))) //nolint: errcheck
+
+	return m.matchDigest(h.Sum(nil)), nil
+}
+
+// matchRequest hashes the request body and returns its digest, prefixed by the name of the hash.
+func (m HashMatcher) matchRequest(r *http.Request) (string, bool, error) {
+	digest, err := value.HashBody(r, m.name, m.newHash)
+	if err != nil {
+		return initActual, false, err
+	}
+
+	return fmt.Sprintf("%s:%x", m.name, digest), m.matchDigest(digest), nil
+}
+
+func (m HashMatcher) matchDigest(digest []byte) bool {
+	return strings.EqualFold(m.expected, hex.EncodeToString(digest))
+}
+
+// BodyHash creates a new HashMatcher that matches the hex-encoded digest of the body, hashed by the hash function.
+//
+//	Server.Expect(httpmock.MethodPost, "/upload").
+//		WithBody(matcher.BodyHash("sha512", sha512.New, "9b71d224..."))
+func BodyHash(name string, newHash func() hash.Hash, hexDigest string) *HashMatcher {
+	return &HashMatcher{
+		name:     name,
+		newHash:  newHash,
+		expected: hexDigest,
+	}
+}
+
+// BodySHA256 creates a new HashMatcher that matches the hex-encoded SHA-256 digest of the body.
+//
+//	Server.Expect(httpmock.MethodPost, "/upload").
+//		WithBody(matcher.BodySHA256("b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"))
+func BodySHA256(hexDigest string) *HashMatcher {
+	return BodyHash("sha256", sha256.New, hexDigest)
+}
+
+// BodyMD5 creates a new HashMatcher that matches the hex-encoded MD5 digest of the body.
+//
+//	Server.Expect(httpmock.MethodPost, "/upload").
+//		WithBody(matcher.BodyMD5("5eb63bbbe01eeed093cb22bb8f5acdc3"))
+func BodyMD5(hexDigest string) *HashMatcher {
+	return BodyHash("md5", md5.New, hexDigest)
+}
+
+// BodyCRC32 creates a new HashMatcher that matches the hex-encoded CRC-32 (IEEE) checksum of the body.
+//
+//	Server.Expect(httpmock.MethodPost, "/upload").
+//		WithBody(matcher.BodyCRC32("0d4a1185"))
+func BodyCRC32(hexDigest string) *HashMatcher {
+	return BodyHash("crc32", func() hash.Hash {
+		return crc32.NewIEEE()
+	}, hexDigest)
+}
@@ -1,31 +1,87 @@
 package matcher
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
+	"text/tabwriter"
 )
 
 // HeaderMatcher matches the header values.
 type HeaderMatcher map[string]Matcher
 
-// Match matches the header in context.
+// Match matches the header in context. If more than one header is expected and one of them is mismatched, the error
+// contains a table of all the expected headers and whether they are matched.
 func (m HeaderMatcher) Match(header http.Header) error {
 	if len(m) == 0 {
 		return nil
 	}
 
-	for h, m := range m {
+	headers := make([]string, 0, len(m))
+
+	for h := range m {
+		headers = append(headers, h)
+	}
+
+	sort.Strings(headers)
+
+	var (
+		mismatch error
+		results  = make([]headerResult, 0, len(headers))
+	)
+
+	for _, h := range headers {
+		expected := m[h]
 		value := header.Get(h)
 
-		matched, err := m.Match(value)
+		matched, err := expected.Match(value)
 		if err != nil {
 			return fmt.Errorf("could not match header: %w", err)
 		}
 
-		if !matched {
-			return fmt.Errorf("header %q with value %q expected, %q received", h, m.Expected(), value) // nolint: goerr113
+		if !matched && mismatch == nil {
+			mismatch = fmt.Errorf("header %q with value %q expected, %q received", h, expected.Expected(), value) // nolint: goerr113
 		}
+
+		results = append(results, headerResult{header: h, expected: expected.Expected(), received: value, matched: matched})
 	}
 
-	return nil
+	if mismatch == nil || len(results) == 1 {
+		return mismatch
+	}
+
+	return errors.New(mismatch.Error() + "\n" + formatHeaderResults(results)) // nolint: goerr113
+}
+
+// headerResult is the result of matching an expected header.
+type headerResult struct {
+	header   string
+	expected string
+	received string
+	matched  bool
+}
+
+// formatHeaderResults formats the results of matching the expected headers in a table.
+func formatHeaderResults(results []headerResult) string {
+	var sb strings.Builder
+
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(w, "    RESULT\tHEADER\tEXPECTED\tRECEIVED") //nolint: errcheck
+
+	for _, r := range results {
+		result := "PASS"
+
+		if !r.matched {
+			result = "FAIL"
+		}
+
+		_, _ = fmt.Fprintf(w, "    %s\t%s\t%q\t%q\n", result, r.header, r.expected, r.received) //nolint: errcheck
+	}
+
+	_ = w.Flush() //nolint: errcheck
+
+	return strings.TrimSuffix(sb.String(), "\n")
 }
@@ -46,6 +46,23 @@ func TestHeaderMatcher_Match(t *testing.T) {
 			},
 			expectedError: `header "Authorization" with value "Bearer token" expected, "Bearer foobar" received`,
 		},
+		{
+			scenario: "multiple headers mismatched",
+			matcher: matcher.HeaderMatcher{
+				"Authorization": matcher.Match("Bearer token"),
+				"X-Request-Id":  matcher.Match("42"),
+				"Accept":        matcher.Match("application/json"),
+			},
+			header: map[string][]string{
+				"Authorization": {"Bearer token"},
+				"X-Request-Id":  {"43"},
+			},
+			expectedError: `header "Accept" with value "application/json" expected, "" received
+    RESULT  HEADER         EXPECTED            RECEIVED
+    FAIL    Accept         "application/json"  ""
+    PASS    Authorization  "Bearer token"      "Bearer token"
+    FAIL    X-Request-Id   "42"                "43"`,
+		},
 		{
 			scenario: "matched",
 			matcher: matcher.HeaderMatcher{
@@ -0,0 +1,133 @@
+package matcher
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"go.nhat.io/matcher/v2"
+)
+
+var _ matcher.Matcher = (*PathTemplateMatcher)(nil)
+
+// pathParamPattern matches the parameters of a path template, for example, {id}.
+var pathParamPattern = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// PathTemplateMatcher matches a request uri by a template with path parameters, for example, /users/{id}. A parameter
+// matches a non-empty path segment, and it could be constrained by a matcher, see WithParam.
+type PathTemplateMatcher struct {
+	template string
+	pattern  *regexp.Regexp
+	names    []string
+	// params are the constraints of the parameters, by name.
+	params map[string]Matcher
+}
+
+// Expected returns the template.
+func (m *PathTemplateMatcher) Expected() string {
+	return m.template
+}
+
+// Match checks whether the uri matches the template and the constraints of the parameters.
+func (m *PathTemplateMatcher) Match(actual any) (bool, error) {
+	uri, ok := actual.(string)
+	if !ok {
+		return false, nil
+	}
+
+	values, ok := m.Params(uri)
+	if !ok {
+		return false, nil
+	}
+
+	for name, p := range m.params {
+		matched, err := p.Match(values[name])
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// Params returns the unescaped values of the parameters in the uri, the second result is false if the uri does not
+// match the template. The constraints of the parameters are not checked.
+func (m *PathTemplateMatcher) Params(uri string) (map[string]string, bool) {
+	found := m.pattern.FindStringSubmatch(uri)
+	if found == nil {
+		return nil, false
+	}
+
+	values := make(map[string]string, len(m.names))
+
+	for i, name := range m.names {
+		v, err := url.PathUnescape(found[i+1])
+		if err != nil {
+			v = found[i+1]
+		}
+
+		values[name] = v
+	}
+
+	return values, true
+}
+
+// HasParam checks whether the template has the parameter.
+func (m *PathTemplateMatcher) HasParam(name string) bool {
+	for _, n := range m.names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithParam returns a copy of the matcher with the constraint of the parameter. The value could be a string or a
+// Matcher.
+func (m *PathTemplateMatcher) WithParam(name string, value any) *PathTemplateMatcher {
+	c := *m
+	c.params = make(map[string]Matcher, len(m.params)+1)
+
+	for k, v := range m.params {
+		c.params[k] = v
+	}
+
+	c.params[name] = Match(value)
+
+	return &c
+}
+
+// PathTemplate matches a request uri by a template with path parameters, for example, /users/{id}/orders/{orderID}. A
+// parameter is a name in braces, and it matches a non-empty path segment. The rest of the template, including the query,
+// is matched exactly.
+//
+//	Server.Expect(httpmock.MethodGet, matcher.PathTemplate("/users/{id}"))
+func PathTemplate(template string) *PathTemplateMatcher {
+	locs := pathParamPattern.FindAllStringSubmatchIndex(template, -1)
+
+	var (
+		sb    strings.Builder
+		names = make([]string, 0, len(locs))
+		last  int
+	)
+
+	sb.WriteString("^")
+
+	for _, loc := range locs {
+		sb.WriteString(regexp.QuoteMeta(template[last:loc[0]]))
+		sb.WriteString(`([^/?#]+)`)
+
+		names = append(names, template[loc[2]:loc[3]])
+		last = loc[1]
+	}
+
+	sb.WriteString(regexp.QuoteMeta(template[last:]))
+	sb.WriteString("$")
+
+	return &PathTemplateMatcher{
+		template: template,
+		pattern:  regexp.MustCompile(sb.String()),
+		names:    names,
+	}
+}
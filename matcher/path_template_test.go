@@ -0,0 +1,91 @@
+package matcher_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock/matcher"
+)
+
+func TestPathTemplate(t *testing.T) {
+	t.Parallel()
+
+	m := matcher.PathTemplate("/users/{id}/orders/{orderID}?v=1")
+
+	testCases := []struct {
+		scenario       string
+		uri            any
+		expectedResult bool
+		expectedParams map[string]string
+	}{
+		{
+			scenario:       "match",
+			uri:            "/users/42/orders/a%20b?v=1",
+			expectedResult: true,
+			expectedParams: map[string]string{"id": "42", "orderID": "a b"},
+		},
+		{
+			scenario: "empty segment",
+			uri:      "/users//orders/1?v=1",
+		},
+		{
+			scenario: "extra segment",
+			uri:      "/users/42/orders/1/items?v=1",
+		},
+		{
+			scenario: "different query",
+			uri:      "/users/42/orders/1?v=2",
+		},
+		{
+			scenario: "not a string",
+			uri:      42,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			matched, err := m.Match(tc.uri)
+
+			assert.Equal(t, tc.expectedResult, matched)
+			assert.NoError(t, err)
+
+			if uri, ok := tc.uri.(string); ok {
+				params, ok := m.Params(uri)
+
+				assert.Equal(t, tc.expectedResult, ok)
+				assert.Equal(t, tc.expectedParams, params)
+			}
+		})
+	}
+}
+
+func TestPathTemplate_WithParam(t *testing.T) {
+	t.Parallel()
+
+	m := matcher.PathTemplate("/users/{id}")
+	constrained := m.WithParam("id", matcher.RegexPattern(`^\d+$`))
+
+	assert.True(t, m.HasParam("id"))
+	assert.False(t, m.HasParam("name"))
+	assert.Equal(t, "/users/{id}", constrained.Expected())
+
+	matched, err := constrained.Match("/users/john")
+
+	assert.False(t, matched)
+	assert.NoError(t, err)
+
+	matched, err = constrained.Match("/users/42")
+
+	assert.True(t, matched)
+	assert.NoError(t, err)
+
+	// The original matcher is not changed.
+	matched, err = m.Match("/users/john")
+
+	assert.True(t, matched)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,51 @@
+package matcher
+
+import (
+	"reflect"
+	"sync"
+
+	"go.nhat.io/matcher/v2"
+)
+
+// valueMatchers contains the functions to convert the values of the registered types to matchers.
+var valueMatchers sync.Map // map[reflect.Type]func(v any) Matcher
+
+// RegisterValueMatcher registers a function to convert the values of a type to a matcher, so they can be used as the
+// expectations, for example, a domain struct to a JSON matcher. The type must be the exact type of the values, a
+// registration of an interface type is not used.
+//
+//	matcher.RegisterValueMatcher(func(u User) matcher.Matcher {
+//		b, _ := json.Marshal(u)
+//
+//		return matcher.JSON(string(b))
+//	})
+func RegisterValueMatcher[T any](fn func(v T) Matcher) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	valueMatchers.Store(t, func(v any) Matcher {
+		return fn(v.(T)) // nolint: forcetypeassert
+	})
+}
+
+// HasValueMatcher checks whether the type of the value is registered by RegisterValueMatcher.
+func HasValueMatcher(v any) bool {
+	if v == nil {
+		return false
+	}
+
+	_, ok := valueMatchers.Load(reflect.TypeOf(v))
+
+	return ok
+}
+
+// Match returns a matcher according to its type. The values of the types registered by RegisterValueMatcher are
+// converted by the registered functions.
+func Match(v any) Matcher {
+	if v != nil {
+		if fn, ok := valueMatchers.Load(reflect.TypeOf(v)); ok {
+			return fn.(func(v any) Matcher)(v) // nolint: forcetypeassert
+		}
+	}
+
+	return matcher.Match(v)
+}
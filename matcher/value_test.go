@@ -0,0 +1,37 @@
+package matcher_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock/matcher"
+)
+
+type userID int
+
+func TestRegisterValueMatcher(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, matcher.HasValueMatcher(userID(42)))
+	assert.False(t, matcher.HasValueMatcher(nil))
+
+	matcher.RegisterValueMatcher(func(id userID) matcher.Matcher {
+		return matcher.Exact(fmt.Sprintf("/users/%d", id))
+	})
+
+	assert.True(t, matcher.HasValueMatcher(userID(42)))
+
+	m := matcher.Match(userID(42))
+
+	assert.Equal(t, "/users/42", m.Expected())
+
+	matched, err := m.Match("/users/42")
+
+	assert.True(t, matched)
+	assert.NoError(t, err)
+
+	// The other types are not affected.
+	assert.Equal(t, "42", matcher.Match("42").Expected())
+}
@@ -0,0 +1,314 @@
+package matcher
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"go.nhat.io/matcher/v2"
+
+	"go.nhat.io/httpmock/must"
+	"go.nhat.io/httpmock/value"
+)
+
+var (
+	_ matcher.Matcher = (*XMLMatcher)(nil)
+	_ matcher.Matcher = (*XPathMatcher)(nil)
+)
+
+// errXMLNoRoot indicates that the document does not have a root element.
+var errXMLNoRoot = errors.New("xml document has no root element")
+
+// xmlNode is an element of a parsed xml document, the names are resolved to their namespaces.
+type xmlNode struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	text     string
+	children []*xmlNode
+}
+
+func (n *xmlNode) equal(other *xmlNode) bool {
+	if n.name != other.name || n.text != other.text ||
+		len(n.attrs) != len(other.attrs) || len(n.children) != len(other.children) {
+		return false
+	}
+
+	for i := range n.attrs {
+		if n.attrs[i] != other.attrs[i] {
+			return false
+		}
+	}
+
+	for i := range n.children {
+		if !n.children[i].equal(other.children[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseXML parses a xml document. The namespace prefixes, the namespace declarations, the order of the attributes and
+// the whitespaces between the elements are not kept.
+func parseXML(data string) (*xmlNode, error) {
+	dec := xml.NewDecoder(strings.NewReader(data))
+
+	var (
+		root  *xmlNode
+		stack []*xmlNode
+	)
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("could not decode xml: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &xmlNode{name: t.Name, attrs: xmlAttrs(t.Attr)}
+
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			} else if root == nil {
+				root = n
+			}
+
+			stack = append(stack, n)
+
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += strings.TrimSpace(string(t))
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, errXMLNoRoot
+	}
+
+	return root, nil
+}
+
+func xmlAttrs(attrs []xml.Attr) []xml.Attr {
+	result := make([]xml.Attr, 0, len(attrs))
+
+	for _, a := range attrs {
+		// The namespace declarations are already resolved in the names.
+		if a.Name.Space == "xmlns" || (a.Name.Space == "" && a.Name.Local == "xmlns") {
+			continue
+		}
+
+		result = append(result, a)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name.Space != result[j].Name.Space {
+			return result[i].Name.Space < result[j].Name.Space
+		}
+
+		return result[i].Name.Local < result[j].Name.Local
+	})
+
+	return result
+}
+
+// XMLMatcher matches two xml documents by their namespaces instead of their prefixes, so the documents that only differ
+// in the prefixes, the order of the attributes, or the whitespaces between the elements are the same.
+type XMLMatcher struct {
+	expected string
+	root     *xmlNode
+}
+
+// Expected returns the expected document.
+func (m XMLMatcher) Expected() string {
+	return m.expected
+}
+
+// Match determines if the actual document is the same as the expected one.
+func (m XMLMatcher) Match(actual any) (bool, error) {
+	root, err := parseXML(value.String(actual))
+	if err != nil {
+		return false, err
+	}
+
+	return m.root.equal(root), nil
+}
+
+// XML creates a new XMLMatcher. It panics if the expected document is not valid.
+//
+//	Server.Expect(httpmock.MethodPost, "/soap").
+//		WithBody(matcher.XML(`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/">...</s:Envelope>`))
+func XML(expected string) *XMLMatcher {
+	root, err := parseXML(expected)
+	must.NotFail(err)
+
+	return &XMLMatcher{
+		expected: expected,
+		root:     root,
+	}
+}
+
+// XPathMatcher matches the values of the xml document at the paths.
+type XPathMatcher struct {
+	paths    []string
+	matchers map[string]Matcher
+}
+
+// Expected returns the expected values of the paths.
+func (m XPathMatcher) Expected() string {
+	var sb strings.Builder
+
+	for i, p := range m.paths {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+
+		_, _ = fmt.Fprintf(&sb, "%s = %s", p, m.matchers[p].Expected()) //nolint: errcheck
+	}
+
+	return sb.String()
+}
+
+// Match determines if the values of the actual document at the paths are expected.
+func (m XPathMatcher) Match(actual any) (bool, error) {
+	root, err := parseXML(value.String(actual))
+	if err != nil {
+		return false, err
+	}
+
+	for _, p := range m.paths {
+		v, ok := evalXPath(root, p)
+		if !ok {
+			return false, nil
+		}
+
+		matched, err := m.matchers[p].Match(v)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// XPath creates a new XPathMatcher that matches the value at the path of the xml document. The value could be a string,
+// a []byte, or a Matcher.
+//
+// Only a subset of XPath is supported: the absolute paths (/Envelope/Body/GetUser/Id), the descendants (//Id), and the
+// attributes (/Envelope/Body/GetUser/@id). The names are compared without the namespace prefixes, so soap:Body and Body
+// are the same step.
+//
+//	Server.Expect(httpmock.MethodPost, "/soap").
+//		WithBody(matcher.XPath("//GetUser/Id", "42"))
+func XPath(path string, expected any) *XPathMatcher {
+	return XPaths(map[string]any{path: expected})
+}
+
+// XPaths creates a new XPathMatcher that matches the values at the paths of the xml document, see XPath.
+//
+//	Server.Expect(httpmock.MethodPost, "/soap").
+//		WithBody(matcher.XPaths(map[string]any{
+//			"//GetUser/Id":      "42",
+//			"//GetUser/@locale": matcher.RegexPattern("^en"),
+//		}))
+func XPaths(expected map[string]any) *XPathMatcher {
+	m := &XPathMatcher{
+		paths:    make([]string, 0, len(expected)),
+		matchers: make(map[string]Matcher, len(expected)),
+	}
+
+	for p, v := range expected {
+		m.paths = append(m.paths, p)
+		m.matchers[p] = Match(v)
+	}
+
+	sort.Strings(m.paths)
+
+	return m
+}
+
+// evalXPath returns the text or the attribute value of the first node at the path.
+func evalXPath(root *xmlNode, path string) (string, bool) {
+	var attr string
+
+	if i := strings.LastIndex(path, "/@"); i >= 0 {
+		path, attr = path[:i], localName(path[i+2:])
+	}
+
+	// The root is the child of the document.
+	doc := &xmlNode{children: []*xmlNode{root}}
+	nodes := []*xmlNode{doc}
+
+	for path != "" {
+		descendant := strings.HasPrefix(path, "//")
+		path = strings.TrimLeft(path, "/")
+
+		step := path
+		if i := strings.IndexByte(path, '/'); i >= 0 {
+			step, path = path[:i], path[i:]
+		} else {
+			path = ""
+		}
+
+		nodes = xpathStep(nodes, localName(step), descendant)
+	}
+
+	for _, n := range nodes {
+		if attr == "" {
+			return n.text, true
+		}
+
+		for _, a := range n.attrs {
+			if a.Name.Local == attr {
+				return a.Value, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func xpathStep(nodes []*xmlNode, name string, descendant bool) []*xmlNode {
+	var result []*xmlNode
+
+	var walk func(n *xmlNode)
+
+	walk = func(n *xmlNode) {
+		for _, c := range n.children {
+			if name == "*" || c.name.Local == name {
+				result = append(result, c)
+			}
+
+			if descendant {
+				walk(c)
+			}
+		}
+	}
+
+	for _, n := range nodes {
+		walk(n)
+	}
+
+	return result
+}
+
+// localName removes the namespace prefix of the name.
+func localName(name string) string {
+	if i := strings.IndexByte(name, ':'); i >= 0 {
+		return name[i+1:]
+	}
+
+	return name
+}
@@ -0,0 +1,170 @@
+package matcher_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock/matcher"
+)
+
+const soapEnvelope = `<?xml version="1.0" encoding="UTF-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:u="urn:users">
+    <soap:Body>
+        <u:GetUser locale="en-US" version="2">
+            <u:Id>42</u:Id>
+        </u:GetUser>
+    </soap:Body>
+</soap:Envelope>`
+
+func TestXMLMatcher_Match(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario       string
+		actual         string
+		expectedResult bool
+		expectedError  string
+	}{
+		{
+			scenario:      "invalid xml",
+			actual:        `<Envelope>`,
+			expectedError: "could not decode xml: XML syntax error on line 1: unexpected EOF",
+		},
+		{
+			scenario:      "no root",
+			actual:        ``,
+			expectedError: "xml document has no root element",
+		},
+		{
+			scenario:       "same document",
+			actual:         soapEnvelope,
+			expectedResult: true,
+		},
+		{
+			scenario: "different prefixes and attribute order",
+			actual: `<env:Envelope xmlns:env="http://schemas.xmlsoap.org/soap/envelope/"><env:Body>` +
+				`<GetUser xmlns="urn:users" version="2" locale="en-US"><Id>42</Id></GetUser>` +
+				`</env:Body></env:Envelope>`,
+			expectedResult: true,
+		},
+		{
+			scenario: "different namespace",
+			actual: `<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope" xmlns:u="urn:users">` +
+				`<soap:Body><u:GetUser locale="en-US" version="2"><u:Id>42</u:Id></u:GetUser></soap:Body></soap:Envelope>`,
+		},
+		{
+			scenario: "different value",
+			actual: `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:u="urn:users">` +
+				`<soap:Body><u:GetUser locale="en-US" version="2"><u:Id>43</u:Id></u:GetUser></soap:Body></soap:Envelope>`,
+		},
+		{
+			scenario: "different attribute",
+			actual: `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:u="urn:users">` +
+				`<soap:Body><u:GetUser locale="en-US"><u:Id>42</u:Id></u:GetUser></soap:Body></soap:Envelope>`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			m := matcher.XML(soapEnvelope)
+			matched, err := m.Match(tc.actual)
+
+			assert.Equal(t, tc.expectedResult, matched)
+			assert.Equal(t, soapEnvelope, m.Expected())
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestXML_Panic(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		matcher.XML(`<Envelope>`)
+	})
+}
+
+func TestXPathMatcher_Match(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario       string
+		matcher        *matcher.XPathMatcher
+		expected       string
+		expectedResult bool
+	}{
+		{
+			scenario:       "absolute path",
+			matcher:        matcher.XPath("/soap:Envelope/soap:Body/GetUser/Id", "42"),
+			expected:       "/soap:Envelope/soap:Body/GetUser/Id = 42",
+			expectedResult: true,
+		},
+		{
+			scenario:       "descendant",
+			matcher:        matcher.XPath("//Id", "42"),
+			expected:       "//Id = 42",
+			expectedResult: true,
+		},
+		{
+			scenario:       "wildcard",
+			matcher:        matcher.XPath("/Envelope/*/GetUser/Id", "42"),
+			expected:       "/Envelope/*/GetUser/Id = 42",
+			expectedResult: true,
+		},
+		{
+			scenario: "many paths",
+			matcher: matcher.XPaths(map[string]any{
+				"//GetUser/@locale": matcher.RegexPattern("^en"),
+				"//u:Id":            "42",
+			}),
+			expected:       "//GetUser/@locale = ^en, //u:Id = 42",
+			expectedResult: true,
+		},
+		{
+			scenario: "mismatched value",
+			matcher:  matcher.XPath("//Id", "43"),
+			expected: "//Id = 43",
+		},
+		{
+			scenario: "path not found",
+			matcher:  matcher.XPath("/Envelope/Header", ""),
+			expected: "/Envelope/Header = ",
+		},
+		{
+			scenario: "attribute not found",
+			matcher:  matcher.XPath("//GetUser/@id", ""),
+			expected: "//GetUser/@id = ",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			matched, err := tc.matcher.Match(soapEnvelope)
+
+			assert.Equal(t, tc.expectedResult, matched)
+			assert.Equal(t, tc.expected, tc.matcher.Expected())
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestXPathMatcher_Match_InvalidXML(t *testing.T) {
+	t.Parallel()
+
+	matched, err := matcher.XPath("//Id", "42").Match(`<Envelope>`)
+
+	assert.False(t, matched)
+	assert.EqualError(t, err, "could not decode xml: XML syntax error on line 1: unexpected EOF")
+}
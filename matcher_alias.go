@@ -2,9 +2,6 @@ package httpmock
 
 import "go.nhat.io/matcher/v2"
 
-// Match returns a matcher according to its type.
-var Match = matcher.Match
-
 // JSON matches two json strings with <ignore-diff> support.
 var JSON = matcher.JSON
 
@@ -0,0 +1,73 @@
+package httpmock
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"go.nhat.io/httpmock/planner"
+)
+
+// WithMethodNotAllowed responds 405 Method Not Allowed with the Allow header when the request uri matches an
+// expectation but the method does not, instead of failing the test. It is useful to exercise how the clients handle
+// 405.
+//
+//	Server.WithMethodNotAllowed()
+func (s *Server) WithMethodNotAllowed() *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.methodNotAllowed = true
+
+	return s
+}
+
+// allowedMethods returns the methods of the expectations that match the request uri, or nil if there is an expectation
+// for the request method. The caller must hold the lock.
+func (s *Server) allowedMethods(r *http.Request) []string {
+	if !s.methodNotAllowed {
+		return nil
+	}
+
+	allowed := make(map[string]struct{})
+
+	for _, expected := range s.planner.Remain() {
+		if planner.MatchURI(expected, r) != nil {
+			continue
+		}
+
+		if expected.Method() == r.Method {
+			return nil
+		}
+
+		allowed[expected.Method()] = struct{}{}
+
+		if s.autoHead && expected.Method() == http.MethodGet {
+			allowed[http.MethodHead] = struct{}{}
+		}
+	}
+
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	result := make([]string, 0, len(allowed))
+
+	for method := range allowed {
+		result = append(result, method)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter, r *http.Request, allowed []string) string {
+	msg := fmt.Sprintf("method not allowed: %s %s", r.Method, r.RequestURI)
+
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	http.Error(w, msg, http.StatusMethodNotAllowed)
+
+	return msg
+}
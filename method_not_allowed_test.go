@@ -0,0 +1,69 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/planner"
+)
+
+func TestServer_WithMethodNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	testingT := T()
+
+	s := httpmock.MockServer(func(s *httpmock.Server) {
+		s.WithTest(testingT).
+			WithPlanner(planner.FirstMatch()).
+			WithAutoHead().
+			WithMethodNotAllowed()
+
+		s.ExpectGet("/users")
+		s.ExpectPost("/users").WithHeader("Authorization", "Bearer token")
+		s.ExpectDelete("/items")
+	})
+
+	defer s.Close()
+
+	code, headers, body, _ := doRequest(t, s.URL(), http.MethodPut, "/users", nil, nil, 0)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+	assert.Equal(t, "GET, HEAD, POST", headers["Allow"])
+	assert.Equal(t, "method not allowed: PUT /users\n", string(body))
+	assert.Empty(t, testingT.String())
+
+	journal := s.Journal()
+
+	assert.Len(t, journal, 1)
+	assert.False(t, journal[0].Matched)
+	assert.Equal(t, "method not allowed: PUT /users", journal[0].Error)
+
+	// The method matches but the header does not.
+	code, _, _, _ = doRequest(t, s.URL(), http.MethodPost, "/users", nil, nil, 0)
+
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Contains(t, testingT.String(), `Error: header "Authorization" with value "Bearer token" expected, "" received`)
+}
+
+func TestServer_WithoutMethodNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	testingT := T()
+
+	s := httpmock.MockServer(func(s *httpmock.Server) {
+		s.WithTest(testingT)
+
+		s.ExpectGet("/users")
+	})
+
+	defer s.Close()
+
+	code, headers, _, _ := doRequest(t, s.URL(), http.MethodPut, "/users", nil, nil, 0)
+
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Empty(t, headers["Allow"])
+	assert.Contains(t, testingT.String(), `Error: method "GET" expected, "PUT" received`)
+}
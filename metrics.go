@@ -0,0 +1,114 @@
+package httpmock
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const metricsPath = "/metrics"
+
+// metricsBuckets are the upper bounds of the request duration histogram, in seconds.
+var metricsBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// metrics collects the metrics of the server. It is protected by the lock of the server.
+type metrics struct {
+	matched   uint64
+	unmatched uint64
+
+	durationBuckets []uint64
+	durationSum     float64
+	durationCount   uint64
+}
+
+func (m *metrics) observe(matched bool, d time.Duration) {
+	if matched {
+		m.matched++
+	} else {
+		m.unmatched++
+	}
+
+	seconds := d.Seconds()
+
+	for i, le := range metricsBuckets {
+		if seconds <= le {
+			m.durationBuckets[i]++
+		}
+	}
+
+	m.durationSum += seconds
+	m.durationCount++
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		durationBuckets: make([]uint64, len(metricsBuckets)),
+	}
+}
+
+// WithMetrics exposes the metrics of the server in Prometheus text format on /metrics. The endpoint is useful when the
+// server is used as a long-lived stub service.
+func (s *Server) WithMetrics() *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.metrics == nil {
+		s.metrics = newMetrics()
+	}
+
+	return s
+}
+
+func (s *Server) isMetricsRequest(r *http.Request) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.metrics != nil && r.Method == http.MethodGet && r.URL.Path == metricsPath
+}
+
+func (s *Server) serveMetrics(w http.ResponseWriter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	writeMetricHeader(w, "httpmock_requests_total", "counter", "The total number of requests received by the server.")
+	_, _ = fmt.Fprintf(w, "httpmock_requests_total{result=\"matched\"} %d\n", s.metrics.matched)     //nolint: errcheck
+	_, _ = fmt.Fprintf(w, "httpmock_requests_total{result=\"unmatched\"} %d\n", s.metrics.unmatched) //nolint: errcheck
+
+	writeMetricHeader(w, "httpmock_request_duration_seconds", "histogram", "The time spent on serving the requests.")
+
+	for i, le := range metricsBuckets {
+		_, _ = fmt.Fprintf(w, "httpmock_request_duration_seconds_bucket{le=\"%s\"} %d\n", formatMetricFloat(le), s.metrics.durationBuckets[i]) //nolint: errcheck
+	}
+
+	_, _ = fmt.Fprintf(w, "httpmock_request_duration_seconds_bucket{le=\"+Inf\"} %d\n", s.metrics.durationCount)  //nolint: errcheck
+	_, _ = fmt.Fprintf(w, "httpmock_request_duration_seconds_sum %s\n", formatMetricFloat(s.metrics.durationSum)) //nolint: errcheck
+	_, _ = fmt.Fprintf(w, "httpmock_request_duration_seconds_count %d\n", s.metrics.durationCount)                //nolint: errcheck
+
+	writeMetricHeader(w, "httpmock_expectation_hits_total", "counter", "The number of times an expectation was matched.")
+
+	for _, e := range s.expectations {
+		_, _ = fmt.Fprintf(w, "httpmock_expectation_hits_total{id=\"%d\",method=\"%s\",uri=\"%s\"} %d\n", //nolint: errcheck
+			e.id, escapeMetricLabel(e.Method()), escapeMetricLabel(e.URIMatcher().Expected()), e.FulfilledTimes(),
+		)
+	}
+}
+
+func writeMetricHeader(w io.Writer, name, kind, help string) {
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind) //nolint: errcheck
+}
+
+func formatMetricFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var metricLabelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeMetricLabel(v string) string {
+	return metricLabelReplacer.Replace(v)
+}
@@ -0,0 +1,65 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/planner"
+)
+
+func TestServer_WithMetrics(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.MockServer(func(s *httpmock.Server) {
+		s.WithPlanner(planner.FirstMatch()).
+			WithMetrics()
+
+		s.ExpectGet("/").UnlimitedTimes()
+		s.ExpectGet(`/"quoted"`)
+		s.Expect("PURGE\u00a0ALL", "/")
+	})
+
+	defer s.Close()
+
+	doRequest(t, s.URL(), http.MethodGet, "/", nil, nil, 0)
+	doRequest(t, s.URL(), http.MethodGet, "/", nil, nil, 0)
+	doRequest(t, s.URL(), http.MethodGet, "/unknown", nil, nil, 0)
+
+	code, headers, body, _ := doRequest(t, s.URL(), http.MethodGet, "/metrics", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", headers["Content-Type"])
+
+	assert.Contains(t, string(body), `# TYPE httpmock_requests_total counter
+httpmock_requests_total{result="matched"} 2
+httpmock_requests_total{result="unmatched"} 1
+`)
+	assert.Contains(t, string(body), `httpmock_request_duration_seconds_bucket{le="+Inf"} 3
+`)
+	assert.Contains(t, string(body), `httpmock_request_duration_seconds_count 3
+`)
+	assert.Contains(t, string(body), `# TYPE httpmock_expectation_hits_total counter
+httpmock_expectation_hits_total{id="1",method="GET",uri="/"} 2
+httpmock_expectation_hits_total{id="2",method="GET",uri="/\"quoted\""} 0
+`)
+	// The label values are escaped in the text format, not quoted in Go.
+	assert.Contains(t, string(body), "httpmock_expectation_hits_total{id=\"3\",method=\"PURGE\u00a0ALL\",uri=\"/\"} 0\n")
+}
+
+func TestServer_WithoutMetrics(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.MockServer(func(s *httpmock.Server) {
+		s.ExpectGet("/metrics").Return("custom")
+	})
+
+	defer s.Close()
+
+	code, _, body, _ := doRequest(t, s.URL(), http.MethodGet, "/metrics", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "custom", string(body))
+}
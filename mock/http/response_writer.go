@@ -1,6 +1,8 @@
 package http
 
 import (
+	"bufio"
+	"net"
 	"net/http"
 	"testing"
 
@@ -14,7 +16,11 @@ type ResponseWriterMocker func(tb testing.TB) *ResponseWriter
 // NoMockResponseWriter is no mock ResponseWriter.
 var NoMockResponseWriter = MockResponseWriter()
 
-var _ http.ResponseWriter = (*ResponseWriter)(nil)
+var (
+	_ http.ResponseWriter = (*ResponseWriter)(nil)
+	_ http.Flusher        = (*ResponseWriter)(nil)
+	_ http.Hijacker       = (*ResponseWriter)(nil)
+)
 
 // ResponseWriter is a http.ResponseWriter.
 type ResponseWriter struct {
@@ -48,6 +54,21 @@ func (r *ResponseWriter) WriteHeader(statusCode int) {
 	r.Called(statusCode)
 }
 
+// Flush satisfies http.Flusher interface.
+func (r *ResponseWriter) Flush() {
+	r.Called()
+}
+
+// Hijack satisfies http.Hijacker interface.
+func (r *ResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	result := r.Called()
+
+	conn, _ := result.Get(0).(net.Conn)        //nolint: errcheck
+	rw, _ := result.Get(1).(*bufio.ReadWriter) //nolint: errcheck
+
+	return conn, rw, result.Error(2)
+}
+
 // mockResponseWriter mocks http.ResponseWriter interface.
 func mockResponseWriter(mocks ...func(w *ResponseWriter)) *ResponseWriter {
 	w := &ResponseWriter{}
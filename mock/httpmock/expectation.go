@@ -3,6 +3,8 @@
 package httpmock
 
 import (
+	context "context"
+
 	http "net/http"
 
 	httpmock "go.nhat.io/httpmock"
@@ -17,6 +19,22 @@ type Expectation struct {
 	mock.Mock
 }
 
+// AbortAfterBytes provides a mock function with given fields: n
+func (_m *Expectation) AbortAfterBytes(n int) httpmock.Expectation {
+	ret := _m.Called(n)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(int) httpmock.Expectation); ok {
+		r0 = rf(n)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
 // After provides a mock function with given fields: d
 func (_m *Expectation) After(d time.Duration) httpmock.Expectation {
 	ret := _m.Called(d)
@@ -33,6 +51,54 @@ func (_m *Expectation) After(d time.Duration) httpmock.Expectation {
 	return r0
 }
 
+// AfterFunc provides a mock function with given fields: fn
+func (_m *Expectation) AfterFunc(fn func(uint) time.Duration) httpmock.Expectation {
+	ret := _m.Called(fn)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(func(uint) time.Duration) httpmock.Expectation); ok {
+		r0 = rf(fn)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// Clone provides a mock function with given fields:
+func (_m *Expectation) Clone() httpmock.Expectation {
+	ret := _m.Called()
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func() httpmock.Expectation); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// ExpectWithin provides a mock function with given fields: d
+func (_m *Expectation) ExpectWithin(d time.Duration) httpmock.Expectation {
+	ret := _m.Called(d)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(time.Duration) httpmock.Expectation); ok {
+		r0 = rf(d)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
 // Handle provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Expectation) Handle(_a0 http.ResponseWriter, _a1 *http.Request, _a2 map[string]string) error {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -47,6 +113,45 @@ func (_m *Expectation) Handle(_a0 http.ResponseWriter, _a1 *http.Request, _a2 ma
 	return r0
 }
 
+// InScenario provides a mock function with given fields: name
+func (_m *Expectation) InScenario(name string) httpmock.Expectation {
+	ret := _m.Called(name)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(string) httpmock.Expectation); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// MarshalJSON provides a mock function with given fields:
+func (_m *Expectation) MarshalJSON() ([]byte, error) {
+	ret := _m.Called()
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func() []byte); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Once provides a mock function with given fields:
 func (_m *Expectation) Once() httpmock.Expectation {
 	ret := _m.Called()
@@ -79,6 +184,70 @@ func (_m *Expectation) Return(v interface{}) httpmock.Expectation {
 	return r0
 }
 
+// ReturnBase64 provides a mock function with given fields: b64
+func (_m *Expectation) ReturnBase64(b64 string) httpmock.Expectation {
+	ret := _m.Called(b64)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(string) httpmock.Expectation); ok {
+		r0 = rf(b64)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// ReturnByUserAgent provides a mock function with given fields: responses
+func (_m *Expectation) ReturnByUserAgent(responses map[string]interface{}) httpmock.Expectation {
+	ret := _m.Called(responses)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(map[string]interface{}) httpmock.Expectation); ok {
+		r0 = rf(responses)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// ReturnCacheable provides a mock function with given fields: maxAge
+func (_m *Expectation) ReturnCacheable(maxAge time.Duration) httpmock.Expectation {
+	ret := _m.Called(maxAge)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(time.Duration) httpmock.Expectation); ok {
+		r0 = rf(maxAge)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// ReturnChunks provides a mock function with given fields: chunks, gap
+func (_m *Expectation) ReturnChunks(chunks [][]byte, gap time.Duration) httpmock.Expectation {
+	ret := _m.Called(chunks, gap)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func([][]byte, time.Duration) httpmock.Expectation); ok {
+		r0 = rf(chunks, gap)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
 // ReturnCode provides a mock function with given fields: code
 func (_m *Expectation) ReturnCode(code int) httpmock.Expectation {
 	ret := _m.Called(code)
@@ -95,6 +264,22 @@ func (_m *Expectation) ReturnCode(code int) httpmock.Expectation {
 	return r0
 }
 
+// ReturnCorruptGzip provides a mock function with given fields: v
+func (_m *Expectation) ReturnCorruptGzip(v interface{}) httpmock.Expectation {
+	ret := _m.Called(v)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(interface{}) httpmock.Expectation); ok {
+		r0 = rf(v)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
 // ReturnFile provides a mock function with given fields: filePath
 func (_m *Expectation) ReturnFile(filePath string) httpmock.Expectation {
 	ret := _m.Called(filePath)
@@ -159,6 +344,86 @@ func (_m *Expectation) ReturnJSON(body interface{}) httpmock.Expectation {
 	return r0
 }
 
+// ReturnNoCache provides a mock function with given fields:
+func (_m *Expectation) ReturnNoCache() httpmock.Expectation {
+	ret := _m.Called()
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func() httpmock.Expectation); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// ReturnOnTimeout provides a mock function with given fields: code, body
+func (_m *Expectation) ReturnOnTimeout(code int, body interface{}) httpmock.Expectation {
+	ret := _m.Called(code, body)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(int, interface{}) httpmock.Expectation); ok {
+		r0 = rf(code, body)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// ReturnTemplate provides a mock function with given fields: tmpl
+func (_m *Expectation) ReturnTemplate(tmpl string) httpmock.Expectation {
+	ret := _m.Called(tmpl)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(string) httpmock.Expectation); ok {
+		r0 = rf(tmpl)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// ReturnWeighted provides a mock function with given fields: responses
+func (_m *Expectation) ReturnWeighted(responses map[interface{}]int) httpmock.Expectation {
+	ret := _m.Called(responses)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(map[interface{}]int) httpmock.Expectation); ok {
+		r0 = rf(responses)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// ReturnWithCharset provides a mock function with given fields: v, charset
+func (_m *Expectation) ReturnWithCharset(v interface{}, charset string) httpmock.Expectation {
+	ret := _m.Called(v, charset)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(interface{}, string) httpmock.Expectation); ok {
+		r0 = rf(v, charset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
 // Returnf provides a mock function with given fields: format, args
 func (_m *Expectation) Returnf(format string, args ...interface{}) httpmock.Expectation {
 	var _ca []interface{}
@@ -194,6 +459,52 @@ func (_m *Expectation) Run(handle func(*http.Request) ([]byte, error)) httpmock.
 	return r0
 }
 
+// RunHandler provides a mock function with given fields: h
+func (_m *Expectation) RunHandler(h http.Handler) httpmock.Expectation {
+	ret := _m.Called(h)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(http.Handler) httpmock.Expectation); ok {
+		r0 = rf(h)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// String provides a mock function with given fields:
+func (_m *Expectation) String() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// Tag provides a mock function with given fields: tag
+func (_m *Expectation) Tag(tag string) httpmock.Expectation {
+	ret := _m.Called(tag)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(string) httpmock.Expectation); ok {
+		r0 = rf(tag)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
 // Times provides a mock function with given fields: i
 func (_m *Expectation) Times(i uint) httpmock.Expectation {
 	ret := _m.Called(i)
@@ -242,6 +553,22 @@ func (_m *Expectation) UnlimitedTimes() httpmock.Expectation {
 	return r0
 }
 
+// WaitFor provides a mock function with given fields: condition, pollInterval
+func (_m *Expectation) WaitFor(condition func() bool, pollInterval time.Duration) httpmock.Expectation {
+	ret := _m.Called(condition, pollInterval)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(func() bool, time.Duration) httpmock.Expectation); ok {
+		r0 = rf(condition, pollInterval)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
 // WaitUntil provides a mock function with given fields: w
 func (_m *Expectation) WaitUntil(w <-chan time.Time) httpmock.Expectation {
 	ret := _m.Called(w)
@@ -258,6 +585,54 @@ func (_m *Expectation) WaitUntil(w <-chan time.Time) httpmock.Expectation {
 	return r0
 }
 
+// WaitUntilContext provides a mock function with given fields: ctx
+func (_m *Expectation) WaitUntilContext(ctx context.Context) httpmock.Expectation {
+	ret := _m.Called(ctx)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(context.Context) httpmock.Expectation); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// WhenScenarioStateIs provides a mock function with given fields: state
+func (_m *Expectation) WhenScenarioStateIs(state string) httpmock.Expectation {
+	ret := _m.Called(state)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(string) httpmock.Expectation); ok {
+		r0 = rf(state)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// WillSetStateTo provides a mock function with given fields: state
+func (_m *Expectation) WillSetStateTo(state string) httpmock.Expectation {
+	ret := _m.Called(state)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(string) httpmock.Expectation); ok {
+		r0 = rf(state)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
 // WithBody provides a mock function with given fields: body
 func (_m *Expectation) WithBody(body interface{}) httpmock.Expectation {
 	ret := _m.Called(body)
@@ -274,6 +649,22 @@ func (_m *Expectation) WithBody(body interface{}) httpmock.Expectation {
 	return r0
 }
 
+// WithBodyBase64 provides a mock function with given fields: b64
+func (_m *Expectation) WithBodyBase64(b64 string) httpmock.Expectation {
+	ret := _m.Called(b64)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(string) httpmock.Expectation); ok {
+		r0 = rf(b64)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
 // WithBodyJSON provides a mock function with given fields: v
 func (_m *Expectation) WithBodyJSON(v interface{}) httpmock.Expectation {
 	ret := _m.Called(v)
@@ -309,6 +700,22 @@ func (_m *Expectation) WithBodyf(format string, args ...interface{}) httpmock.Ex
 	return r0
 }
 
+// WithFormField provides a mock function with given fields: field, expected
+func (_m *Expectation) WithFormField(field string, expected interface{}) httpmock.Expectation {
+	ret := _m.Called(field, expected)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(string, interface{}) httpmock.Expectation); ok {
+		r0 = rf(field, expected)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
 // WithHeader provides a mock function with given fields: header, value
 func (_m *Expectation) WithHeader(header string, value interface{}) httpmock.Expectation {
 	ret := _m.Called(header, value)
@@ -325,6 +732,28 @@ func (_m *Expectation) WithHeader(header string, value interface{}) httpmock.Exp
 	return r0
 }
 
+// WithHeaderOrder provides a mock function with given fields: headers
+func (_m *Expectation) WithHeaderOrder(headers ...string) httpmock.Expectation {
+	_va := make([]interface{}, len(headers))
+	for _i := range headers {
+		_va[_i] = headers[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(...string) httpmock.Expectation); ok {
+		r0 = rf(headers...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
 // WithHeaders provides a mock function with given fields: headers
 func (_m *Expectation) WithHeaders(headers map[string]interface{}) httpmock.Expectation {
 	ret := _m.Called(headers)
@@ -341,6 +770,182 @@ func (_m *Expectation) WithHeaders(headers map[string]interface{}) httpmock.Expe
 	return r0
 }
 
+// WithHost provides a mock function with given fields: host
+func (_m *Expectation) WithHost(host interface{}) httpmock.Expectation {
+	ret := _m.Called(host)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(interface{}) httpmock.Expectation); ok {
+		r0 = rf(host)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// WithPathParam provides a mock function with given fields: name, value
+func (_m *Expectation) WithPathParam(name string, value interface{}) httpmock.Expectation {
+	ret := _m.Called(name, value)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(string, interface{}) httpmock.Expectation); ok {
+		r0 = rf(name, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// WithProto provides a mock function with given fields: proto
+func (_m *Expectation) WithProto(proto string) httpmock.Expectation {
+	ret := _m.Called(proto)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(string) httpmock.Expectation); ok {
+		r0 = rf(proto)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// WithRandSeed provides a mock function with given fields: seed
+func (_m *Expectation) WithRandSeed(seed int64) httpmock.Expectation {
+	ret := _m.Called(seed)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(int64) httpmock.Expectation); ok {
+		r0 = rf(seed)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// WithRemoteAddr provides a mock function with given fields: addr
+func (_m *Expectation) WithRemoteAddr(addr interface{}) httpmock.Expectation {
+	ret := _m.Called(addr)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(interface{}) httpmock.Expectation); ok {
+		r0 = rf(addr)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// WithScheme provides a mock function with given fields: scheme
+func (_m *Expectation) WithScheme(scheme string) httpmock.Expectation {
+	ret := _m.Called(scheme)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(string) httpmock.Expectation); ok {
+		r0 = rf(scheme)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// WithStrictHeaderCase provides a mock function with given fields:
+func (_m *Expectation) WithStrictHeaderCase() httpmock.Expectation {
+	ret := _m.Called()
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func() httpmock.Expectation); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// WithTimeout provides a mock function with given fields: d
+func (_m *Expectation) WithTimeout(d time.Duration) httpmock.Expectation {
+	ret := _m.Called(d)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(time.Duration) httpmock.Expectation); ok {
+		r0 = rf(d)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// WithoutDefaultHeaders provides a mock function with given fields:
+func (_m *Expectation) WithoutDefaultHeaders() httpmock.Expectation {
+	ret := _m.Called()
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func() httpmock.Expectation); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// WithoutQuery provides a mock function with given fields: key
+func (_m *Expectation) WithoutQuery(key string) httpmock.Expectation {
+	ret := _m.Called(key)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(string) httpmock.Expectation); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
+// Wrap provides a mock function with given fields: wrapper
+func (_m *Expectation) Wrap(wrapper func(httpmock.ExpectationHandler) httpmock.ExpectationHandler) httpmock.Expectation {
+	ret := _m.Called(wrapper)
+
+	var r0 httpmock.Expectation
+	if rf, ok := ret.Get(0).(func(func(httpmock.ExpectationHandler) httpmock.ExpectationHandler) httpmock.Expectation); ok {
+		r0 = rf(wrapper)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httpmock.Expectation)
+		}
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewExpectation interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,162 @@
+package httpmock
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const openAPIVersion = "3.0.3"
+
+// openAPIDocument is a minimal OpenAPI document.
+type openAPIDocument struct {
+	OpenAPI string                                 `json:"openapi"`
+	Info    openAPIInfo                            `json:"info"`
+	Paths   map[string]map[string]openAPIOperation `json:"paths"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIOperation struct {
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIBody               `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required"`
+	Example  string `json:"example,omitempty"`
+}
+
+type openAPIBody struct {
+	Content map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Example any `json:"example,omitempty"`
+}
+
+// ExportOpenAPI writes a best-effort OpenAPI document, in JSON, of the registered expectations, to document what a
+// test suite exercises. The paths are the expected uris without the query, and the examples are the expected request
+// bodies and the static response bodies, set by Return or ReturnJSON.
+func (s *Server) ExportOpenAPI(w io.Writer) error {
+	s.mu.Lock()
+
+	doc := openAPIDocument{
+		OpenAPI: openAPIVersion,
+		Info:    openAPIInfo{Title: "httpmock", Version: "1.0.0"},
+		Paths:   make(map[string]map[string]openAPIOperation),
+	}
+
+	for _, e := range s.expectations {
+		addOpenAPIOperation(doc.Paths, e, s.defaultResponseHeader)
+	}
+
+	s.mu.Unlock()
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+
+	return enc.Encode(doc)
+}
+
+func addOpenAPIOperation(paths map[string]map[string]openAPIOperation, e *requestExpectation, defaultHeaders Header) {
+	path := e.URIMatcher().Expected()
+
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+
+	if paths[path] == nil {
+		paths[path] = make(map[string]openAPIOperation)
+	}
+
+	method := strings.ToLower(e.Method())
+
+	op, ok := paths[path][method]
+	if !ok {
+		op = openAPIOperation{Responses: make(map[string]openAPIResponse)}
+
+		for name, m := range e.HeaderMatcher() {
+			// These headers are described by the other fields of the document.
+			switch http.CanonicalHeaderKey(name) {
+			case "Accept", "Authorization", "Content-Type":
+				continue
+			}
+
+			op.Parameters = append(op.Parameters, openAPIParameter{
+				Name:     name,
+				In:       "header",
+				Required: true,
+				Example:  m.Expected(),
+			})
+		}
+
+		sort.Slice(op.Parameters, func(i, j int) bool {
+			return op.Parameters[i].Name < op.Parameters[j].Name
+		})
+
+		if b := e.BodyMatcher(); b != nil {
+			var contentType string
+
+			if m, ok := e.HeaderMatcher()["Content-Type"]; ok {
+				contentType = m.Expected()
+			}
+
+			op.RequestBody = &openAPIBody{Content: openAPIContent(contentType, []byte(b.Expected()))}
+		}
+	}
+
+	e.lock()
+	code := e.responseCode
+	contentType := e.responseHeader["Content-Type"]
+	example := e.example
+	e.unlock()
+
+	if _, ok := op.Responses[strconv.Itoa(code)]; !ok {
+		if contentType == "" {
+			contentType = defaultHeaders["Content-Type"]
+		}
+
+		resp := openAPIResponse{Description: http.StatusText(code)}
+
+		if len(example) > 0 {
+			resp.Content = openAPIContent(contentType, example)
+		}
+
+		op.Responses[strconv.Itoa(code)] = resp
+	}
+
+	paths[path][method] = op
+}
+
+func openAPIContent(contentType string, example []byte) map[string]openAPIMediaType {
+	var v any = string(example)
+
+	if json.Valid(example) {
+		v = json.RawMessage(example)
+
+		if contentType == "" {
+			contentType = "application/json"
+		}
+	}
+
+	if contentType == "" {
+		contentType = "text/plain"
+	}
+
+	return map[string]openAPIMediaType{contentType: {Example: v}}
+}
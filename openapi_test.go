@@ -0,0 +1,86 @@
+package httpmock_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestServer_ExportOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+
+	defer s.Close()
+
+	s.ExpectGet("/users?page=1").
+		WithHeader("Authorization", "Bearer token").
+		WithHeader("X-Tenant", "acme").
+		Return(`[{"id":42}]`)
+
+	s.ExpectGet("/users").
+		ReturnCode(httpmock.StatusUnauthorized).
+		ReturnHeader("Content-Type", "text/plain").
+		Return("unauthorized")
+
+	s.ExpectPost("/users").
+		WithHeader("Content-Type", "application/json").
+		WithBody(`{"name":"john"}`).
+		ReturnCode(httpmock.StatusCreated).
+		ReturnJSON(map[string]any{"id": 42, "name": "john"})
+
+	s.ExpectDelete("/users/42").
+		ReturnCode(httpmock.StatusNoContent)
+
+	var buf bytes.Buffer
+
+	require.NoError(t, s.ExportOpenAPI(&buf))
+
+	expected := `{
+		"openapi": "3.0.3",
+		"info": {"title": "httpmock", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {
+					"parameters": [
+						{"name": "X-Tenant", "in": "header", "required": true, "example": "acme"}
+					],
+					"responses": {
+						"200": {
+							"description": "OK",
+							"content": {"application/json": {"example": [{"id": 42}]}}
+						},
+						"401": {
+							"description": "Unauthorized",
+							"content": {"text/plain": {"example": "unauthorized"}}
+						}
+					}
+				},
+				"post": {
+					"requestBody": {
+						"content": {"application/json": {"example": {"name": "john"}}}
+					},
+					"responses": {
+						"201": {
+							"description": "Created",
+							"content": {"application/json": {"example": {"id": 42, "name": "john"}}}
+						}
+					}
+				}
+			},
+			"/users/42": {
+				"delete": {
+					"responses": {
+						"204": {"description": "No Content"}
+					}
+				}
+			}
+		}
+	}`
+
+	assert.JSONEq(t, expected, buf.String())
+}
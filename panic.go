@@ -0,0 +1,46 @@
+package httpmock
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+// ErrHandlerPanicked indicates that the handler of an expectation panicked. The server responds 500 with the stack trace
+// and fails the test, instead of crashing the serving goroutine and leaving the client with an EOF.
+var ErrHandlerPanicked = errors.New("handler panicked")
+
+// recoverHandler converts a panic to an error with the stack trace. http.ErrAbortHandler is not recovered, so the
+// connection is still reset.
+func recoverHandler(p any) error {
+	if p == http.ErrAbortHandler { // nolint: errorlint,goerr113
+		panic(p)
+	}
+
+	return fmt.Errorf("%w: %v\n%s", ErrHandlerPanicked, p, debug.Stack())
+}
+
+// handleSafely handles the request, if the handler panics, it responds 500 and returns the panic as an error.
+func handleSafely(h ExpectationHandler, w http.ResponseWriter, r *http.Request, defaultHeaders map[string]string) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = recoverHandler(p)
+
+			_ = FailResponse(w, "%s", err.Error()) //nolint: errcheck
+		}
+	}()
+
+	return h.Handle(w, r, defaultHeaders)
+}
+
+// runSafely calls the handler, if it panics, the panic is returned as an error.
+func runSafely(handle func(r *http.Request) ([]byte, error), r *http.Request) (body []byte, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = recoverHandler(p)
+		}
+	}()
+
+	return handle(r)
+}
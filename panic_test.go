@@ -0,0 +1,99 @@
+package httpmock_test
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+)
+
+// errorRecorderT records the errors without stopping the handler.
+type errorRecorderT struct {
+	mu     sync.Mutex
+	errors []string
+}
+
+func (t *errorRecorderT) Errorf(format string, args ...any) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *errorRecorderT) FailNow() {}
+
+func (t *errorRecorderT) Cleanup(func()) {}
+
+func (t *errorRecorderT) Errors() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]string(nil), t.errors...)
+}
+
+func TestServer_HandlerPanic(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		mock     func(e httpmock.Expectation)
+	}{
+		{
+			scenario: "run",
+			mock: func(e httpmock.Expectation) {
+				e.Run(func(*http.Request) ([]byte, error) {
+					panic("boom")
+				})
+			},
+		},
+		{
+			scenario: "run with timeout",
+			mock: func(e httpmock.Expectation) {
+				e.WithTimeout(time.Second).
+					Run(func(*http.Request) ([]byte, error) {
+						panic("boom")
+					})
+			},
+		},
+		{
+			scenario: "run handler",
+			mock: func(e httpmock.Expectation) {
+				e.RunHandler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+					panic("boom")
+				}))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			testingT := &errorRecorderT{}
+
+			s := httpmock.NewServer().WithTest(testingT)
+			defer s.Close()
+
+			tc.mock(s.ExpectGet("/"))
+
+			code, _, body, _ := doRequest(t, s.URL(), http.MethodGet, "/", nil, nil, 0)
+
+			assert.Equal(t, http.StatusInternalServerError, code)
+			assert.Contains(t, string(body), "handler panicked: boom\ngoroutine ")
+
+			errs := testingT.Errors()
+
+			if assert.Len(t, errs, 1) {
+				assert.Contains(t, errs[0], "handler panicked: boom")
+				assert.Contains(t, errs[0], "panic_test.go")
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package httpmock
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.nhat.io/httpmock/matcher"
+)
+
+type pathParamsKey struct{}
+
+// PathTemplate matches a request uri by a template with path parameters, for example, /users/{id}. The parameters could
+// be constrained with WithPathParam, and their values are accessible in the handlers with PathParams.
+//
+//	Server.Expect(httpmock.MethodGet, httpmock.PathTemplate("/users/{id}"))
+var PathTemplate = matcher.PathTemplate
+
+// WithPathParam constrains a parameter of the request uri template, for example, the id of /users/{id}. The value
+// could be a string or a Matcher. It panics if the request uri is not a template, see PathTemplate, or does not have the
+// parameter.
+//
+//	Server.Expect(httpmock.MethodGet, httpmock.PathTemplate("/users/{id}")).
+//		WithPathParam("id", httpmock.RegexPattern(`^\d+$`))
+func (e *requestExpectation) WithPathParam(name string, value any) Expectation {
+	e.lock()
+	defer e.unlock()
+
+	t, ok := e.requestURIMatcher.(*matcher.PathTemplateMatcher)
+	if !ok {
+		panic(fmt.Errorf("could not constrain path param %q: request uri is not a template", name)) // nolint: goerr113
+	}
+
+	if !t.HasParam(name) {
+		panic(fmt.Errorf("could not constrain path param %q: it is not in %q", name, t.Expected())) // nolint: goerr113
+	}
+
+	e.requestURIMatcher = t.WithParam(name, value)
+
+	return e
+}
+
+// withPathParams attaches the path parameters of the request to it, if the request uri matcher is a template, see
+// PathParams.
+func withPathParams(r *http.Request, uri matcher.Matcher) *http.Request {
+	t, ok := uri.(*matcher.PathTemplateMatcher)
+	if !ok {
+		return r
+	}
+
+	params, ok := t.Params(r.RequestURI)
+	if !ok {
+		params, _ = t.Params(r.URL.RequestURI())
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), pathParamsKey{}, params))
+}
+
+// PathParams returns the path parameters of the request, extracted by the request uri template of the expectation that
+// handles it, nil if the request uri of the expectation is not a template. The values are unescaped.
+//
+//	Server.Expect(httpmock.MethodGet, httpmock.PathTemplate("/users/{id}/orders/{orderID}")).
+//		Run(func(r *http.Request) ([]byte, error) {
+//			params := httpmock.PathParams(r)
+//
+//			return []byte(fmt.Sprintf(`{"user":%q,"order":%q}`, params["id"], params["orderID"])), nil
+//		})
+func PathParams(r *http.Request) map[string]string {
+	params, _ := r.Context().Value(pathParamsKey{}).(map[string]string) // nolint: errcheck
+
+	return params
+}
+
+// PathParam returns the path parameter of the request by name, empty if it is not found, see PathParams.
+func PathParam(r *http.Request, name string) string {
+	return PathParams(r)[name]
+}
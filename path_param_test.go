@@ -0,0 +1,127 @@
+package httpmock_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/planner"
+)
+
+func TestServer_PathTemplate(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.NewServer().WithPlanner(planner.FirstMatch())
+	defer srv.Close()
+
+	srv.ExpectGet(httpmock.PathTemplate("/users/{id}/orders/{orderID}")).
+		WithPathParam("id", httpmock.RegexPattern(`^\d+$`)).
+		Run(func(r *http.Request) ([]byte, error) {
+			return []byte(fmt.Sprintf("%s:%s", httpmock.PathParam(r, "id"), httpmock.PathParam(r, "orderID"))), nil
+		}).
+		UnlimitedTimes()
+
+	code, _, body, _ := doRequest(t, srv.URL(), http.MethodGet, "/users/42/orders/a%20b", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "42:a b", string(body))
+
+	code, _, _, _ = doRequest(t, srv.URL(), http.MethodGet, "/users/john/orders/1", nil, nil, 0)
+
+	assert.Equal(t, http.StatusInternalServerError, code)
+
+	code, _, _, _ = doRequest(t, srv.URL(), http.MethodGet, "/users/42/orders/1/items", nil, nil, 0)
+
+	assert.Equal(t, http.StatusInternalServerError, code)
+}
+
+func TestServer_PathTemplate_ReturnTemplate(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet(httpmock.PathTemplate("/users/{id}")).
+			ReturnTemplate(`{"id":"{{.PathParams.id}}"}`)
+	})(t)
+
+	code, _, body, _ := doRequest(t, srv.URL(), http.MethodGet, "/users/42", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `{"id":"42"}`, string(body))
+}
+
+func TestServer_PathTemplate_NotTemplate(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet("/users").
+			Run(func(r *http.Request) ([]byte, error) {
+				assert.Nil(t, httpmock.PathParams(r))
+
+				return nil, nil
+			})
+	})(t)
+
+	code, _, _, _ := doRequest(t, srv.URL(), http.MethodGet, "/users", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestServer_PathTemplate_PlainString(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.NewServer()
+	defer srv.Close()
+
+	srv.ExpectGet("/users/{id}")
+
+	// A plain string is not a template, it is matched exactly.
+	code, _, _, _ := doRequest(t, srv.URL(), http.MethodGet, "/users/42", nil, nil, 0)
+
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Error(t, srv.ExpectationsWereMet())
+}
+
+func TestExpectation_WithPathParam_Panic(t *testing.T) {
+	t.Parallel()
+
+	assert.PanicsWithError(t, `could not constrain path param "id": request uri is not a template`, func() {
+		httpmock.NewExpectation(httpmock.MethodGet, "/users").
+			WithPathParam("id", "42")
+	})
+
+	assert.PanicsWithError(t, `could not constrain path param "id": request uri is not a template`, func() {
+		httpmock.NewExpectation(httpmock.MethodGet, "/users/{id}").
+			WithPathParam("id", "42")
+	})
+
+	assert.PanicsWithError(t, `could not constrain path param "name": it is not in "/users/{id}"`, func() {
+		httpmock.NewExpectation(httpmock.MethodGet, httpmock.PathTemplate("/users/{id}")).
+			WithPathParam("name", "john")
+	})
+}
+
+func TestExpectation_WithPathParam_Clone(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.NewServer().WithPlanner(planner.FirstMatch())
+	defer srv.Close()
+
+	srv.ExpectGet(httpmock.PathTemplate("/users/{id}")).
+		WithPathParam("id", "1").
+		ReturnCode(httpmock.StatusOK).
+		Clone().
+		WithPathParam("id", "2").
+		ReturnCode(httpmock.StatusNotFound)
+
+	code, _, _, _ := doRequest(t, srv.URL(), http.MethodGet, "/users/2", nil, nil, 0)
+
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, _, _, _ = doRequest(t, srv.URL(), http.MethodGet, "/users/1", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.NoError(t, srv.ExpectationsWereMet())
+}
@@ -0,0 +1,68 @@
+package planner_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/planner"
+)
+
+func BenchmarkMatchRequest(b *testing.B) {
+	e := httpmock.NewExpectation(http.MethodPost, "/users")
+
+	e.WithHeader("Content-Type", "application/json").
+		WithBody(`{"name":"john"}`)
+
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"john"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := planner.MatchRequest(e, req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFirstMatch_Plan(b *testing.B) {
+	benchmarkPlan(b, planner.FirstMatch())
+}
+
+func BenchmarkRoundRobin_Plan(b *testing.B) {
+	benchmarkPlan(b, planner.RoundRobin())
+}
+
+func BenchmarkFIFO_Plan(b *testing.B) {
+	benchmarkPlan(b, planner.FIFO())
+}
+
+func benchmarkPlan(b *testing.B, p planner.Planner) {
+	b.Helper()
+
+	for i := 0; i < 10; i++ {
+		e := httpmock.NewExpectation(http.MethodGet, fmt.Sprintf("/users/%d", i))
+
+		e.WithHeader("Authorization", "Bearer token").
+			UnlimitedTimes()
+
+		p.Expect(e)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/users/9", nil)
+	req.Header.Set("Authorization", "Bearer token")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := p.Plan(req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package planner
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var (
+	_ Planner     = (*chain)(nil)
+	_ Validator   = (*chain)(nil)
+	_ Snapshotter = (*chain)(nil)
+	_ Replacer    = (*chain)(nil)
+	_ Planner     = (*acceptor)(nil)
+	_ Validator   = (*acceptor)(nil)
+	_ Snapshotter = (*acceptor)(nil)
+	_ Replacer    = (*acceptor)(nil)
+)
+
+// Acceptor is an optional interface that a planner in a chain can implement to decide whether it takes an expectation.
+type Acceptor interface {
+	// Accept checks whether the planner takes the expectation.
+	Accept(e Expectation) bool
+}
+
+type chain struct {
+	planners []Planner
+}
+
+func (c *chain) IsEmpty() bool {
+	for _, p := range c.planners {
+		if !p.IsEmpty() {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *chain) Expect(e Expectation) {
+	if len(c.planners) == 0 {
+		panic(errors.New("could not expect: no planner in the chain")) // nolint: goerr113
+	}
+
+	for _, p := range c.planners {
+		if !accepts(p, e) {
+			continue
+		}
+
+		p.Expect(e)
+
+		return
+	}
+
+	// No planner accepts the expectation, it is given to the last one, so it is not lost.
+	c.planners[len(c.planners)-1].Expect(e)
+}
+
+func (c *chain) Plan(req *http.Request) (Expectation, error) {
+	var firstErr error
+
+	for _, p := range c.planners {
+		if p.IsEmpty() {
+			continue
+		}
+
+		expected, err := p.Plan(req)
+		if err == nil {
+			return expected, nil
+		}
+
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if firstErr == nil {
+		return nil, ErrNoExpectation
+	}
+
+	return nil, firstErr
+}
+
+func (c *chain) Remain() []Expectation {
+	var result []Expectation
+
+	for _, p := range c.planners {
+		result = append(result, p.Remain()...)
+	}
+
+	return result
+}
+
+func (c *chain) Validate() error {
+	var messages []string
+
+	for _, p := range c.planners {
+		if err := Validate(p); err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+
+	// nolint:goerr113
+	return errors.New(strings.Join(messages, ""))
+}
+
+func (c *chain) Reset() {
+	for _, p := range c.planners {
+		p.Reset()
+	}
+}
+
+// Replace puts the new expectation in the first planner that has an expectation to remove and accepts the new one, or
+// adds it to the chain if there is no such planner.
+func (c *chain) Replace(remove func(e Expectation) bool, e Expectation) bool {
+	removed, replaced := false, e == nil
+
+	for _, p := range c.planners {
+		if !replaced && accepts(p, e) && hasExpectation(p, remove) {
+			removed = Replace(p, remove, e) || removed
+			replaced = true
+
+			continue
+		}
+
+		removed = Replace(p, remove, nil) || removed
+	}
+
+	if !replaced {
+		c.Expect(e)
+	}
+
+	return removed
+}
+
+func (c *chain) SnapshotQueue() (any, error) {
+	result := make([]*Checkpoint, len(c.planners))
+
+	for i, p := range c.planners {
+		cp, err := Snapshot(p)
+		if err != nil {
+			return nil, err
+		}
+
+		result[i] = cp
+	}
+
+	return result, nil
+}
+
+func (c *chain) RestoreQueue(queue any) {
+	for i, cp := range queue.([]*Checkpoint) { // nolint: forcetypeassert
+		_ = Restore(c.planners[i], cp) // nolint: errcheck // The checkpoint is taken from the same planner.
+	}
+}
+
+// Chain creates a new Planner that tries the planners in order until one of them finds an expectation for the request.
+// A new expectation is given to the first planner that accepts it, see Acceptor and Accept, or to the last planner if
+// none does. It panics if there is no planner when an expectation is added.
+//
+//	planner.Chain(
+//		planner.Accept(planner.FirstMatch(), func(e planner.Expectation) bool {
+//			return e.Method() == http.MethodOptions
+//		}),
+//		planner.Sequence(),
+//	)
+func Chain(planners ...Planner) Planner {
+	return &chain{planners: planners}
+}
+
+type acceptor struct {
+	Planner
+
+	accept func(e Expectation) bool
+}
+
+func (a *acceptor) Accept(e Expectation) bool {
+	return a.accept(e)
+}
+
+func (a *acceptor) Validate() error {
+	return Validate(a.Planner)
+}
+
+func (a *acceptor) Replace(remove func(e Expectation) bool, e Expectation) bool {
+	return Replace(a.Planner, remove, e)
+}
+
+func (a *acceptor) SnapshotQueue() (any, error) {
+	return Snapshot(a.Planner)
+}
+
+func (a *acceptor) RestoreQueue(queue any) {
+	_ = Restore(a.Planner, queue.(*Checkpoint)) // nolint: errcheck,forcetypeassert // The checkpoint is taken from the same planner.
+}
+
+// accepts checks whether the planner takes the expectation in a chain.
+func accepts(p Planner, e Expectation) bool {
+	a, ok := p.(Acceptor)
+
+	return !ok || a.Accept(e)
+}
+
+// Accept wraps a planner so that it only takes the expectations that satisfy the condition when it is used in a Chain.
+func Accept(p Planner, accept func(e Expectation) bool) Planner {
+	return &acceptor{
+		Planner: p,
+		accept:  accept,
+	}
+}
@@ -0,0 +1,147 @@
+package planner_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"go.nhat.io/httpmock/mock/http"
+	plannermock "go.nhat.io/httpmock/mock/planner"
+	"go.nhat.io/httpmock/planner"
+)
+
+func TestChain(t *testing.T) {
+	t.Parallel()
+
+	users := mockGetExpectation("/users", 1)(t)
+	items := mockGetExpectation("/items", 1)(t)
+	unlimited := mockGetExpectation("/unlimited", 0)(t)
+
+	p := planner.Chain(
+		planner.Accept(planner.FirstMatch(), func(e planner.Expectation) bool {
+			return e.RemainTimes() == 0
+		}),
+		planner.Sequence(),
+	)
+
+	assert.True(t, p.IsEmpty())
+
+	p.Expect(users)
+	p.Expect(items)
+	p.Expect(unlimited)
+
+	assert.False(t, p.IsEmpty())
+	assert.Equal(t, []planner.Expectation{unlimited, users, items}, p.Remain())
+
+	// The 1st planner takes the request.
+	result, err := p.Plan(http.BuildRequest().WithURI("/unlimited").Build())
+
+	assert.NoError(t, err)
+	assert.Equal(t, unlimited, result)
+
+	// The 2nd planner takes the request.
+	result, err = p.Plan(http.BuildRequest().WithURI("/users").Build())
+
+	assert.NoError(t, err)
+	assert.Equal(t, users, result)
+
+	// No planner takes the request, the error of the 1st planner is returned.
+	result, err = p.Plan(http.BuildRequest().WithURI("/unknown").Build())
+
+	expectedError := `Expected: GET /unlimited
+Actual: GET /unknown
+Error: request uri "/unlimited" expected, "/unknown" received
+`
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, expectedError)
+
+	p.Reset()
+
+	assert.True(t, p.IsEmpty())
+	assert.Empty(t, p.Remain())
+}
+
+func TestChain_SkipEmptyPlanner(t *testing.T) {
+	t.Parallel()
+
+	expected := plannermock.NoMockExpectation(t)
+
+	first := plannermock.Mock(func(p *plannermock.Planner) {
+		p.On("IsEmpty").Return(true)
+	})(t)
+
+	second := plannermock.Mock(func(p *plannermock.Planner) {
+		p.On("IsEmpty").Return(false)
+		p.On("Plan", mock.Anything).Return(expected, nil)
+	})(t)
+
+	result, err := planner.Chain(first, second).Plan(http.BuildRequest().Build())
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestChain_NoPlanner(t *testing.T) {
+	t.Parallel()
+
+	p := planner.Chain()
+
+	assert.PanicsWithError(t, "could not expect: no planner in the chain", func() {
+		p.Expect(plannermock.NoMockExpectation(t))
+	})
+
+	result, err := p.Plan(http.BuildRequest().Build())
+
+	assert.True(t, p.IsEmpty())
+	assert.Nil(t, result)
+	assert.True(t, errors.Is(err, planner.ErrNoExpectation))
+}
+
+func TestChain_NotAccepted(t *testing.T) {
+	t.Parallel()
+
+	users := mockGetExpectation("/users", 1)(t)
+
+	never := func(planner.Expectation) bool { return false }
+
+	p := planner.Chain(
+		planner.Accept(planner.FirstMatch(), never),
+		planner.Accept(planner.Sequence(), never),
+	)
+
+	// The expectation is given to the last planner.
+	p.Expect(users)
+
+	assert.False(t, p.IsEmpty())
+	assert.Equal(t, []planner.Expectation{users}, p.Remain())
+
+	result, err := p.Plan(http.BuildRequest().WithURI("/users").Build())
+
+	assert.NoError(t, err)
+	assert.Equal(t, users, result)
+}
+
+func TestChain_Validate(t *testing.T) {
+	t.Parallel()
+
+	options := planner.Accept(planner.Sequence(), func(e planner.Expectation) bool {
+		return e.Method() == http.MethodPost
+	})
+
+	sequence := planner.Sequence()
+
+	p := planner.Chain(options, planner.FirstMatch(), sequence)
+
+	p.Expect(mockGetExpectation("/a", 0)(t))
+	p.Expect(mockGetExpectation("/b", 1)(t))
+
+	assert.NoError(t, planner.Validate(p))
+
+	sequence.Expect(mockGetExpectation("/c", 0)(t))
+	sequence.Expect(mockGetExpectation("/d", 1)(t))
+
+	assert.EqualError(t, planner.Validate(p), "there are expectations that can never be reached because of the unlimited expectation GET /c\n- GET /d\n")
+}
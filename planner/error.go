@@ -1,6 +1,7 @@
 package planner
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,6 +11,9 @@ import (
 	"go.nhat.io/httpmock/value"
 )
 
+// ErrNoExpectation indicates that there is no expectation to match the request.
+var ErrNoExpectation = errors.New("no expectation")
+
 // Error represents an error that occurs while matching a request.
 type Error struct {
 	expected Expectation
@@ -26,10 +30,14 @@ func (e Error) formatExpected(w io.Writer) {
 		e.expected.HeaderMatcher(),
 		e.expected.BodyMatcher(),
 	)
+
+	if l := location(e.expected); l != "" {
+		_, _ = fmt.Fprintf(w, "    declared at %s\n", l) //nolint: errcheck
+	}
 }
 
 func (e Error) formatActual(w io.Writer) {
-	body, err := value.GetBody(e.actual)
+	body, err := value.PeekBody(e.actual)
 	if err != nil {
 		body = []byte("could not read request body: " + err.Error())
 	}
@@ -46,12 +54,22 @@ func (e Error) Error() string {
 	_, _ = fmt.Fprint(&sb, "Actual: ")
 	e.formatActual(&sb)
 	_, _ = fmt.Fprint(&sb, "Error: ")
-	_, _ = fmt.Fprintf(&sb, e.messageFormat, e.messageArgs...)
+	_, _ = fmt.Fprint(&sb, e.message())
 	_, _ = fmt.Fprint(&sb, "\n")
 
 	return sb.String()
 }
 
+// Expected returns the expectation that the request is matched against.
+func (e Error) Expected() Expectation {
+	return e.expected
+}
+
+// message returns the reason of the error, without the expected and the actual requests.
+func (e Error) message() string {
+	return fmt.Sprintf(e.messageFormat, e.messageArgs...)
+}
+
 // NewError creates a new Error.
 func NewError(expected Expectation, request *http.Request, messageFormat string, messageArgs ...any) *Error {
 	return &Error{
@@ -14,3 +14,38 @@ type Expectation interface {
 	Fulfilled()
 	FulfilledTimes() uint
 }
+
+// Locator is an optional interface that an expectation can implement to report where it is declared, for example, the
+// file and the line of the test. The location is printed in the errors of the expectation.
+type Locator interface {
+	// Location returns the location of the expectation, empty if it is unknown.
+	Location() string
+}
+
+// Unwrapper is an optional interface that an expectation can implement when it wraps another one, for example, the view
+// of an expectation in a session of PerSession.
+type Unwrapper interface {
+	// Unwrap returns the wrapped expectation.
+	Unwrap() Expectation
+}
+
+// Unwrap returns the innermost expectation, unwrapping the expectations that implement Unwrapper.
+func Unwrap(expected Expectation) Expectation {
+	for {
+		u, ok := expected.(Unwrapper)
+		if !ok {
+			return expected
+		}
+
+		expected = u.Unwrap()
+	}
+}
+
+// location returns the location of the expectation, empty if it is unknown.
+func location(expected Expectation) string {
+	if l, ok := expected.(Locator); ok {
+		return l.Location()
+	}
+
+	return ""
+}
@@ -0,0 +1,106 @@
+package planner
+
+import (
+	"net/http"
+	"sync"
+)
+
+var (
+	_ Planner     = (*fifo)(nil)
+	_ Snapshotter = (*fifo)(nil)
+	_ Replacer    = (*fifo)(nil)
+)
+
+type fifo struct {
+	expectations []Expectation
+
+	mu sync.Mutex
+}
+
+func (f *fifo) IsEmpty() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.expectations) == 0
+}
+
+func (f *fifo) Expect(e Expectation) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.expectations = append(f.expectations, e)
+}
+
+func (f *fifo) Plan(req *http.Request) (Expectation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.expectations) == 0 {
+		return nil, ErrNoExpectation
+	}
+
+	for i, expected := range f.expectations {
+		if !isRouteMatched(expected, req) {
+			continue
+		}
+
+		// The request must match the head of its queue.
+		if err := MatchRequest(expected, req); err != nil {
+			return nil, err
+		}
+
+		if !trackRepeatable(expected) {
+			f.expectations = append(f.expectations[:i:i], f.expectations[i+1:]...)
+		}
+
+		return expected, nil
+	}
+
+	return nil, MatchRequest(f.expectations[0], req)
+}
+
+func (f *fifo) Remain() []Expectation {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.expectations
+}
+
+func (f *fifo) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.expectations = nil
+}
+
+func (f *fifo) Replace(remove func(e Expectation) bool, e Expectation) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var removed bool
+
+	f.expectations, removed = replaceExpectations(f.expectations, remove, e)
+
+	return removed
+}
+
+func (f *fifo) SnapshotQueue() (any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return copyExpectations(f.expectations), nil
+}
+
+func (f *fifo) RestoreQueue(queue any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.expectations = copyExpectations(queue.([]Expectation)) // nolint: forcetypeassert
+}
+
+// FIFO creates a new Planner that keeps a queue of expectations for every method and uri. A request must match the
+// head of its queue, and the head moves on to the next expectation once it is exhausted. Unlike Sequence, the requests
+// to the other methods or uris can interleave in any order.
+func FIFO() Planner {
+	return &fifo{}
+}
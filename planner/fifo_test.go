@@ -0,0 +1,144 @@
+package planner_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock/matcher"
+	"go.nhat.io/httpmock/mock/http"
+	plannermock "go.nhat.io/httpmock/mock/planner"
+	"go.nhat.io/httpmock/planner"
+)
+
+func TestFIFO(t *testing.T) {
+	t.Parallel()
+
+	p := planner.FIFO()
+
+	users1 := mockGetExpectation("/users", 2)(t)
+	items := mockGetExpectation("/items", 1)(t)
+	users2 := mockGetExpectation("/users", 1)(t)
+
+	p.Expect(users1)
+	p.Expect(items)
+	p.Expect(users2)
+
+	usersReq := http.BuildRequest().WithURI("/users").Build()
+
+	// The head of the queue is repeatable, it stays.
+	result, err := p.Plan(usersReq)
+
+	assert.NoError(t, err)
+	assert.Equal(t, users1, result)
+	assert.Equal(t, []planner.Expectation{users1, items, users2}, p.Remain())
+
+	// Other requests interleave.
+	result, err = p.Plan(http.BuildRequest().WithURI("/items").Build())
+
+	assert.NoError(t, err)
+	assert.Equal(t, items, result)
+	assert.Equal(t, []planner.Expectation{users1, users2}, p.Remain())
+
+	// The head of the queue is exhausted, it is removed.
+	users1.On("RemainTimes").Unset()
+	users1.On("RemainTimes").Return(uint(1))
+
+	result, err = p.Plan(usersReq)
+
+	assert.NoError(t, err)
+	assert.Equal(t, users1, result)
+	assert.Equal(t, []planner.Expectation{users2}, p.Remain())
+
+	// Move on to the next expectation.
+	result, err = p.Plan(usersReq)
+
+	assert.NoError(t, err)
+	assert.Equal(t, users2, result)
+	assert.Empty(t, p.Remain())
+	assert.True(t, p.IsEmpty())
+
+	// The queue is empty.
+	result, err = p.Plan(usersReq)
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, planner.ErrNoExpectation)
+}
+
+func TestFIFO_Mismatched(t *testing.T) {
+	t.Parallel()
+
+	p := planner.FIFO()
+
+	p.Expect(mockGetExpectation("/users", 1)(t))
+	p.Expect(plannermock.MockExpectation(func(e *plannermock.Expectation) {
+		e.On("Method").Maybe().Return(http.MethodGet)
+		e.On("URIMatcher").Maybe().Return(matcher.Match("/items"))
+		e.On("HeaderMatcher").Maybe().Return(matcher.HeaderMatcher{
+			"Authorization": matcher.Match("Bearer token"),
+		})
+		e.On("BodyMatcher").Maybe().Return(nil)
+	})(t))
+	p.Expect(mockGetExpectation("/items", 1)(t))
+
+	// The request does not match the head of its queue.
+	result, err := p.Plan(http.BuildRequest().WithURI("/items").Build())
+
+	expectedError := `Expected: GET /items
+    with header:
+        Authorization: Bearer token
+Actual: GET /items
+Error: header "Authorization" with value "Bearer token" expected, "" received
+`
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, expectedError)
+
+	// There is no queue for the request.
+	result, err = p.Plan(http.BuildRequest().WithURI("/unknown").Build())
+
+	expectedError = `Expected: GET /users
+Actual: GET /unknown
+Error: request uri "/users" expected, "/unknown" received
+`
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, expectedError)
+	assert.Len(t, p.Remain(), 3)
+}
+
+func TestFIFO_Concurrency(t *testing.T) {
+	t.Parallel()
+
+	p := planner.FIFO()
+
+	for i := 0; i < 10; i++ {
+		p.Expect(mockGetExpectation("/users", 1)(t))
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		matched int
+	)
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if _, err := p.Plan(http.BuildRequest().WithURI("/users").Build()); err == nil {
+				mu.Lock()
+				matched++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 10, matched)
+	assert.True(t, p.IsEmpty())
+}
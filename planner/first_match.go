@@ -0,0 +1,100 @@
+package planner
+
+import (
+	"net/http"
+	"sync"
+)
+
+var (
+	_ Planner     = (*firstMatch)(nil)
+	_ Snapshotter = (*firstMatch)(nil)
+	_ Replacer    = (*firstMatch)(nil)
+)
+
+type firstMatch struct {
+	expectations []Expectation
+
+	mu sync.Mutex
+}
+
+func (m *firstMatch) IsEmpty() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.expectations) == 0
+}
+
+func (m *firstMatch) Expect(e Expectation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expectations = append(m.expectations, e)
+}
+
+func (m *firstMatch) Plan(req *http.Request) (Expectation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.expectations) == 0 {
+		return nil, ErrNoExpectation
+	}
+
+	for i, expected := range m.expectations {
+		if !isRouteMatched(expected, req) || MatchRequest(expected, req) != nil {
+			continue
+		}
+
+		if !trackRepeatable(expected) {
+			m.expectations = append(m.expectations[:i:i], m.expectations[i+1:]...)
+		}
+
+		return expected, nil
+	}
+
+	return nil, mismatchError(m.expectations, req)
+}
+
+func (m *firstMatch) Remain() []Expectation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.expectations
+}
+
+func (m *firstMatch) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expectations = nil
+}
+
+func (m *firstMatch) Replace(remove func(e Expectation) bool, e Expectation) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var removed bool
+
+	m.expectations, removed = replaceExpectations(m.expectations, remove, e)
+
+	return removed
+}
+
+func (m *firstMatch) SnapshotQueue() (any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return copyExpectations(m.expectations), nil
+}
+
+func (m *firstMatch) RestoreQueue(queue any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expectations = copyExpectations(queue.([]Expectation)) // nolint: forcetypeassert
+}
+
+// FirstMatch creates a new Planner that matches the request against all the expectations in the order they were
+// registered and picks the first one that matches.
+func FirstMatch() Planner {
+	return &firstMatch{}
+}
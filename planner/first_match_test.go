@@ -0,0 +1,106 @@
+package planner_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock/matcher"
+	"go.nhat.io/httpmock/mock/http"
+	plannermock "go.nhat.io/httpmock/mock/planner"
+	"go.nhat.io/httpmock/planner"
+)
+
+func TestFirstMatch(t *testing.T) {
+	t.Parallel()
+
+	p := planner.FirstMatch()
+
+	users := mockGetExpectation("/users", 1)(t)
+	items := mockGetExpectation("/items", 0)(t)
+
+	p.Expect(users)
+	p.Expect(items)
+
+	// Match the 2nd expectation first.
+	result, err := p.Plan(http.BuildRequest().WithURI("/items").Build())
+
+	assert.NoError(t, err)
+	assert.Equal(t, items, result)
+	assert.Len(t, p.Remain(), 2)
+
+	// Match the 1st expectation, it is removed because it is exhausted.
+	result, err = p.Plan(http.BuildRequest().WithURI("/users").Build())
+
+	assert.NoError(t, err)
+	assert.Equal(t, users, result)
+	assert.Equal(t, []planner.Expectation{items}, p.Remain())
+
+	// Unlimited expectation stays.
+	result, err = p.Plan(http.BuildRequest().WithURI("/items").Build())
+
+	assert.NoError(t, err)
+	assert.Equal(t, items, result)
+	assert.Equal(t, []planner.Expectation{items}, p.Remain())
+}
+
+func TestFirstMatch_Mismatched(t *testing.T) {
+	t.Parallel()
+
+	p := planner.FirstMatch()
+
+	p.Expect(mockGetExpectation("/users", 1)(t))
+	p.Expect(plannermock.MockExpectation(func(e *plannermock.Expectation) {
+		e.On("Method").Maybe().Return(http.MethodGet)
+		e.On("URIMatcher").Maybe().Return(matcher.Match("/items"))
+		e.On("HeaderMatcher").Maybe().Return(matcher.HeaderMatcher{
+			"Authorization": matcher.Match("Bearer token"),
+		})
+		e.On("BodyMatcher").Maybe().Return(nil)
+	})(t))
+
+	result, err := p.Plan(http.BuildRequest().WithURI("/items").Build())
+
+	expectedError := `Expected: GET /items
+    with header:
+        Authorization: Bearer token
+Actual: GET /items
+Error: header "Authorization" with value "Bearer token" expected, "" received
+`
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, expectedError)
+	assert.Len(t, p.Remain(), 2)
+}
+
+func TestFirstMatch_Empty(t *testing.T) {
+	t.Parallel()
+
+	p := planner.FirstMatch()
+
+	assert.True(t, p.IsEmpty())
+
+	result, err := p.Plan(http.BuildRequest().Build())
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, planner.ErrNoExpectation)
+
+	p.Expect(plannermock.NoMockExpectation(t))
+
+	assert.False(t, p.IsEmpty())
+
+	p.Reset()
+
+	assert.True(t, p.IsEmpty())
+	assert.Empty(t, p.Remain())
+}
+
+func mockGetExpectation(uri string, times uint) plannermock.ExpectationMocker {
+	return plannermock.MockExpectation(func(e *plannermock.Expectation) {
+		e.On("Method").Maybe().Return(http.MethodGet)
+		e.On("URIMatcher").Maybe().Return(matcher.Match(uri))
+		e.On("HeaderMatcher").Maybe().Return(nil)
+		e.On("BodyMatcher").Maybe().Return(nil)
+		e.On("RemainTimes").Maybe().Return(times)
+	})
+}
@@ -1,9 +1,18 @@
 package planner
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
 )
 
+// RequestMatcher is an optional interface that an expectation can implement to match the request beyond its method, uri,
+// header and body, for example, the order of the headers.
+type RequestMatcher interface {
+	// MatchRequest returns an error if the request does not match.
+	MatchRequest(actual *http.Request) error
+}
+
 // MatchRequest checks whether a request is matched.
 func MatchRequest(expected Expectation, actual *http.Request) error {
 	if err := MatchMethod(expected, actual); err != nil {
@@ -22,6 +31,66 @@ func MatchRequest(expected Expectation, actual *http.Request) error {
 		return err
 	}
 
+	if m, ok := expected.(RequestMatcher); ok {
+		return matchRequest(expected, m, actual)
+	}
+
+	return nil
+}
+
+// MatchRequestAll checks whether a request is matched, like MatchRequest, but it does not stop at the first mismatch,
+// so the error reports all the divergences of the method, the uri, the header, the body and the request at once.
+func MatchRequestAll(expected Expectation, actual *http.Request) error {
+	matchers := []func(Expectation, *http.Request) error{MatchMethod, MatchURI, MatchHeader, MatchBody}
+
+	if m, ok := expected.(RequestMatcher); ok {
+		matchers = append(matchers, func(expected Expectation, actual *http.Request) error {
+			return matchRequest(expected, m, actual)
+		})
+	}
+
+	var mismatches []*Error
+
+	for _, match := range matchers {
+		if err := match(expected, actual); err != nil {
+			mismatches = append(mismatches, err.(*Error)) // nolint: errorlint,forcetypeassert
+		}
+	}
+
+	switch len(mismatches) {
+	case 0:
+		return nil
+
+	case 1:
+		return mismatches[0]
+	}
+
+	var sb strings.Builder
+
+	_, _ = fmt.Fprintf(&sb, "%d mismatches:", len(mismatches))
+
+	for _, err := range mismatches {
+		sb.WriteString("\n    - ")
+		sb.WriteString(strings.ReplaceAll(err.message(), "\n", "\n      "))
+	}
+
+	return NewError(expected, actual, "%s", sb.String())
+}
+
+// matchRequest matches the request with the RequestMatcher of the expectation.
+func matchRequest(expected Expectation, m RequestMatcher, actual *http.Request) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = NewError(expected, actual,
+				"could not match request: %s", recovered(p),
+			)
+		}
+	}()
+
+	if err := m.MatchRequest(actual); err != nil {
+		return NewError(expected, actual, "%s", err.Error())
+	}
+
 	return nil
 }
 
@@ -48,7 +117,7 @@ func MatchURI(expected Expectation, actual *http.Request) (err error) {
 		}
 	}()
 
-	matched, err := uri.Match(actual.RequestURI)
+	matched, err := matchRequestURI(uri, actual)
 	if err != nil {
 		return NewError(expected, actual,
 			"could not match request uri: %s", err.Error(),
@@ -118,3 +187,33 @@ func MatchBody(expected Expectation, actual *http.Request) (err error) {
 
 	return nil
 }
+
+// isRouteMatched checks whether the method and the uri of a given request match without building an error, so the
+// planners can skip the other expectations cheaply.
+func isRouteMatched(expected Expectation, actual *http.Request) (matched bool) {
+	if expected.Method() != actual.Method {
+		return false
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			matched = false
+		}
+	}()
+
+	matched, err := matchRequestURI(expected.URIMatcher(), actual)
+
+	return err == nil && matched
+}
+
+// mismatchError returns the error of the first expectation that has the same method and uri as the request, or the
+// error of the first expectation.
+func mismatchError(expectations []Expectation, actual *http.Request) error {
+	for _, expected := range expectations {
+		if isRouteMatched(expected, actual) {
+			return MatchRequest(expected, actual)
+		}
+	}
+
+	return MatchRequest(expectations[0], actual)
+}
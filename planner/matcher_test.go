@@ -2,6 +2,7 @@ package planner_test
 
 import (
 	"errors"
+	nethttp "net/http"
 	"regexp"
 	"testing"
 
@@ -273,3 +274,162 @@ Error: body does not match expectation, received: {"id":42}
 		})
 	}
 }
+
+type requestMatcherExpectation struct {
+	*plannermock.Expectation
+
+	match func(r *nethttp.Request) error
+}
+
+func (e requestMatcherExpectation) MatchRequest(r *nethttp.Request) error {
+	return e.match(r)
+}
+
+func TestMatchRequest_RequestMatcher(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		match         func(r *nethttp.Request) error
+		expectedError string
+	}{
+		{
+			scenario: "match panic",
+			match: func(*nethttp.Request) error {
+				panic("match panic")
+			},
+			expectedError: `Expected: GET /
+Actual: GET /
+Error: could not match request: match panic
+`,
+		},
+		{
+			scenario: "mismatched",
+			match: func(*nethttp.Request) error {
+				return errors.New("header order mismatched")
+			},
+			expectedError: `Expected: GET /
+Actual: GET /
+Error: header order mismatched
+`,
+		},
+		{
+			scenario: "mismatched with percent sign",
+			match: func(*nethttp.Request) error {
+				return errors.New(`query "name" with value "John%20Doe" expected`)
+			},
+			expectedError: `Expected: GET /
+Actual: GET /
+Error: query "name" with value "John%20Doe" expected
+`,
+		},
+		{
+			scenario: "matched",
+			match: func(*nethttp.Request) error {
+				return nil
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			expected := requestMatcherExpectation{
+				Expectation: plannermock.MockExpectation(func(e *plannermock.Expectation) {
+					e.On("URIMatcher").Return(matcher.Match("/"))
+					e.On("Method").Return(http.MethodGet)
+					e.On("HeaderMatcher").Return(nil)
+					e.On("BodyMatcher").Return(nil)
+				})(t),
+				match: tc.match,
+			}
+
+			err := planner.MatchRequest(expected, http.BuildRequest().Build())
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestMatchRequestAll(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		request       *nethttp.Request
+		expectedError string
+	}{
+		{
+			scenario: "one mismatch",
+			request: http.BuildRequest().
+				WithURI("/users").
+				WithHeader("Authorization", "Bearer token").
+				WithBody(`{"id":42}`).
+				Build(),
+			expectedError: `Expected: GET /users
+    with header:
+        Authorization: Bearer token
+    with body
+        {"id":42}
+Actual: GET /users
+    with header:
+        Authorization: Bearer token
+    with body
+        {"id":42}
+Error: header order mismatched
+`,
+		},
+		{
+			scenario: "all mismatches",
+			request: http.BuildRequest().
+				WithMethod(nethttp.MethodPost).
+				WithURI("/").
+				WithBody(`{"id":43}`).
+				Build(),
+			expectedError: `Expected: GET /users
+    with header:
+        Authorization: Bearer token
+    with body
+        {"id":42}
+Actual: POST /
+    with body
+        {"id":43}
+Error: 5 mismatches:
+    - method "GET" expected, "POST" received
+    - request uri "/users" expected, "/" received
+    - header "Authorization" with value "Bearer token" expected, "" received
+    - expected request body: {"id":42}, received: {"id":43}
+    - header order mismatched
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			expected := requestMatcherExpectation{
+				Expectation: plannermock.MockExpectation(func(e *plannermock.Expectation) {
+					e.On("URIMatcher").Return(matcher.Match("/users"))
+					e.On("Method").Return(http.MethodGet)
+					e.On("HeaderMatcher").Return(matcher.HeaderMatcher{"Authorization": matcher.Match("Bearer token")})
+					e.On("BodyMatcher").Return(matcher.Body(`{"id":42}`))
+				})(t),
+				match: func(*nethttp.Request) error {
+					return errors.New("header order mismatched")
+				},
+			}
+
+			err := planner.MatchRequestAll(expected, tc.request)
+
+			assert.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
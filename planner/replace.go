@@ -0,0 +1,80 @@
+package planner
+
+// Replacer is an optional interface that a planner can implement to replace or remove some expectations in its queue,
+// without losing the state of the others, see Replace.
+type Replacer interface {
+	// Replace removes the expectations that satisfy the condition and puts the new expectation, if it is not nil, at the
+	// position of the first removed one, or at the end if nothing is removed. It returns whether any expectation is
+	// removed.
+	Replace(remove func(e Expectation) bool, e Expectation) bool
+}
+
+// Replace removes the expectations that satisfy the condition from the planner and puts the new expectation, if it is
+// not nil, at the position of the first removed one, or at the end if nothing is removed. It returns whether any
+// expectation is removed. If the planner does not implement Replacer, the remaining expectations are added again after
+// a reset, so the planner loses its other state, for example, the positions of a round-robin planner.
+//
+//	planner.Replace(p, func(e planner.Expectation) bool {
+//		return e.Method() == http.MethodGet && e.URIMatcher().Expected() == "/users"
+//	}, newExpectation)
+func Replace(p Planner, remove func(e Expectation) bool, e Expectation) bool {
+	if r, ok := p.(Replacer); ok {
+		return r.Replace(remove, e)
+	}
+
+	remain, removed := replaceExpectations(p.Remain(), remove, e)
+
+	if !removed {
+		if e != nil {
+			p.Expect(e)
+		}
+
+		return false
+	}
+
+	p.Reset()
+
+	for _, expected := range remain {
+		p.Expect(expected)
+	}
+
+	return true
+}
+
+// hasExpectation checks whether an expectation of the planner satisfies the condition.
+func hasExpectation(p Planner, match func(e Expectation) bool) bool {
+	for _, e := range p.Remain() {
+		if match(e) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// replaceExpectations returns a copy of the expectations without the ones that satisfy the condition, and the new
+// expectation, if it is not nil, at the position of the first removed one, or at the end if nothing is removed.
+func replaceExpectations(expectations []Expectation, remove func(e Expectation) bool, e Expectation) ([]Expectation, bool) {
+	result := make([]Expectation, 0, len(expectations)+1)
+	removed := false
+
+	for _, expected := range expectations {
+		if !remove(expected) {
+			result = append(result, expected)
+
+			continue
+		}
+
+		if !removed && e != nil {
+			result = append(result, e)
+		}
+
+		removed = true
+	}
+
+	if !removed && e != nil {
+		result = append(result, e)
+	}
+
+	return result, removed
+}
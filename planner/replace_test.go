@@ -0,0 +1,146 @@
+package planner_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock/mock/http"
+	"go.nhat.io/httpmock/planner"
+)
+
+func isURI(uri string) func(e planner.Expectation) bool {
+	return func(e planner.Expectation) bool {
+		return e.URIMatcher().Expected() == uri
+	}
+}
+
+func TestReplace(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		planner  func() planner.Planner
+	}{
+		{scenario: "sequence", planner: planner.Sequence},
+		{scenario: "first match", planner: planner.FirstMatch},
+		{scenario: "fifo", planner: planner.FIFO},
+		{scenario: "round robin", planner: planner.RoundRobin},
+		{scenario: "chain", planner: func() planner.Planner {
+			return planner.Chain(planner.Accept(planner.FirstMatch(), func(e planner.Expectation) bool {
+				return e.URIMatcher().Expected() == "/items"
+			}), planner.Sequence())
+		}},
+		{scenario: "not a replacer", planner: func() planner.Planner {
+			return struct{ planner.Planner }{planner.Sequence()}
+		}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			users := mockGetExpectation("/users", 1)(t)
+			items := mockGetExpectation("/items", 1)(t)
+			users2 := mockGetExpectation("/users", 1)(t)
+			orders := mockGetExpectation("/orders", 1)(t)
+			overridden := mockGetExpectation("/users", 1)(t)
+
+			p := tc.planner()
+
+			p.Expect(users)
+			p.Expect(items)
+			p.Expect(users2)
+			p.Expect(orders)
+
+			assert.True(t, planner.Replace(p, isURI("/users"), overridden))
+			assert.ElementsMatch(t, []planner.Expectation{overridden, items, orders}, p.Remain())
+
+			// The new expectation takes the position of the first removed one.
+			result, err := p.Plan(http.BuildRequest().WithURI("/users").Build())
+
+			assert.NoError(t, err)
+			assert.Same(t, overridden, result)
+
+			// Nothing to remove, the new expectation is added at the end.
+			unknown := mockGetExpectation("/unknown", 1)(t)
+
+			assert.False(t, planner.Replace(p, isURI("/unknown"), unknown))
+			assert.Contains(t, p.Remain(), unknown)
+
+			// Remove only.
+			assert.True(t, planner.Replace(p, isURI("/unknown"), nil))
+			assert.NotContains(t, p.Remain(), unknown)
+		})
+	}
+}
+
+func TestReplace_RoundRobinKeepsPicks(t *testing.T) {
+	t.Parallel()
+
+	p := planner.RoundRobin()
+
+	backend1 := mockGetExpectation("/backend", 0)(t)
+	backend2 := mockGetExpectation("/backend", 0)(t)
+	items := mockGetExpectation("/items", 1)(t)
+
+	p.Expect(backend1)
+	p.Expect(backend2)
+	p.Expect(items)
+
+	req := http.BuildRequest().WithURI("/backend").Build()
+
+	result, err := p.Plan(req)
+
+	assert.NoError(t, err)
+	assert.Same(t, backend1, result)
+
+	planner.Replace(p, isURI("/items"), mockGetExpectation("/items", 1)(t))
+
+	// The rotation goes on.
+	result, err = p.Plan(req)
+
+	assert.NoError(t, err)
+	assert.Same(t, backend2, result)
+}
+
+func TestReplace_PerSession(t *testing.T) {
+	t.Parallel()
+
+	p := planner.PerSession(planner.SessionByHeader("X-Session-ID"), planner.Sequence)
+
+	users := mockGetExpectation("/users", 1)(t)
+	items := mockGetExpectation("/items", 1)(t)
+	orders := mockGetExpectation("/orders", 1)(t)
+	overridden := mockGetExpectation("/items", 1)(t)
+
+	users.On("Fulfilled").Once()
+	overridden.On("Fulfilled").Once()
+
+	p.Expect(users)
+	p.Expect(items)
+	p.Expect(orders)
+
+	plan := func(uri string) (planner.Expectation, error) {
+		return p.Plan(http.BuildRequest().WithURI(uri).WithHeader("X-Session-ID", "alice").Build())
+	}
+
+	result, err := plan("/users")
+
+	assert.NoError(t, err)
+
+	result.Fulfilled()
+
+	assert.True(t, planner.Replace(p, isURI("/items"), overridden))
+	assert.Equal(t, []planner.Expectation{users, overridden, orders}, p.Remain())
+
+	// The session keeps its progress and sees the new expectation.
+	result, err = plan("/items")
+
+	assert.NoError(t, err)
+
+	result.Fulfilled()
+
+	assert.Equal(t, uint(1), result.FulfilledTimes())
+}
@@ -0,0 +1,154 @@
+package planner
+
+import (
+	"net/http"
+	"sync"
+)
+
+var (
+	_ Planner     = (*roundRobin)(nil)
+	_ Snapshotter = (*roundRobin)(nil)
+	_ Replacer    = (*roundRobin)(nil)
+)
+
+type roundRobin struct {
+	expectations []Expectation
+	// picks is the number of times an expectation was picked.
+	picks map[Expectation]uint
+
+	mu sync.Mutex
+}
+
+func (m *roundRobin) IsEmpty() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.expectations) == 0
+}
+
+func (m *roundRobin) Expect(e Expectation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expectations = append(m.expectations, e)
+}
+
+func (m *roundRobin) Plan(req *http.Request) (Expectation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.expectations) == 0 {
+		return nil, ErrNoExpectation
+	}
+
+	picked := -1
+
+	for i, expected := range m.expectations {
+		if !isRouteMatched(expected, req) || MatchRequest(expected, req) != nil {
+			continue
+		}
+
+		if picked < 0 || m.picks[expected] < m.picks[m.expectations[picked]] {
+			picked = i
+		}
+	}
+
+	if picked < 0 {
+		return nil, mismatchError(m.expectations, req)
+	}
+
+	expected := m.expectations[picked]
+
+	if trackRepeatable(expected) {
+		m.picks[expected]++
+	} else {
+		m.expectations = append(m.expectations[:picked:picked], m.expectations[picked+1:]...)
+
+		delete(m.picks, expected)
+	}
+
+	return expected, nil
+}
+
+func (m *roundRobin) Remain() []Expectation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.expectations
+}
+
+func (m *roundRobin) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expectations = nil
+	m.picks = make(map[Expectation]uint)
+}
+
+// roundRobinQueue is the queue of a round-robin planner, see Snapshotter.
+type roundRobinQueue struct {
+	expectations []Expectation
+	picks        map[Expectation]uint
+}
+
+// Replace keeps the number of picks of the remaining expectations, so the rotation goes on.
+func (m *roundRobin) Replace(remove func(e Expectation) bool, e Expectation) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, expected := range m.expectations {
+		if remove(expected) {
+			delete(m.picks, expected)
+		}
+	}
+
+	var removed bool
+
+	m.expectations, removed = replaceExpectations(m.expectations, remove, e)
+
+	return removed
+}
+
+func (m *roundRobin) SnapshotQueue() (any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return roundRobinQueue{
+		expectations: copyExpectations(m.expectations),
+		picks:        copyPicks(m.picks),
+	}, nil
+}
+
+func (m *roundRobin) RestoreQueue(queue any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q := queue.(roundRobinQueue) // nolint: forcetypeassert
+
+	m.expectations = copyExpectations(q.expectations)
+	m.picks = copyPicks(q.picks)
+}
+
+func copyPicks(picks map[Expectation]uint) map[Expectation]uint {
+	result := make(map[Expectation]uint, len(picks))
+
+	for e, n := range picks {
+		result[e] = n
+	}
+
+	return result
+}
+
+// RoundRobin creates a new Planner that cycles through all the expectations that match the request, in the order they
+// were registered. It is useful to simulate a load-balanced upstream that returns the responses from different
+// backends.
+//
+//	s := httpmock.NewServer().WithPlanner(planner.RoundRobin())
+//
+//	s.ExpectGet("/backend").Return("backend 1").UnlimitedTimes()
+//	s.ExpectGet("/backend").Return("backend 2").UnlimitedTimes()
+func RoundRobin() Planner {
+	return &roundRobin{
+		picks: make(map[Expectation]uint),
+	}
+}
@@ -0,0 +1,77 @@
+package planner_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock/mock/http"
+	plannermock "go.nhat.io/httpmock/mock/planner"
+	"go.nhat.io/httpmock/planner"
+)
+
+func TestRoundRobin(t *testing.T) {
+	t.Parallel()
+
+	p := planner.RoundRobin()
+
+	backend1 := mockGetExpectation("/backend", 0)(t)
+	items := mockGetExpectation("/items", 0)(t)
+	backend2 := mockGetExpectation("/backend", 0)(t)
+	backend3 := mockGetExpectation("/backend", 1)(t)
+
+	p.Expect(backend1)
+	p.Expect(items)
+	p.Expect(backend2)
+	p.Expect(backend3)
+
+	req := http.BuildRequest().WithURI("/backend").Build()
+
+	for _, expected := range []planner.Expectation{backend1, backend2, backend3, backend1, backend2, backend1} {
+		result, err := p.Plan(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+
+		// Other requests do not interfere.
+		result, err = p.Plan(http.BuildRequest().WithURI("/items").Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, items, result)
+	}
+
+	// The exhausted expectation is removed.
+	assert.Equal(t, []planner.Expectation{backend1, items, backend2}, p.Remain())
+
+	result, err := p.Plan(http.BuildRequest().WithURI("/unknown").Build())
+
+	expectedError := `Expected: GET /backend
+Actual: GET /unknown
+Error: request uri "/backend" expected, "/unknown" received
+`
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, expectedError)
+}
+
+func TestRoundRobin_Empty(t *testing.T) {
+	t.Parallel()
+
+	p := planner.RoundRobin()
+
+	assert.True(t, p.IsEmpty())
+
+	result, err := p.Plan(http.BuildRequest().Build())
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, planner.ErrNoExpectation)
+
+	p.Expect(plannermock.NoMockExpectation(t))
+
+	assert.False(t, p.IsEmpty())
+
+	p.Reset()
+
+	assert.True(t, p.IsEmpty())
+	assert.Empty(t, p.Remain())
+}
@@ -5,7 +5,12 @@ import (
 	"sync"
 )
 
-var _ Planner = (*sequence)(nil)
+var (
+	_ Planner     = (*sequence)(nil)
+	_ Validator   = (*sequence)(nil)
+	_ Snapshotter = (*sequence)(nil)
+	_ Replacer    = (*sequence)(nil)
+)
 
 type sequence struct {
 	expectations []Expectation
@@ -31,6 +36,10 @@ func (s *sequence) Plan(req *http.Request) (Expectation, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if len(s.expectations) == 0 {
+		return nil, ErrNoExpectation
+	}
+
 	if err := MatchRequest(s.expectations[0], req); err != nil {
 		return nil, err
 	}
@@ -48,6 +57,22 @@ func (s *sequence) Remain() []Expectation {
 	return s.expectations
 }
 
+// Validate reports the expectations after an unlimited expectation, the sequence never moves past it.
+func (s *sequence) Validate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, expected := range s.expectations {
+		if expected.RemainTimes() != unlimitedTimes || i == len(s.expectations)-1 {
+			continue
+		}
+
+		return unreachableError(expected, s.expectations[i+1:])
+	}
+
+	return nil
+}
+
 func (s *sequence) Reset() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -55,6 +80,31 @@ func (s *sequence) Reset() {
 	s.expectations = nil
 }
 
+func (s *sequence) Replace(remove func(e Expectation) bool, e Expectation) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var removed bool
+
+	s.expectations, removed = replaceExpectations(s.expectations, remove, e)
+
+	return removed
+}
+
+func (s *sequence) SnapshotQueue() (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return copyExpectations(s.expectations), nil
+}
+
+func (s *sequence) RestoreQueue(queue any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expectations = copyExpectations(queue.([]Expectation)) // nolint: forcetypeassert
+}
+
 // Sequence creates a new Planner that matches the request sequentially.
 func Sequence() Planner {
 	return &sequence{}
@@ -163,6 +163,11 @@ func TestSequence_Empty(t *testing.T) {
 
 	assert.True(t, p.IsEmpty())
 
+	result, err := p.Plan(http.BuildRequest().Build())
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, planner.ErrNoExpectation)
+
 	p.Expect(plannermock.NoMockExpectation(t))
 
 	assert.False(t, p.IsEmpty())
@@ -186,3 +191,62 @@ func TestSequence_Reset(t *testing.T) {
 
 	assert.Empty(t, p.Remain())
 }
+
+func TestSequence_Validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		expectations  []plannermock.ExpectationMocker
+		expectedError string
+	}{
+		{
+			scenario: "no unlimited expectation",
+			expectations: []plannermock.ExpectationMocker{
+				mockGetExpectation("/a", 1),
+				mockGetExpectation("/b", 2),
+			},
+		},
+		{
+			scenario: "unlimited expectation at the end",
+			expectations: []plannermock.ExpectationMocker{
+				mockGetExpectation("/a", 1),
+				mockGetExpectation("/b", 0),
+			},
+		},
+		{
+			scenario: "unreachable expectations",
+			expectations: []plannermock.ExpectationMocker{
+				mockGetExpectation("/a", 1),
+				mockGetExpectation("/b", 0),
+				mockGetExpectation("/c", 1),
+				mockGetExpectation("/b", 0),
+			},
+			expectedError: `there are expectations that can never be reached because of the unlimited expectation GET /b
+- GET /c
+- GET /b
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			p := planner.Sequence()
+
+			for _, mockExpectation := range tc.expectations {
+				p.Expect(mockExpectation(t))
+			}
+
+			err := planner.Validate(p)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package planner
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+)
+
+var (
+	_ Planner       = (*perSession)(nil)
+	_ Snapshotter   = (*perSession)(nil)
+	_ Replacer      = (*perSession)(nil)
+	_ TimesRestorer = (*sessionExpectation)(nil)
+	_ Unwrapper     = (*sessionExpectation)(nil)
+)
+
+// SessionKeyFunc identifies the client session of a request.
+type SessionKeyFunc func(r *http.Request) string
+
+// SessionByHeader identifies the client session by a request header, for example, X-Session-ID.
+func SessionByHeader(header string) SessionKeyFunc {
+	return func(r *http.Request) string {
+		return r.Header.Get(header)
+	}
+}
+
+// SessionByRemoteAddr identifies the client session by the remote address of the connection.
+func SessionByRemoteAddr() SessionKeyFunc {
+	return func(r *http.Request) string {
+		return r.RemoteAddr
+	}
+}
+
+// expectationHandler is the same as httpmock.ExpectationHandler.
+type expectationHandler interface {
+	Handle(w http.ResponseWriter, r *http.Request, defaultHeaders map[string]string) error
+}
+
+// sessionExpectation is the view of an expectation in a session. It counts the calls of the session and still reports
+// them to the original expectation.
+type sessionExpectation struct {
+	Expectation
+
+	remainTimes    uint
+	fulfilledTimes uint
+
+	mu sync.Mutex
+}
+
+// Unwrap returns the original expectation.
+func (e *sessionExpectation) Unwrap() Expectation {
+	return e.Expectation
+}
+
+func (e *sessionExpectation) RemainTimes() uint {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.remainTimes
+}
+
+func (e *sessionExpectation) Fulfilled() {
+	e.mu.Lock()
+
+	if e.remainTimes > 0 {
+		e.remainTimes--
+	}
+
+	e.fulfilledTimes++
+
+	e.mu.Unlock()
+
+	e.Expectation.Fulfilled()
+}
+
+func (e *sessionExpectation) FulfilledTimes() uint {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.fulfilledTimes
+}
+
+func (e *sessionExpectation) RestoreTimes(remainTimes, fulfilledTimes uint) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.remainTimes = remainTimes
+	e.fulfilledTimes = fulfilledTimes
+}
+
+func (e *sessionExpectation) Handle(w http.ResponseWriter, r *http.Request, defaultHeaders map[string]string) error {
+	h, ok := e.Expectation.(expectationHandler)
+	if !ok {
+		return errors.New("could not handle request: expectation is not a handler") // nolint: goerr113
+	}
+
+	return h.Handle(w, r, defaultHeaders)
+}
+
+type sessionTimes struct {
+	expectation Expectation
+	times       uint
+}
+
+type perSession struct {
+	key        SessionKeyFunc
+	newPlanner func() Planner
+
+	expectations []sessionTimes
+	sessions     map[string]Planner
+
+	mu sync.Mutex
+}
+
+func (p *perSession) IsEmpty() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.expectations) == 0
+}
+
+func (p *perSession) Expect(e Expectation) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	st := sessionTimes{expectation: e, times: e.RemainTimes()}
+
+	p.expectations = append(p.expectations, st)
+
+	for _, s := range p.sessions {
+		s.Expect(newSessionExpectation(st))
+	}
+}
+
+func (p *perSession) Plan(req *http.Request) (Expectation, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.expectations) == 0 {
+		return nil, ErrNoExpectation
+	}
+
+	key := p.key(req)
+
+	s, ok := p.sessions[key]
+	if !ok {
+		s = p.newPlanner()
+
+		for _, st := range p.expectations {
+			s.Expect(newSessionExpectation(st))
+		}
+
+		p.sessions[key] = s
+	}
+
+	if s.IsEmpty() {
+		return nil, ErrNoExpectation
+	}
+
+	return s.Plan(req)
+}
+
+func (p *perSession) Remain() []Expectation {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	result := make([]Expectation, len(p.expectations))
+
+	for i, st := range p.expectations {
+		result[i] = st.expectation
+	}
+
+	return result
+}
+
+func (p *perSession) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.expectations = nil
+	p.sessions = make(map[string]Planner)
+}
+
+// Replace replaces the expectations in every session too, so the sessions keep their progress.
+func (p *perSession) Replace(remove func(e Expectation) bool, e Expectation) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var st sessionTimes
+
+	if e != nil {
+		st = sessionTimes{expectation: e, times: e.RemainTimes()}
+	}
+
+	expectations := make([]sessionTimes, 0, len(p.expectations)+1)
+	removed := false
+
+	for _, existing := range p.expectations {
+		if !remove(existing.expectation) {
+			expectations = append(expectations, existing)
+
+			continue
+		}
+
+		if !removed && e != nil {
+			expectations = append(expectations, st)
+		}
+
+		removed = true
+	}
+
+	if !removed && e != nil {
+		expectations = append(expectations, st)
+	}
+
+	p.expectations = expectations
+
+	removeInSession := func(e Expectation) bool {
+		return remove(Unwrap(e))
+	}
+
+	for _, s := range p.sessions {
+		if e == nil {
+			Replace(s, removeInSession, nil)
+		} else {
+			Replace(s, removeInSession, newSessionExpectation(st))
+		}
+	}
+
+	return removed
+}
+
+// perSessionQueue is the queue of a per-session planner, see Snapshotter.
+type perSessionQueue struct {
+	expectations []sessionTimes
+	sessions     map[string]*Checkpoint
+}
+
+func (p *perSession) SnapshotQueue() (any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	q := perSessionQueue{
+		expectations: make([]sessionTimes, len(p.expectations)),
+		sessions:     make(map[string]*Checkpoint, len(p.sessions)),
+	}
+
+	copy(q.expectations, p.expectations)
+
+	for key, s := range p.sessions {
+		cp, err := Snapshot(s)
+		if err != nil {
+			return nil, err
+		}
+
+		q.sessions[key] = cp
+	}
+
+	return q, nil
+}
+
+func (p *perSession) RestoreQueue(queue any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	q := queue.(perSessionQueue) // nolint: forcetypeassert
+
+	p.expectations = make([]sessionTimes, len(q.expectations))
+	p.sessions = make(map[string]Planner, len(q.sessions))
+
+	copy(p.expectations, q.expectations)
+
+	for key, cp := range q.sessions {
+		_ = Restore(cp.planner, cp) // nolint: errcheck // The checkpoint is taken from the same planner.
+
+		p.sessions[key] = cp.planner
+	}
+}
+
+func newSessionExpectation(st sessionTimes) *sessionExpectation {
+	return &sessionExpectation{
+		Expectation: st.expectation,
+		remainTimes: st.times,
+	}
+}
+
+// PerSession creates a new Planner that gives every client session its own planner, so different clients receive
+// independent response sequences from the same expectations. The calls of all the sessions are still counted together
+// to verify the expectations.
+//
+//	s := httpmock.NewServer().
+//		WithPlanner(planner.PerSession(planner.SessionByHeader("X-Session-ID"), planner.Sequence))
+func PerSession(key SessionKeyFunc, newPlanner func() Planner) Planner {
+	return &perSession{
+		key:        key,
+		newPlanner: newPlanner,
+		sessions:   make(map[string]Planner),
+	}
+}
@@ -0,0 +1,121 @@
+package planner_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock/mock/http"
+	plannermock "go.nhat.io/httpmock/mock/planner"
+	"go.nhat.io/httpmock/planner"
+)
+
+func TestPerSession(t *testing.T) {
+	t.Parallel()
+
+	p := planner.PerSession(planner.SessionByHeader("X-Session-ID"), planner.Sequence)
+
+	first := mockGetExpectation("/", 1)(t)
+	second := mockGetExpectation("/", 1)(t)
+
+	first.On("Fulfilled").Twice()
+	second.On("Fulfilled").Once()
+
+	p.Expect(first)
+	p.Expect(second)
+
+	plan := func(session string) planner.Expectation {
+		result, err := p.Plan(http.BuildRequest().WithHeader("X-Session-ID", session).Build())
+
+		assert.NoError(t, err)
+
+		result.Fulfilled()
+
+		return result
+	}
+
+	// Every session goes through the sequence independently.
+	alice := plan("alice")
+	bob := plan("bob")
+
+	assert.Equal(t, first.Method(), alice.Method())
+	assert.Equal(t, uint(1), alice.FulfilledTimes())
+	assert.Equal(t, uint(1), bob.FulfilledTimes())
+	assert.NotSame(t, alice, bob)
+
+	alice = plan("alice")
+
+	assert.Equal(t, uint(1), alice.FulfilledTimes())
+
+	// The session is exhausted.
+	result, err := p.Plan(http.BuildRequest().WithHeader("X-Session-ID", "alice").Build())
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, planner.ErrNoExpectation)
+
+	// The original expectations remain.
+	assert.Equal(t, []planner.Expectation{first, second}, p.Remain())
+	assert.False(t, p.IsEmpty())
+
+	p.Reset()
+
+	assert.True(t, p.IsEmpty())
+	assert.Empty(t, p.Remain())
+}
+
+func TestPerSession_ExpectAfterSessionStarted(t *testing.T) {
+	t.Parallel()
+
+	p := planner.PerSession(planner.SessionByRemoteAddr(), planner.FirstMatch)
+
+	users := mockGetExpectation("/users", 0)(t)
+	items := mockGetExpectation("/items", 0)(t)
+
+	p.Expect(users)
+
+	result, err := p.Plan(http.BuildRequest().WithURI("/users").Build())
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), result.RemainTimes())
+
+	p.Expect(items)
+
+	result, err = p.Plan(http.BuildRequest().WithURI("/items").Build())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/items", result.URIMatcher().Expected())
+}
+
+func TestPerSession_Empty(t *testing.T) {
+	t.Parallel()
+
+	p := planner.PerSession(planner.SessionByRemoteAddr(), planner.Sequence)
+
+	result, err := p.Plan(http.BuildRequest().Build())
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, planner.ErrNoExpectation)
+
+	p.Expect(plannermock.MockExpectation(func(e *plannermock.Expectation) {
+		e.On("RemainTimes").Return(uint(1))
+	})(t))
+
+	assert.False(t, p.IsEmpty())
+}
+
+func TestPerSession_Unwrap(t *testing.T) {
+	t.Parallel()
+
+	p := planner.PerSession(planner.SessionByRemoteAddr(), planner.Sequence)
+
+	users := mockGetExpectation("/users", 0)(t)
+
+	p.Expect(users)
+
+	result, err := p.Plan(http.BuildRequest().WithURI("/users").Build())
+
+	assert.NoError(t, err)
+	assert.NotSame(t, users, result)
+	assert.Same(t, users, planner.Unwrap(result))
+	assert.Same(t, users, planner.Unwrap(users))
+}
@@ -0,0 +1,112 @@
+package planner
+
+import (
+	"errors"
+)
+
+// ErrSnapshotNotSupported indicates that the planner does not implement Snapshotter.
+var ErrSnapshotNotSupported = errors.New("planner does not support snapshot")
+
+// Snapshotter is an optional interface that a planner can implement to capture and restore its queue of expectations,
+// see Snapshot and Restore.
+type Snapshotter interface {
+	// SnapshotQueue returns a copy of the queue of expectations, in a format that only the planner understands.
+	SnapshotQueue() (any, error)
+	// RestoreQueue replaces the queue of expectations with a copy of the one returned by SnapshotQueue.
+	RestoreQueue(queue any)
+}
+
+// TimesRestorer is an optional interface that an expectation can implement to restore its call counts, see Restore.
+type TimesRestorer interface {
+	// RestoreTimes sets the remaining and the fulfilled times of the expectation.
+	RestoreTimes(remainTimes, fulfilledTimes uint)
+}
+
+// Checkpoint is the state of a planner, its queue of expectations and their call counts, see Snapshot.
+type Checkpoint struct {
+	planner Planner
+	queue   any
+	times   []expectationTimes
+}
+
+// expectationTimes are the call counts of an expectation.
+type expectationTimes struct {
+	expectation    Expectation
+	remainTimes    uint
+	fulfilledTimes uint
+}
+
+// Snapshot captures the queue of expectations of the planner and the call counts of the expectations in the queue, so
+// they can be restored later, for example, to roll back to a checkpoint between the phases of a long test.
+//
+//	p := planner.Sequence()
+//
+//	// Your expectations and requests.
+//
+//	cp, err := planner.Snapshot(p)
+//
+//	// More expectations and requests.
+//
+//	err = planner.Restore(p, cp)
+func Snapshot(p Planner) (*Checkpoint, error) {
+	s, ok := p.(Snapshotter)
+	if !ok {
+		return nil, ErrSnapshotNotSupported
+	}
+
+	queue, err := s.SnapshotQueue()
+	if err != nil {
+		return nil, err
+	}
+
+	remain := p.Remain()
+	times := make([]expectationTimes, len(remain))
+
+	for i, e := range remain {
+		times[i] = expectationTimes{
+			expectation:    e,
+			remainTimes:    e.RemainTimes(),
+			fulfilledTimes: e.FulfilledTimes(),
+		}
+	}
+
+	return &Checkpoint{planner: p, queue: queue, times: times}, nil
+}
+
+// Restore restores the queue of expectations of the planner and the call counts of the expectations in the queue to the
+// checkpoint. The expectations added after the checkpoint are removed, and the ones matched after the checkpoint could
+// be matched again. The call counts are only restored for the expectations that implement TimesRestorer. A checkpoint
+// could be restored many times.
+func Restore(p Planner, cp *Checkpoint) error {
+	s, ok := p.(Snapshotter)
+	if !ok {
+		return ErrSnapshotNotSupported
+	}
+
+	if cp == nil || cp.planner != p {
+		return errors.New("could not restore planner: checkpoint is not taken from the planner") // nolint: goerr113
+	}
+
+	s.RestoreQueue(cp.queue)
+
+	for _, t := range cp.times {
+		if r, ok := t.expectation.(TimesRestorer); ok {
+			r.RestoreTimes(t.remainTimes, t.fulfilledTimes)
+		}
+	}
+
+	return nil
+}
+
+// copyExpectations copies the expectations, so the queue of a planner and a checkpoint do not share the same array.
+func copyExpectations(expectations []Expectation) []Expectation {
+	if expectations == nil {
+		return nil
+	}
+
+	result := make([]Expectation, len(expectations))
+
+	copy(result, expectations)
+
+	return result
+}
@@ -0,0 +1,150 @@
+package planner_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+	plannermock "go.nhat.io/httpmock/mock/planner"
+	"go.nhat.io/httpmock/planner"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario   string
+		newPlanner func() planner.Planner
+	}{
+		{
+			scenario:   "first match",
+			newPlanner: planner.FirstMatch,
+		},
+		{
+			scenario:   "sequence",
+			newPlanner: planner.Sequence,
+		},
+		{
+			scenario:   "fifo",
+			newPlanner: planner.FIFO,
+		},
+		{
+			scenario:   "round robin",
+			newPlanner: planner.RoundRobin,
+		},
+		{
+			scenario: "chain",
+			newPlanner: func() planner.Planner {
+				return planner.Chain(
+					planner.Accept(planner.FirstMatch(), func(e planner.Expectation) bool {
+						return e.Method() == http.MethodOptions
+					}),
+					planner.Sequence(),
+				)
+			},
+		},
+		{
+			scenario: "per session",
+			newPlanner: func() planner.Planner {
+				return planner.PerSession(planner.SessionByHeader("X-Session-ID"), planner.Sequence)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			p := tc.newPlanner()
+
+			users := httpmock.NewExpectation(http.MethodGet, "/users")
+			items := httpmock.NewExpectation(http.MethodGet, "/items")
+
+			users.Twice()
+
+			p.Expect(users)
+			p.Expect(items)
+
+			plan := func(uri string) {
+				result, err := p.Plan(httptest.NewRequest(http.MethodGet, uri, nil))
+				require.NoError(t, err)
+
+				result.Fulfilled()
+			}
+
+			plan("/users")
+
+			cp, err := planner.Snapshot(p)
+			require.NoError(t, err)
+
+			plan("/users")
+			plan("/items")
+
+			p.Expect(httpmock.NewExpectation(http.MethodGet, "/extra"))
+
+			err = planner.Restore(p, cp)
+			require.NoError(t, err)
+
+			assert.Len(t, p.Remain(), 2)
+			assert.Equal(t, uint(1), users.RemainTimes())
+			assert.Equal(t, uint(1), users.FulfilledTimes())
+			assert.Equal(t, uint(1), items.RemainTimes())
+			assert.Equal(t, uint(0), items.FulfilledTimes())
+
+			// The expectations after the checkpoint could be matched again, the checkpoint could be restored again.
+			plan("/users")
+			plan("/items")
+
+			err = planner.Restore(p, cp)
+			require.NoError(t, err)
+
+			plan("/users")
+			plan("/items")
+
+			assert.Equal(t, uint(2), users.FulfilledTimes())
+			assert.Equal(t, uint(1), items.FulfilledTimes())
+		})
+	}
+}
+
+func TestSnapshot_NotSupported(t *testing.T) {
+	t.Parallel()
+
+	p := plannermock.NewPlanner(t)
+
+	cp, err := planner.Snapshot(p)
+
+	assert.Nil(t, cp)
+	assert.ErrorIs(t, err, planner.ErrSnapshotNotSupported)
+
+	err = planner.Restore(p, nil)
+
+	assert.ErrorIs(t, err, planner.ErrSnapshotNotSupported)
+}
+
+func TestSnapshot_ChainNotSupported(t *testing.T) {
+	t.Parallel()
+
+	p := planner.Chain(planner.FirstMatch(), plannermock.NewPlanner(t))
+
+	cp, err := planner.Snapshot(p)
+
+	assert.Nil(t, cp)
+	assert.ErrorIs(t, err, planner.ErrSnapshotNotSupported)
+}
+
+func TestRestore_AnotherPlanner(t *testing.T) {
+	t.Parallel()
+
+	cp, err := planner.Snapshot(planner.Sequence())
+	require.NoError(t, err)
+
+	err = planner.Restore(planner.Sequence(), cp)
+
+	assert.EqualError(t, err, "could not restore planner: checkpoint is not taken from the planner")
+}
@@ -0,0 +1,75 @@
+package planner
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.nhat.io/httpmock/matcher"
+)
+
+// TargetURL returns the target of the request, with the scheme and the host in lower case, and without the default
+// port. The target of an absolute-form request, for example, a request to a forward proxy, is its request uri. The target
+// of an origin-form request is built from the connection and the Host header.
+func TargetURL(r *http.Request) *url.URL {
+	u := *r.URL
+
+	if !IsAbsoluteForm(r) {
+		u.Scheme, u.Host = "http", r.Host
+
+		if r.TLS != nil {
+			u.Scheme = "https"
+		}
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = normalizeHost(u.Scheme, u.Host)
+
+	return &u
+}
+
+// IsAbsoluteForm checks whether the request uri is in the absolute form, for example, http://example.com/path, which is
+// sent by the clients to a forward proxy.
+func IsAbsoluteForm(r *http.Request) bool {
+	return r.URL.IsAbs() && !strings.HasPrefix(r.RequestURI, "/")
+}
+
+// normalizeHost lowers the case of the host and removes the default port of the scheme.
+func normalizeHost(scheme, host string) string {
+	host = strings.ToLower(host)
+
+	h, port, err := net.SplitHostPort(host)
+	if err != nil {
+		return host
+	}
+
+	if (scheme == "http" && port == "80") || (scheme == "https" && port == "443") {
+		if strings.Contains(h, ":") {
+			return "[" + h + "]"
+		}
+
+		return h
+	}
+
+	return host
+}
+
+// matchRequestURI matches the request uri. An absolute-form request uri is also matched by its normalized form and by
+// its origin form, so the expectations of the path still match the requests sent to a forward proxy.
+func matchRequestURI(uri matcher.Matcher, actual *http.Request) (bool, error) {
+	candidates := []string{actual.RequestURI}
+
+	if IsAbsoluteForm(actual) {
+		candidates = append(candidates, TargetURL(actual).String(), actual.URL.RequestURI())
+	}
+
+	for _, candidate := range candidates {
+		matched, err := uri.Match(candidate)
+		if err != nil || matched {
+			return matched, err
+		}
+	}
+
+	return false, nil
+}
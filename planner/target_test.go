@@ -0,0 +1,135 @@
+package planner_test
+
+import (
+	"crypto/tls"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock/matcher"
+	"go.nhat.io/httpmock/mock/http"
+	plannermock "go.nhat.io/httpmock/mock/planner"
+	"go.nhat.io/httpmock/planner"
+)
+
+func TestTargetURL(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		request       func() *nethttp.Request
+		expectedURL   string
+		expectedIsAbs bool
+	}{
+		{
+			scenario: "origin form",
+			request: func() *nethttp.Request {
+				return httptest.NewRequest(nethttp.MethodGet, "/users?page=2", nil)
+			},
+			expectedURL: "http://example.com/users?page=2",
+		},
+		{
+			scenario: "origin form with tls",
+			request: func() *nethttp.Request {
+				r := httptest.NewRequest(nethttp.MethodGet, "/users", nil)
+				r.Host = "API.example.com:443"
+				r.TLS = &tls.ConnectionState{}
+
+				return r
+			},
+			expectedURL: "https://api.example.com/users",
+		},
+		{
+			scenario: "absolute form",
+			request: func() *nethttp.Request {
+				return httptest.NewRequest(nethttp.MethodGet, "HTTP://API.Example.com:80/users?page=2", nil)
+			},
+			expectedURL:   "http://api.example.com/users?page=2",
+			expectedIsAbs: true,
+		},
+		{
+			scenario: "absolute form with port",
+			request: func() *nethttp.Request {
+				return httptest.NewRequest(nethttp.MethodGet, "http://api.example.com:8080/users", nil)
+			},
+			expectedURL:   "http://api.example.com:8080/users",
+			expectedIsAbs: true,
+		},
+		{
+			scenario: "absolute form with ipv6",
+			request: func() *nethttp.Request {
+				return httptest.NewRequest(nethttp.MethodGet, "https://[::1]:443/users", nil)
+			},
+			expectedURL:   "https://[::1]/users",
+			expectedIsAbs: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			r := tc.request()
+
+			assert.Equal(t, tc.expectedURL, planner.TargetURL(r).String())
+			assert.Equal(t, tc.expectedIsAbs, planner.IsAbsoluteForm(r))
+		})
+	}
+}
+
+func TestMatchURI_AbsoluteForm(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		uri           any
+		expectedError string
+	}{
+		{
+			scenario: "request uri",
+			uri:      matcher.Match("HTTP://API.Example.com:80/users?page=2"),
+		},
+		{
+			scenario: "normalized",
+			uri:      matcher.Match("http://api.example.com/users?page=2"),
+		},
+		{
+			scenario: "origin form",
+			uri:      matcher.Match("/users?page=2"),
+		},
+		{
+			scenario: "mismatched",
+			uri:      matcher.Match("http://example.com/users?page=2"),
+			expectedError: `Expected: GET http://example.com/users?page=2
+Actual: GET HTTP://API.Example.com:80/users?page=2
+Error: request uri "http://example.com/users?page=2" expected, "HTTP://API.Example.com:80/users?page=2" received
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			expected := plannermock.MockExpectation(func(e *plannermock.Expectation) {
+				e.On("URIMatcher").Return(tc.uri)
+				e.On("Method").Maybe().Return(http.MethodGet)
+				e.On("HeaderMatcher").Maybe().Return(nil)
+				e.On("BodyMatcher").Maybe().Return(nil)
+			})(t)
+
+			r := httptest.NewRequest(nethttp.MethodGet, "HTTP://API.Example.com:80/users?page=2", nil)
+			err := planner.MatchURI(expected, r)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
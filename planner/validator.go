@@ -0,0 +1,50 @@
+package planner
+
+import (
+	"errors"
+	"strings"
+
+	"go.nhat.io/httpmock/format"
+)
+
+// Validator is an optional interface that a planner can implement to report the expectations that can never be
+// reached.
+type Validator interface {
+	// Validate returns an error if some expectations can never be reached.
+	Validate() error
+}
+
+// Validate validates the expectations of the planner if it implements Validator.
+func Validate(p Planner) error {
+	if v, ok := p.(Validator); ok {
+		return v.Validate()
+	}
+
+	return nil
+}
+
+// unreachableError returns an error that lists the expectations that can never be reached because of an unlimited
+// expectation.
+func unreachableError(unlimited Expectation, unreachable []Expectation) error {
+	var sb strings.Builder
+
+	sb.WriteString("there are expectations that can never be reached because of the unlimited expectation ")
+	formatExpectation(&sb, unlimited)
+
+	for _, expected := range unreachable {
+		sb.WriteString("- ")
+		formatExpectation(&sb, expected)
+	}
+
+	// nolint:goerr113
+	return errors.New(sb.String())
+}
+
+func formatExpectation(sb *strings.Builder, expected Expectation) {
+	format.ExpectedRequest(sb,
+		expected.Method(),
+		expected.URIMatcher(),
+		expected.HeaderMatcher(),
+		expected.BodyMatcher(),
+	)
+}
@@ -0,0 +1,220 @@
+package presets
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.nhat.io/httpmock"
+)
+
+// CRUD serves an in-memory store of JSON documents at the path, so a simple CRUD client can be tested without writing an
+// expectation for every request. The endpoints are background expectations, like Health, and every server has its own
+// store.
+//
+//	POST   /items       stores the document and responds 201 with it. The id of the document is its "id" field, or a
+//	                    generated number if it does not have one.
+//	GET    /items       responds all the documents, in the order they were stored.
+//	GET    /items/{id}  responds the document.
+//	PUT    /items/{id}  replaces the document and responds 200 with it.
+//	DELETE /items/{id}  removes the document and responds 204.
+//
+// The endpoints of a document respond 404 if it does not exist.
+//
+//	srv := httpmock.New(presets.CRUD("/items"))(t)
+func CRUD(path string) func(s *httpmock.Server) {
+	path = strings.TrimSuffix(path, "/")
+
+	collection := httpmock.RegexPattern(`^` + regexp.QuoteMeta(path) + `/?(\?.*)?$`)
+	document := httpmock.RegexPattern(`^` + regexp.QuoteMeta(path) + `/[^/?]+(\?.*)?$`)
+
+	return func(s *httpmock.Server) {
+		store := &resourceStore{
+			path:      path,
+			documents: make(map[string]map[string]any),
+		}
+
+		s.ExpectBackground(httpmock.MethodPost, collection).RunHandler(http.HandlerFunc(store.create))
+		s.ExpectBackground(httpmock.MethodGet, collection).RunHandler(http.HandlerFunc(store.list))
+		s.ExpectBackground(httpmock.MethodGet, document).RunHandler(http.HandlerFunc(store.get))
+		s.ExpectBackground(httpmock.MethodPut, document).RunHandler(http.HandlerFunc(store.replace))
+		s.ExpectBackground(httpmock.MethodDelete, document).RunHandler(http.HandlerFunc(store.delete))
+	}
+}
+
+// resourceStore stores the JSON documents of a CRUD resource.
+type resourceStore struct {
+	path string
+
+	mu        sync.Mutex
+	documents map[string]map[string]any
+	// ids are the ids of the documents, in the order they were stored.
+	ids    []string
+	lastID int
+}
+
+func (s *resourceStore) create(w http.ResponseWriter, r *http.Request) {
+	doc, ok := decodeDocument(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, ok := documentID(doc)
+	if !ok {
+		id = s.nextID()
+		doc["id"] = json.Number(id)
+	}
+
+	if _, ok := s.documents[id]; ok {
+		http.Error(w, fmt.Sprintf("document %q already exists", id), http.StatusConflict)
+
+		return
+	}
+
+	s.documents[id] = doc
+	s.ids = append(s.ids, id)
+
+	w.Header().Set("Location", s.path+"/"+id)
+
+	writeDocument(w, http.StatusCreated, doc)
+}
+
+func (s *resourceStore) list(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	docs := make([]map[string]any, len(s.ids))
+
+	for i, id := range s.ids {
+		docs[i] = s.documents[id]
+	}
+
+	writeDocument(w, http.StatusOK, docs)
+}
+
+func (s *resourceStore) get(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.documents[s.idOf(r)]
+	if !ok {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	writeDocument(w, http.StatusOK, doc)
+}
+
+func (s *resourceStore) replace(w http.ResponseWriter, r *http.Request) {
+	doc, ok := decodeDocument(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.idOf(r)
+
+	old, ok := s.documents[id]
+	if !ok {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	// The id of the document does not change.
+	doc["id"] = old["id"]
+	s.documents[id] = doc
+
+	writeDocument(w, http.StatusOK, doc)
+}
+
+func (s *resourceStore) delete(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.idOf(r)
+
+	if _, ok := s.documents[id]; !ok {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	delete(s.documents, id)
+
+	for i := range s.ids {
+		if s.ids[i] == id {
+			s.ids = append(s.ids[:i:i], s.ids[i+1:]...)
+
+			break
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// idOf returns the id of the document in the path of the request.
+func (s *resourceStore) idOf(r *http.Request) string {
+	return strings.TrimPrefix(r.URL.Path, s.path+"/")
+}
+
+// nextID generates an id that is not taken by any document. The caller must hold the lock.
+func (s *resourceStore) nextID() string {
+	for {
+		s.lastID++
+
+		id := strconv.Itoa(s.lastID)
+
+		if _, ok := s.documents[id]; !ok {
+			return id
+		}
+	}
+}
+
+// decodeDocument decodes the JSON document in the body of the request, it responds 400 if the body is not a JSON object.
+func decodeDocument(w http.ResponseWriter, r *http.Request) (map[string]any, bool) {
+	var doc map[string]any
+
+	dec := json.NewDecoder(r.Body)
+	dec.UseNumber()
+
+	if err := dec.Decode(&doc); err != nil || doc == nil {
+		http.Error(w, "could not decode document: a JSON object is expected", http.StatusBadRequest)
+
+		return nil, false
+	}
+
+	return doc, true
+}
+
+// documentID returns the id of the document, if it has a string or a number id.
+func documentID(doc map[string]any) (string, bool) {
+	switch id := doc["id"].(type) {
+	case string:
+		return id, id != ""
+
+	case json.Number:
+		return id.String(), true
+	}
+
+	return "", false
+}
+
+func writeDocument(w http.ResponseWriter, code int, v any) {
+	b, _ := json.Marshal(v) // nolint: errcheck // The documents are decoded from JSON.
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	_, _ = w.Write(b) // nolint: errcheck
+}
@@ -0,0 +1,114 @@
+package presets_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/presets"
+)
+
+func TestCRUD(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(presets.CRUD("/items"))(t)
+
+	code, headers, body, _ := httpmock.DoRequest(t, httpmock.MethodPost, srv.URL()+"/items", nil, []byte(`{"name":"foo"}`))
+
+	assert.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, "application/json", headers["Content-Type"])
+	assert.Equal(t, "/items/1", headers["Location"])
+	assert.JSONEq(t, `{"id":1,"name":"foo"}`, string(body))
+
+	code, _, body, _ = httpmock.DoRequest(t, httpmock.MethodPost, srv.URL()+"/items", nil, []byte(`{"id":"bar","name":"bar"}`))
+
+	assert.Equal(t, http.StatusCreated, code)
+	assert.JSONEq(t, `{"id":"bar","name":"bar"}`, string(body))
+
+	code, _, body, _ = httpmock.DoRequest(t, httpmock.MethodGet, srv.URL()+"/items/1", nil, nil)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"id":1,"name":"foo"}`, string(body))
+
+	code, _, body, _ = httpmock.DoRequest(t, httpmock.MethodPut, srv.URL()+"/items/1", nil, []byte(`{"name":"baz"}`))
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"id":1,"name":"baz"}`, string(body))
+
+	code, _, body, _ = httpmock.DoRequest(t, httpmock.MethodGet, srv.URL()+"/items?page=1", nil, nil)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `[{"id":1,"name":"baz"},{"id":"bar","name":"bar"}]`, string(body))
+
+	code, _, _, _ = httpmock.DoRequest(t, httpmock.MethodDelete, srv.URL()+"/items/1", nil, nil)
+
+	assert.Equal(t, http.StatusNoContent, code)
+
+	code, _, _, _ = httpmock.DoRequest(t, httpmock.MethodGet, srv.URL()+"/items/1", nil, nil)
+
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, _, body, _ = httpmock.DoRequest(t, httpmock.MethodGet, srv.URL()+"/items", nil, nil)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `[{"id":"bar","name":"bar"}]`, string(body))
+}
+
+func TestCRUD_Error(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario     string
+		method       string
+		uri          string
+		body         string
+		expectedCode int
+	}{
+		{
+			scenario:     "invalid document",
+			method:       httpmock.MethodPost,
+			uri:          "/items",
+			body:         `[]`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			scenario:     "duplicate id",
+			method:       httpmock.MethodPost,
+			uri:          "/items",
+			body:         `{"id":42}`,
+			expectedCode: http.StatusConflict,
+		},
+		{
+			scenario:     "replace unknown document",
+			method:       httpmock.MethodPut,
+			uri:          "/items/1",
+			body:         `{"name":"foo"}`,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			scenario:     "delete unknown document",
+			method:       httpmock.MethodDelete,
+			uri:          "/items/1",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httpmock.New(presets.CRUD("/items/"))(t)
+
+			code, _, _, _ := httpmock.DoRequest(t, httpmock.MethodPost, srv.URL()+"/items", nil, []byte(`{"id":42}`))
+
+			assert.Equal(t, http.StatusCreated, code)
+
+			code, _, _, _ = httpmock.DoRequest(t, tc.method, srv.URL()+tc.uri, nil, []byte(tc.body))
+
+			assert.Equal(t, tc.expectedCode, code)
+		})
+	}
+}
@@ -0,0 +1,2 @@
+// Package presets provides the common expectations to set up a mocked server.
+package presets
@@ -0,0 +1,26 @@
+package presets
+
+import (
+	"go.nhat.io/httpmock"
+)
+
+// Health answers the health check endpoints, such as the liveness and the readiness probes, with 200 and a JSON
+// status. The endpoints are background expectations, so they can be called any number of times, even none, without
+// interfering with the other expectations.
+//
+//	srv := httpmock.New(
+//		presets.Health("/healthz", "/readyz"),
+//		func(s *httpmock.Server) {
+//			s.ExpectGet("/users/42").
+//				Return(`{"id":42}`)
+//		},
+//	)(t)
+func Health(paths ...string) func(s *httpmock.Server) {
+	return func(s *httpmock.Server) {
+		for _, path := range paths {
+			s.ExpectBackground(httpmock.MethodGet, path).
+				ReturnHeader("Content-Type", "application/json").
+				Return(`{"status":"ok"}`)
+		}
+	}
+}
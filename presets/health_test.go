@@ -0,0 +1,36 @@
+package presets_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/presets"
+)
+
+func TestHealth(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(
+		presets.Health("/healthz", "/readyz"),
+		func(s *httpmock.Server) {
+			s.ExpectGet("/users/42").
+				Return(`{"id":42}`)
+		},
+	)(t)
+
+	for _, uri := range []string{"/readyz", "/healthz", "/readyz"} {
+		code, headers, body, _ := httpmock.DoRequest(t, httpmock.MethodGet, srv.URL()+uri, nil, nil)
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "application/json", headers["Content-Type"])
+		assert.JSONEq(t, `{"status":"ok"}`, string(body))
+	}
+
+	code, _, body, _ := httpmock.DoRequest(t, httpmock.MethodGet, srv.URL()+"/users/42", nil, nil)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `{"id":42}`, string(body))
+}
@@ -0,0 +1,261 @@
+package httpmock
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"go.nhat.io/httpmock/must"
+)
+
+// WithForwardProxy makes the server behave as a forward proxy, so the clients configured with HTTP_PROXY can be tested
+// without changing their urls. The plain HTTP requests have an absolute-form uri, see WithScheme and WithHost. The
+// CONNECT requests open a tunnel and the requests sent through it are matched against the expectations, like any
+// other requests. The tunnels carry plain HTTP unless WithProxyMITM is used.
+//
+//	srv := httpmock.NewServer().
+//		WithForwardProxy()
+//
+//	srv.ExpectGet("/users").
+//		WithHost("api.example.com")
+//
+//	client := srv.ProxyClient()
+func (s *Server) WithForwardProxy() *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.forwardProxy = true
+
+	return s
+}
+
+// WithProxyMITM makes the server behave as a forward proxy, like WithForwardProxy, that intercepts the TLS tunnels. The
+// server presents a certificate of the host of the tunnel, signed by a generated certificate authority, see ProxyCA and
+// WithProxyCA. The requests sent through the tunnels are matched against the expectations, with the https scheme.
+//
+//	srv := httpmock.NewServer().
+//		WithProxyMITM()
+//
+//	srv.ExpectGet("/users").
+//		WithScheme("https").
+//		WithHost("api.example.com")
+//
+//	client := srv.ProxyClient()
+//
+//	resp, err := client.Get("https://api.example.com/users")
+func (s *Server) WithProxyMITM() *Server {
+	ca, err := GenerateCA()
+	must.NotFail(err)
+
+	return s.WithProxyCA(ca)
+}
+
+// WithProxyCA is like WithProxyMITM, with the certificate authority, for example, one that is already installed to the
+// trust store of the client.
+//
+//	ca, err := httpmock.GenerateCA()
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//
+//	srv := httpmock.NewServer().
+//		WithProxyCA(ca)
+func (s *Server) WithProxyCA(ca *CA) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.forwardProxy = true
+	s.proxyCA = ca
+
+	return s
+}
+
+// ProxyCA returns the certificate authority that signs the certificates of the intercepted tunnels, nil if the server
+// does not intercept them. The clients must trust it, see ProxyClient.
+func (s *Server) ProxyCA() *x509.Certificate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.proxyCA == nil {
+		return nil
+	}
+
+	return s.proxyCA.Certificate
+}
+
+// ProxyClient returns a client that sends the requests through the server, as a forward proxy, and trusts the
+// certificate authority of the intercepted tunnels.
+func (s *Server) ProxyClient() *http.Client {
+	proxyURL, err := url.Parse(s.URL())
+	must.NotFail(err)
+
+	transport := &http.Transport{Proxy: http.ProxyURL(proxyURL)}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.proxyCA != nil {
+		transport.TLSClientConfig = &tls.Config{RootCAs: s.proxyCA.CertPool()} // nolint: gosec
+	}
+
+	return &http.Client{Transport: transport}
+}
+
+// isTunnelRequest checks whether the request opens a tunnel through the forward proxy.
+func (s *Server) isTunnelRequest(r *http.Request) bool {
+	if r.Method != http.MethodConnect {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.forwardProxy
+}
+
+// serveTunnel hijacks the connection of the CONNECT request and serves the requests sent through it.
+func (s *Server) serveTunnel(w http.ResponseWriter, r *http.Request) {
+	h, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "could not open tunnel: connection can not be hijacked", http.StatusInternalServerError)
+
+		return
+	}
+
+	conn, rw, err := h.Hijack()
+	if err != nil {
+		http.Error(w, "could not open tunnel: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if _, err := io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n"); err != nil {
+		_ = conn.Close() // nolint: errcheck
+
+		return
+	}
+
+	var tunnel net.Conn = &bufferedConn{Conn: conn, r: rw.Reader}
+
+	s.mu.Lock()
+	ca := s.proxyCA
+	s.mu.Unlock()
+
+	if ca != nil {
+		tunnel = tls.Server(tunnel, &tls.Config{ // nolint: gosec
+			GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+				if hello.ServerName != "" {
+					return ca.ServerCertFor(hello.ServerName)
+				}
+
+				return ca.ServerCertFor(r.Host)
+			},
+		})
+	} else if s.capturesHeaderOrder() {
+		tunnel = &headerOrderConn{Conn: tunnel}
+	}
+
+	s.tunnels.serve(s, tunnel)
+}
+
+// tunnelServer serves the requests sent through the tunnels of the forward proxy.
+type tunnelServer struct {
+	once     sync.Once
+	listener *tunnelListener
+	server   *http.Server
+}
+
+func (t *tunnelServer) serve(h http.Handler, conn net.Conn) {
+	t.once.Do(func() {
+		t.listener = newTunnelListener()
+		t.server = &http.Server{ // nolint: gosec
+			Handler:     h,
+			ConnContext: withHeaderOrderConn,
+			// The errors of the tunnels, for example, a client that does not trust the certificate, are expected.
+			ErrorLog: log.New(io.Discard, "", 0),
+		}
+
+		go t.server.Serve(t.listener) // nolint: errcheck
+	})
+
+	t.listener.push(conn)
+}
+
+func (t *tunnelServer) close() {
+	t.once.Do(func() {})
+
+	if t.server != nil {
+		_ = t.server.Close() // nolint: errcheck
+	}
+}
+
+// tunnelListener accepts the connections of the tunnels.
+type tunnelListener struct {
+	conns  chan net.Conn
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newTunnelListener() *tunnelListener {
+	return &tunnelListener{
+		conns:  make(chan net.Conn),
+		closed: make(chan struct{}),
+	}
+}
+
+func (l *tunnelListener) push(conn net.Conn) {
+	select {
+	case l.conns <- conn:
+	case <-l.closed:
+		_ = conn.Close() // nolint: errcheck
+	}
+}
+
+func (l *tunnelListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *tunnelListener) Close() error {
+	l.once.Do(func() {
+		close(l.closed)
+	})
+
+	return nil
+}
+
+func (l *tunnelListener) Addr() net.Addr {
+	return tunnelAddr{}
+}
+
+// tunnelAddr is the address of the tunnel listener.
+type tunnelAddr struct{}
+
+func (tunnelAddr) Network() string {
+	return "tunnel"
+}
+
+func (tunnelAddr) String() string {
+	return "tunnel"
+}
+
+// bufferedConn reads the bytes buffered while hijacking the connection before reading from the connection.
+type bufferedConn struct {
+	net.Conn
+
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
@@ -0,0 +1,168 @@
+package httpmock_test
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/planner"
+)
+
+func TestServer_WithForwardProxy(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer().
+		WithTest(T()).
+		WithForwardProxy().
+		WithHeaderOrderCapture()
+
+	defer s.Close()
+
+	s.ExpectGet("/users").
+		WithHost("api.example.com").
+		Return("users")
+
+	assert.Nil(t, s.ProxyCA())
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(s.URL(), "http://"))
+	require.NoError(t, err)
+
+	defer conn.Close() //nolint: errcheck
+
+	_, err = fmt.Fprint(conn, "CONNECT api.example.com:80 HTTP/1.1\r\nHost: api.example.com:80\r\n\r\n")
+	require.NoError(t, err)
+
+	br := bufio.NewReader(conn)
+
+	resp, err := http.ReadResponse(br, &http.Request{Method: http.MethodConnect})
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, err = fmt.Fprint(conn, "GET /users HTTP/1.1\r\nHost: api.example.com\r\n\r\n")
+	require.NoError(t, err)
+
+	resp, err = http.ReadResponse(br, nil)
+	require.NoError(t, err)
+
+	defer resp.Body.Close() //nolint: errcheck
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "users", string(body))
+	assert.NoError(t, s.ExpectationsWereMet())
+
+	journal := s.Journal()
+
+	require.Len(t, journal, 1)
+	assert.Equal(t, "/users", journal[0].RequestURI)
+	assert.Equal(t, []string{"Host"}, journal[0].HeaderOrder)
+}
+
+func TestServer_WithProxyMITM(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer().
+		WithTest(T()).
+		WithPlanner(planner.FirstMatch()).
+		WithProxyMITM()
+
+	defer s.Close()
+
+	s.ExpectGet("/users").
+		WithScheme("https").
+		WithHost("api.example.com").
+		Return("users of https")
+
+	s.ExpectGet("/users").
+		WithScheme("http").
+		WithHost("api.example.com").
+		Return("users of http")
+
+	s.ExpectGet("/users").
+		WithHost("127.0.0.1").
+		Return("users of ip")
+
+	client := s.ProxyClient()
+
+	request := func(target string) (int, string) {
+		resp, err := client.Get(target) //nolint: noctx
+		require.NoError(t, err)
+
+		defer resp.Body.Close() //nolint: errcheck
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp.StatusCode, string(body)
+	}
+
+	code, body := request("https://api.example.com/users")
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "users of https", body)
+
+	code, body = request("http://api.example.com/users")
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "users of http", body)
+
+	code, body = request("https://127.0.0.1/users")
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "users of ip", body)
+
+	assert.NoError(t, s.ExpectationsWereMet())
+
+	journal := s.Journal()
+
+	require.Len(t, journal, 3)
+	require.NotNil(t, journal[0].TLS)
+	assert.Equal(t, "api.example.com", journal[0].TLS.ServerName)
+	assert.Nil(t, journal[1].TLS)
+
+	// The certificate authority is not trusted by the other clients.
+	untrusted := &http.Client{Transport: &http.Transport{
+		Proxy: client.Transport.(*http.Transport).Proxy, //nolint: forcetypeassert
+	}}
+
+	_, err := untrusted.Get("https://api.example.com/users") //nolint: noctx
+
+	var unknownAuthority x509.UnknownAuthorityError
+
+	assert.ErrorAs(t, err, &unknownAuthority)
+
+	ca := s.ProxyCA()
+
+	require.NotNil(t, ca)
+	assert.True(t, ca.IsCA)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+
+	trusted := &http.Client{Transport: &http.Transport{
+		Proxy:           client.Transport.(*http.Transport).Proxy, //nolint: forcetypeassert
+		TLSClientConfig: &tls.Config{RootCAs: pool},               //nolint: gosec
+	}}
+
+	s.ExpectGet("/").Return("trusted")
+
+	resp, err := trusted.Get("https://example.com/") //nolint: noctx
+	require.NoError(t, err)
+
+	defer resp.Body.Close() //nolint: errcheck
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
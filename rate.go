@@ -0,0 +1,98 @@
+package httpmock
+
+import (
+	"sort"
+	"time"
+
+	"go.nhat.io/httpmock/test"
+)
+
+// AssertMaxRequestRate asserts that the expectations of the method and the uri were not called more than n times in
+// any window of the duration, to verify that the rate limiter of the client throttles the requests. The uri is the
+// expected uri of the expectations, not the uri of the requests.
+//
+//	srv.ExpectGet("/users").UnlimitedTimes()
+//
+//	// Your requests.
+//
+//	srv.AssertMaxRequestRate(t, httpmock.MethodGet, "/users", 10, time.Second)
+func (s *Server) AssertMaxRequestRate(t test.T, method, uri string, n int, per time.Duration) bool {
+	arrivals := s.arrivals(method, uri)
+
+	for i := range arrivals {
+		j := sort.Search(len(arrivals), func(j int) bool {
+			return arrivals[j].Sub(arrivals[i]) >= per
+		})
+
+		if j-i > n {
+			t.Errorf("expected at most %d requests of %s %s per %s, %d received in %s",
+				n, method, uri, per, j-i, arrivals[j-1].Sub(arrivals[i]),
+			)
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// AssertBackoff asserts that the successive calls of the expectations of the method and the uri, for example, the
+// retries of a failed request, are spaced by at least the gaps, to verify the backoff of the client. The first gap is
+// between the first and the second call, and so on. The uri is the expected uri of the expectations, not the uri of the
+// requests.
+//
+//	srv.ExpectGet("/users").ReturnCode(httpmock.StatusServiceUnavailable).Times(3)
+//	srv.ExpectGet("/users").Return(`[]`)
+//
+//	// Your requests.
+//
+//	srv.AssertBackoff(t, httpmock.MethodGet, "/users", []time.Duration{
+//		100 * time.Millisecond,
+//		200 * time.Millisecond,
+//		400 * time.Millisecond,
+//	})
+func (s *Server) AssertBackoff(t test.T, method, uri string, minGaps []time.Duration) bool {
+	arrivals := s.arrivals(method, uri)
+
+	if len(arrivals) < len(minGaps)+1 {
+		t.Errorf("expected %d requests of %s %s to check the backoff, %d received", len(minGaps)+1, method, uri, len(arrivals))
+
+		return false
+	}
+
+	ok := true
+
+	for i, gap := range minGaps {
+		if actual := arrivals[i+1].Sub(arrivals[i]); actual < gap {
+			t.Errorf("expected request #%d of %s %s at least %s after the previous one, %s received", i+2, method, uri, gap, actual)
+
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// arrivals returns the times when the expectations of the method and the uri were called, in order.
+func (s *Server) arrivals(method, uri string) []time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []time.Time
+
+	for _, e := range s.expectations {
+		if e.Method() != method || e.URIMatcher().Expected() != uri {
+			continue
+		}
+
+		e.lock()
+		result = append(result, e.calledAt...)
+		e.unlock()
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Before(result[j])
+	})
+
+	return result
+}
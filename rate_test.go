@@ -0,0 +1,75 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/planner"
+)
+
+func TestServer_AssertMaxRequestRate(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer().WithPlanner(planner.FirstMatch())
+	defer s.Close()
+
+	s.ExpectGet("/users").Twice()
+	s.ExpectGet("/users").UnlimitedTimes()
+	s.ExpectGet("/slow").UnlimitedTimes()
+
+	for i := 0; i < 3; i++ {
+		doRequest(t, s.URL(), http.MethodGet, "/users", nil, nil, 0)
+	}
+
+	assert.True(t, s.AssertMaxRequestRate(T(), httpmock.MethodGet, "/users", 3, time.Minute))
+	assert.True(t, s.AssertMaxRequestRate(T(), httpmock.MethodPost, "/users", 0, time.Minute))
+
+	testingT := T()
+
+	assert.False(t, s.AssertMaxRequestRate(testingT, httpmock.MethodGet, "/users", 2, time.Minute))
+	assert.Regexp(t, `^expected at most 2 requests of GET /users per 1m0s, 3 received in \S+$`, testingT.String())
+
+	for i := 0; i < 3; i++ {
+		doRequest(t, s.URL(), http.MethodGet, "/slow", nil, nil, 0)
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	assert.True(t, s.AssertMaxRequestRate(T(), httpmock.MethodGet, "/slow", 1, 40*time.Millisecond))
+	assert.False(t, s.AssertMaxRequestRate(T(), httpmock.MethodGet, "/slow", 2, time.Second))
+}
+
+func TestServer_AssertBackoff(t *testing.T) {
+	t.Parallel()
+
+	const gap = 50 * time.Millisecond
+
+	s := httpmock.NewServer()
+	defer s.Close()
+
+	s.ExpectGet("/users").ReturnCode(httpmock.StatusServiceUnavailable).Twice()
+	s.ExpectGet("/users").Return(`[]`)
+
+	doRequest(t, s.URL(), http.MethodGet, "/users", nil, nil, 0)
+	doRequest(t, s.URL(), http.MethodGet, "/users", nil, nil, 0)
+
+	time.Sleep(gap)
+
+	doRequest(t, s.URL(), http.MethodGet, "/users", nil, nil, 0)
+
+	assert.True(t, s.AssertBackoff(T(), httpmock.MethodGet, "/users", []time.Duration{0, gap}))
+
+	testingT := T()
+
+	assert.False(t, s.AssertBackoff(testingT, httpmock.MethodGet, "/users", []time.Duration{gap, gap}))
+	assert.Regexp(t, `^expected request #2 of GET /users at least 50ms after the previous one, \S+ received$`, testingT.String())
+
+	testingT = T()
+
+	assert.False(t, s.AssertBackoff(testingT, httpmock.MethodGet, "/users", []time.Duration{0, gap, gap}))
+	assert.Equal(t, `expected 4 requests of GET /users to check the backoff, 3 received`, testingT.String())
+}
@@ -0,0 +1,69 @@
+package httpmock_test
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/planner"
+)
+
+func TestExpectation_WithRemoteAddr(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer().
+		WithTest(T()).
+		WithPlanner(planner.FirstMatch())
+
+	defer s.Close()
+
+	s.ExpectGet("/").
+		WithRemoteAddr("127.0.0.2").
+		Return("client 2")
+
+	s.ExpectGet("/").
+		WithRemoteAddr(httpmock.RegexPattern(`^127\.0\.0\.[13]$`)).
+		Return("client 1 or 3").
+		Twice()
+
+	request := func(localIP string) (int, string) {
+		client := &http.Client{Transport: &http.Transport{
+			DialContext: (&net.Dialer{LocalAddr: &net.TCPAddr{IP: net.ParseIP(localIP)}}).DialContext,
+		}}
+
+		resp, err := client.Get(s.URL() + "/") //nolint: noctx
+		require.NoError(t, err)
+
+		defer resp.Body.Close() //nolint: errcheck
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp.StatusCode, string(body)
+	}
+
+	code, body := request("127.0.0.3")
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "client 1 or 3", body)
+
+	code, body = request("127.0.0.2")
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "client 2", body)
+
+	code, body = request("127.0.0.2")
+
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Contains(t, body, `remote address "^127\\.0\\.0\\.[13]$" expected, "127.0.0.2" received`)
+
+	journal := s.Journal()
+
+	assert.Regexp(t, `^127\.0\.0\.3:\d+$`, journal[0].RemoteAddr)
+	assert.Regexp(t, `^127\.0\.0\.2:\d+$`, journal[1].RemoteAddr)
+}
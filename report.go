@@ -0,0 +1,80 @@
+package httpmock
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Report is a summary of the requests received by the server and the calls of its expectations, see Server.Report.
+type Report struct {
+	// TotalRequests is the number of requests received by the server.
+	TotalRequests int
+	// MatchedRequests is the number of requests that matched an expectation.
+	MatchedRequests int
+	// UnmatchedRequests is the number of requests that did not match any expectation.
+	UnmatchedRequests int
+	// Expectations are the call statistics of the expectations, in the order they were registered.
+	Expectations []ExpectationStats
+}
+
+// Uncalled returns the expectations that were never called, for example, to find the mocked endpoints that are not
+// covered by the tests.
+func (r Report) Uncalled() []ExpectationStats {
+	var result []ExpectationStats
+
+	for _, e := range r.Expectations {
+		if e.FulfilledTimes == 0 {
+			result = append(result, e)
+		}
+	}
+
+	return result
+}
+
+// String formats the report to be logged.
+func (r Report) String() string {
+	var sb strings.Builder
+
+	_, _ = fmt.Fprintf(&sb, "requests: %d total, %d matched, %d unmatched\n", //nolint: errcheck
+		r.TotalRequests, r.MatchedRequests, r.UnmatchedRequests,
+	)
+
+	for _, e := range r.Expectations {
+		_, _ = fmt.Fprintf(&sb, "- #%d %s %s: %d calls, %d remaining, average latency %s\n", //nolint: errcheck
+			e.ID, e.Method, e.URI, e.FulfilledTimes, e.RemainTimes, e.AverageLatency,
+		)
+	}
+
+	return sb.String()
+}
+
+// Report returns a summary of the requests received by the server and the calls of its expectations, so a test
+// reporter could log it even when the expectations were met, for example, to track the coverage of the mocked
+// endpoints over time.
+//
+//	defer func() {
+//		t.Log(srv.Report())
+//	}()
+func (s *Server) Report() Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := Report{
+		TotalRequests: len(s.journal),
+		Expectations:  make([]ExpectationStats, len(s.expectations)),
+	}
+
+	for _, entry := range s.journal {
+		if entry.Matched {
+			result.MatchedRequests++
+		} else {
+			result.UnmatchedRequests++
+		}
+	}
+
+	for i, e := range s.expectations {
+		result.Expectations[i] = e.stats()
+	}
+
+	return result
+}
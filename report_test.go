@@ -0,0 +1,53 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestServer_Report(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.MockServer(func(s *httpmock.Server) {
+		s.ExpectGet("/users").
+			Twice()
+
+		s.ExpectGet("/never")
+	})
+
+	defer srv.Close()
+
+	doRequest(t, srv.URL(), http.MethodGet, "/users", nil, nil, 0)
+	doRequest(t, srv.URL(), http.MethodGet, "/users", nil, nil, 0)
+	doRequest(t, srv.URL(), http.MethodPost, "/unknown", nil, nil, 0)
+
+	report := srv.Report()
+
+	assert.Equal(t, 3, report.TotalRequests)
+	assert.Equal(t, 2, report.MatchedRequests)
+	assert.Equal(t, 1, report.UnmatchedRequests)
+	assert.Len(t, report.Expectations, 2)
+	assert.Equal(t, uint(2), report.Expectations[0].FulfilledTimes)
+
+	uncalled := report.Uncalled()
+
+	assert.Len(t, uncalled, 1)
+	assert.Equal(t, "/never", uncalled[0].URI)
+
+	assert.Contains(t, report.String(), "requests: 3 total, 2 matched, 1 unmatched\n")
+	assert.Contains(t, report.String(), "- #2 GET /never: 0 calls, 1 remaining, average latency 0s\n")
+}
+
+func TestServer_Report_NoRequest(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.NewServer()
+	defer srv.Close()
+
+	assert.Equal(t, httpmock.Report{Expectations: []httpmock.ExpectationStats{}}, srv.Report())
+	assert.Equal(t, "requests: 0 total, 0 matched, 0 unmatched\n", srv.Report().String())
+}
@@ -0,0 +1,72 @@
+package request
+
+import (
+	"net/http"
+
+	"go.nhat.io/httpmock"
+)
+
+// AsExpectation converts the request to an expectation of the planner-based server, so the code that still builds the
+// requests could be migrated incrementally. The request is read when it is converted, the later changes are not seen by
+// the expectation. The calls of the expectation are counted in the request, see NumCalls.
+//
+//	e := request.AsExpectation(request.NewRequest(&sync.Mutex{}, http.MethodGet, "/path").
+//		Return("hello world!"))
+//
+//	p.Expect(e)
+//
+// Deprecated: the package will be removed in the future, use httpmock.NewExpectation instead.
+func AsExpectation(r *Request) httpmock.PlannedExpectation {
+	e := httpmock.NewExpectation(r.method, r.requestURI)
+
+	configure(e, r)
+
+	return e
+}
+
+// Register registers the request as an expectation of the server, see AsExpectation.
+//
+//	request.Register(srv, request.NewRequest(&sync.Mutex{}, http.MethodGet, "/path").
+//		Return("hello world!"))
+//
+// Deprecated: the package will be removed in the future, use httpmock.Server.Expect instead.
+func Register(s *httpmock.Server, r *Request) httpmock.Expectation {
+	return configure(s.Expect(r.method, r.requestURI), r)
+}
+
+func configure(e httpmock.Expectation, r *Request) httpmock.Expectation {
+	r.lock()
+	defer r.unlock()
+
+	for header, m := range r.requestHeader {
+		e.WithHeader(header, m)
+	}
+
+	if r.requestBody != nil {
+		e.WithBody(r.requestBody.Matcher())
+	}
+
+	if r.waitFor != nil {
+		e.WaitUntil(r.waitFor)
+	} else if r.waitTime > 0 {
+		e.After(r.waitTime)
+	}
+
+	if r.repeatability > 0 {
+		e.Times(uint(r.repeatability))
+	} else {
+		e.UnlimitedTimes()
+	}
+
+	run := r.run
+
+	return e.ReturnCode(r.responseCode).
+		ReturnHeaders(r.responseHeader).
+		Run(func(req *http.Request) ([]byte, error) {
+			r.lock()
+			CountCall(r)
+			r.unlock()
+
+			return run(req)
+		})
+}
@@ -0,0 +1,61 @@
+package request_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/request"
+)
+
+func TestAsExpectation(t *testing.T) {
+	t.Parallel()
+
+	r := request.NewRequest(&sync.Mutex{}, http.MethodPost, "/users").
+		WithHeader("Authorization", "Bearer token").
+		WithBody(`{"name":"john"}`).
+		ReturnCode(http.StatusCreated).
+		ReturnHeader("Content-Type", "application/json").
+		Return(`{"id":42}`).
+		Twice()
+
+	e := request.AsExpectation(r)
+
+	assert.Equal(t, http.MethodPost, e.Method())
+	assert.Equal(t, uint(2), e.RemainTimes())
+
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"john"}`))
+	w := httptest.NewRecorder()
+
+	require.NoError(t, e.Handle(w, req, nil))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"id":42}`, w.Body.String())
+	assert.Equal(t, 1, request.NumCalls(r))
+}
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	r := request.NewRequest(&sync.Mutex{}, http.MethodGet, httpmock.RegexPattern(`^/users/\d+$`)).
+		WithHeader("Authorization", "Bearer token").
+		Return(`{"id":42}`).
+		Once()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		request.Register(s, r)
+	})(t)
+
+	code, _, body, _ := httpmock.DoRequest(t, http.MethodGet, srv.URL()+"/users/42", map[string]string{"Authorization": "Bearer token"}, nil)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `{"id":42}`, string(body))
+	assert.Equal(t, 1, request.NumCalls(r))
+}
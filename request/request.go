@@ -89,7 +89,7 @@ func (r *Request) WithHeader(header string, value any) *Request {
 		r.requestHeader = matcher.HeaderMatcher{}
 	}
 
-	r.requestHeader[header] = matcher.Match(value)
+	r.requestHeader[http.CanonicalHeaderKey(header)] = matcher.Match(value)
 
 	return r
 }
@@ -0,0 +1,53 @@
+package httpmock
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.nhat.io/httpmock/matcher"
+)
+
+// errRequestMismatched indicates that the request does not match a RequestMatcherFunc.
+var errRequestMismatched = errors.New("request does not match")
+
+// RequestMatcherFunc matches the whole request, see MatchRequest.
+type RequestMatcherFunc func(r *http.Request) (bool, error)
+
+// MatchRequest creates a matcher that receives the whole request, so an ad-hoc matching does not need to implement the
+// matcher interfaces. It can be used as the uri of an expectation, to match any uri, or as the body.
+//
+//	Server.Expect(httpmock.MethodPost, httpmock.MatchRequest(func(r *http.Request) (bool, error) {
+//		return strings.HasPrefix(r.URL.Path, "/users/") && r.URL.Query().Get("page") != "", nil
+//	}))
+//
+//	Server.Expect(httpmock.MethodPost, "/users").
+//		WithBody(httpmock.MatchRequest(func(r *http.Request) (bool, error) {
+//			return r.ContentLength < 1024, nil
+//		}))
+func MatchRequest(match func(r *http.Request) (bool, error)) RequestMatcherFunc {
+	return match
+}
+
+func (f RequestMatcherFunc) matchRequest(r *http.Request) error {
+	matched, err := f(r)
+	if err != nil {
+		return fmt.Errorf("could not match request: %w", err)
+	}
+
+	if !matched {
+		return errRequestMismatched
+	}
+
+	return nil
+}
+
+// requestMatcherDescription describes a RequestMatcherFunc, which is opaque.
+const requestMatcherDescription = "<request matcher>"
+
+// matchAnyURI matches any uri, the request is matched by a RequestMatcherFunc instead.
+func matchAnyURI() matcher.Matcher {
+	return matcher.Fn(requestMatcherDescription, func(any) (bool, error) {
+		return true, nil
+	})
+}
@@ -0,0 +1,119 @@
+package httpmock_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestMatchRequest(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		mockServer    func(s *httpmock.Server)
+		uri           string
+		body          []byte
+		expectedCode  int
+		expectedError string
+	}{
+		{
+			scenario: "uri is matched",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet(httpmock.MatchRequest(func(r *http.Request) (bool, error) {
+					return strings.HasPrefix(r.URL.Path, "/users/") && r.URL.Query().Get("page") != "", nil
+				})).
+					ReturnCode(http.StatusNoContent)
+			},
+			uri:          "/users/42?page=2",
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			scenario: "uri is not matched",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectGet(httpmock.MatchRequest(func(r *http.Request) (bool, error) {
+					return r.URL.Query().Get("page") != "", nil
+				}))
+			},
+			uri:          "/users/42",
+			expectedCode: http.StatusInternalServerError,
+			expectedError: `Expected: GET <request matcher>
+Actual: GET /users/42
+    with header:
+        Accept-Encoding: gzip
+        User-Agent: Go-http-client/1.1
+Error: request does not match
+`,
+		},
+		{
+			scenario: "body is matched",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectPost("/users").
+					WithBody(httpmock.MatchRequest(func(r *http.Request) (bool, error) {
+						return r.ContentLength < 10, nil
+					})).
+					ReturnCode(http.StatusCreated)
+			},
+			uri:          "/users",
+			body:         []byte(`{"id":42}`),
+			expectedCode: http.StatusCreated,
+		},
+		{
+			scenario: "body is not matched",
+			mockServer: func(s *httpmock.Server) {
+				s.ExpectPost("/users").
+					WithBody(httpmock.MatchRequest(func(*http.Request) (bool, error) {
+						return false, errors.New("body is too large")
+					}))
+			},
+			uri:          "/users",
+			body:         []byte(`{"id":42,"name":"John Doe"}`),
+			expectedCode: http.StatusInternalServerError,
+			expectedError: `Expected: POST /users
+Actual: POST /users
+    with header:
+        Accept-Encoding: gzip
+        Content-Length: 27
+        User-Agent: Go-http-client/1.1
+    with body
+        {"id":42,"name":"John Doe"}
+Error: could not match request: body is too large
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			testingT := T()
+
+			s := httpmock.NewServer().WithTest(testingT)
+
+			defer s.Close()
+
+			tc.mockServer(s)
+
+			method := http.MethodGet
+			if tc.body != nil {
+				method = http.MethodPost
+			}
+
+			code, _, _, _ := doRequest(t, s.URL(), method, tc.uri, nil, tc.body, 0)
+
+			assert.Equal(t, tc.expectedCode, code)
+
+			if tc.expectedError == "" {
+				assert.Empty(t, testingT.String())
+			} else {
+				assert.Contains(t, testingT.String(), tc.expectedError)
+			}
+		})
+	}
+}
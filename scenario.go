@@ -0,0 +1,131 @@
+package httpmock
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// ScenarioStarted is the initial state of a scenario.
+const ScenarioStarted = "Started"
+
+// Scenario is a state machine shared by the expectations, to emulate a stateful upstream, for example, a checkout that
+// is pending until it is paid. The expectations in the scenario are matched only in their state and move the scenario
+// to the next state when they are matched, see Expectation.InScenario.
+type Scenario struct {
+	name string
+
+	mu    sync.Mutex
+	state string
+}
+
+// Name returns the name of the scenario.
+func (s *Scenario) Name() string {
+	return s.name
+}
+
+// State returns the current state of the scenario.
+func (s *Scenario) State() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.state
+}
+
+// SetState moves the scenario to the state, for example, to start a test in the middle of a flow.
+func (s *Scenario) SetState(state string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state = state
+}
+
+// Reset moves the scenario back to ScenarioStarted, for example, to restart the flow in another subtest.
+func (s *Scenario) Reset() {
+	s.SetState(ScenarioStarted)
+}
+
+// Scenario returns the scenario of the name, it is created in ScenarioStarted if it does not exist.
+//
+//	srv.ExpectGet("/checkout").
+//		InScenario("checkout").
+//		WhenScenarioStateIs(httpmock.ScenarioStarted).
+//		WillSetStateTo("paid")
+//
+//	// Your requests.
+//
+//	assert.Equal(t, "paid", srv.Scenario("checkout").State())
+func (s *Server) Scenario(name string) *Scenario {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sc, ok := s.scenarios[name]; ok {
+		return sc
+	}
+
+	if s.scenarios == nil {
+		s.scenarios = make(map[string]*Scenario)
+	}
+
+	sc := &Scenario{name: name, state: ScenarioStarted}
+
+	s.scenarios[name] = sc
+
+	return sc
+}
+
+// InScenario adds the expectation to the scenario, see WhenScenarioStateIs and WillSetStateTo.
+//
+//	Server.Expect(httpmock.MethodGet, "/checkout").
+//		InScenario("checkout").
+//		WhenScenarioStateIs("paid").
+//		Return(`{"status":"paid"}`)
+func (e *requestExpectation) InScenario(name string) Expectation {
+	e.lock()
+	scenarioOf := e.scenarioOf
+	e.unlock()
+
+	if scenarioOf == nil {
+		panic(errors.New("could not use scenario: expectation is not created by a server")) // nolint: goerr113
+	}
+
+	sc := scenarioOf(name)
+
+	e.lock()
+	e.scenario = sc
+	e.unlock()
+
+	// The scenario is described by its fields, because its expected state could be set later.
+	return e.withRequestMatcher("", func(*http.Request) error {
+		e.lock()
+		expected := e.scenarioState
+		e.unlock()
+
+		if actual := sc.State(); expected != "" && actual != expected {
+			return fmt.Errorf("scenario %q in state %q expected, %q received", sc.Name(), expected, actual) // nolint: goerr113
+		}
+
+		return nil
+	})
+}
+
+// WhenScenarioStateIs expects the scenario of the expectation to be in the state, see InScenario.
+func (e *requestExpectation) WhenScenarioStateIs(state string) Expectation {
+	e.lock()
+	defer e.unlock()
+
+	e.scenarioState = state
+
+	return e
+}
+
+// WillSetStateTo moves the scenario of the expectation to the state when the expectation is matched, see InScenario.
+func (e *requestExpectation) WillSetStateTo(state string) Expectation {
+	e.lock()
+	defer e.unlock()
+
+	e.scenarioNextState = state
+
+	return e
+}
@@ -0,0 +1,93 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/planner"
+)
+
+func TestServer_Scenario(t *testing.T) {
+	t.Parallel()
+
+	testingT := T()
+
+	s := httpmock.NewServer().
+		WithTest(testingT).
+		WithPlanner(planner.FirstMatch())
+
+	defer s.Close()
+
+	s.ExpectGet("/checkout").
+		InScenario("checkout").
+		WhenScenarioStateIs(httpmock.ScenarioStarted).
+		Return(`{"status":"pending"}`).
+		UnlimitedTimes()
+
+	s.ExpectPost("/checkout/pay").
+		InScenario("checkout").
+		WhenScenarioStateIs(httpmock.ScenarioStarted).
+		WillSetStateTo("paid").
+		UnlimitedTimes()
+
+	s.ExpectGet("/checkout").
+		InScenario("checkout").
+		WhenScenarioStateIs("paid").
+		Return(`{"status":"paid"}`).
+		UnlimitedTimes()
+
+	checkout := s.Scenario("checkout")
+
+	assert.Same(t, checkout, s.Scenario("checkout"))
+	assert.Equal(t, "checkout", checkout.Name())
+	assert.Equal(t, httpmock.ScenarioStarted, checkout.State())
+
+	flow := func(t *testing.T) {
+		t.Helper()
+
+		_, _, body, _ := doRequest(t, s.URL(), http.MethodGet, "/checkout", nil, nil, 0)
+
+		assert.Equal(t, `{"status":"pending"}`, string(body))
+
+		code, _, _, _ := doRequest(t, s.URL(), http.MethodPost, "/checkout/pay", nil, nil, 0)
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "paid", checkout.State())
+
+		_, _, body, _ = doRequest(t, s.URL(), http.MethodGet, "/checkout", nil, nil, 0)
+
+		assert.Equal(t, `{"status":"paid"}`, string(body))
+	}
+
+	flow(t)
+
+	// The payment can not be made twice.
+	code, _, _, _ := doRequest(t, s.URL(), http.MethodPost, "/checkout/pay", nil, nil, 0)
+
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Contains(t, testingT.String(), `Error: scenario "checkout" in state "Started" expected, "paid" received`)
+
+	checkout.Reset()
+
+	assert.Equal(t, httpmock.ScenarioStarted, checkout.State())
+
+	flow(t)
+
+	checkout.SetState("paid")
+
+	_, _, body, _ := doRequest(t, s.URL(), http.MethodGet, "/checkout", nil, nil, 0)
+
+	assert.Equal(t, `{"status":"paid"}`, string(body))
+}
+
+func TestExpectation_InScenario_Panic(t *testing.T) {
+	t.Parallel()
+
+	assert.PanicsWithError(t, "could not use scenario: expectation is not created by a server", func() {
+		httpmock.NewExpectation(httpmock.MethodGet, "/").
+			InScenario("checkout")
+	})
+}
@@ -0,0 +1,143 @@
+package httpmock
+
+import (
+	"sync"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock/planner"
+	"go.nhat.io/httpmock/test"
+)
+
+// Scope is a set of expectations on a shared server. At the end of the test, the expectations of the scope are verified
+// and removed from the server, so one server can be shared across many parallel tests.
+type Scope struct {
+	server *Server
+	test   test.T
+
+	expectations []*requestExpectation
+
+	mu sync.Mutex
+}
+
+// Scope creates a new scope of expectations for the test. The expectations of the scope are verified and removed from
+// the server when the test finishes. Use a planner that does not depend on the order of the requests, such as
+// planner.FirstMatch, when the tests run in parallel.
+//
+//	srv := httpmock.NewServer().WithPlanner(planner.FirstMatch())
+//
+//	t.Run("get user", func(t *testing.T) {
+//		t.Parallel()
+//
+//		srv.Scope(t).ExpectGet("/users/42").
+//			Return(`{"id":42}`)
+//
+//		// Your request and assertions.
+//	})
+func (s *Server) Scope(t test.T) *Scope {
+	sc := &Scope{
+		server: s,
+		test:   t,
+	}
+
+	t.Cleanup(sc.close)
+
+	return sc
+}
+
+// Expect adds a new expected request to the scope.
+//
+//	Scope.Expect(httpmock.MethodGet, "/path").
+func (sc *Scope) Expect(method string, requestURI any) Expectation {
+	expect := sc.server.newExpectation(method, requestURI)
+
+	sc.server.mu.Lock()
+	sc.server.registerExpectation(expect)
+	sc.server.mu.Unlock()
+
+	sc.mu.Lock()
+	sc.expectations = append(sc.expectations, expect)
+	sc.mu.Unlock()
+
+	return expect
+}
+
+// ExpectGet adds a new expected http.MethodGet request to the scope.
+//
+//	Scope.ExpectGet("/path")
+func (sc *Scope) ExpectGet(requestURI any) Expectation {
+	return sc.Expect(MethodGet, requestURI)
+}
+
+// ExpectHead adds a new expected http.MethodHead request to the scope.
+//
+//	Scope.ExpectHead("/path")
+func (sc *Scope) ExpectHead(requestURI any) Expectation {
+	return sc.Expect(MethodHead, requestURI)
+}
+
+// ExpectPost adds a new expected http.MethodPost request to the scope.
+//
+//	Scope.ExpectPost("/path")
+func (sc *Scope) ExpectPost(requestURI any) Expectation {
+	return sc.Expect(MethodPost, requestURI)
+}
+
+// ExpectPut adds a new expected http.MethodPut request to the scope.
+//
+//	Scope.ExpectPut("/path")
+func (sc *Scope) ExpectPut(requestURI any) Expectation {
+	return sc.Expect(MethodPut, requestURI)
+}
+
+// ExpectPatch adds a new expected http.MethodPatch request to the scope.
+//
+//	Scope.ExpectPatch("/path")
+func (sc *Scope) ExpectPatch(requestURI any) Expectation {
+	return sc.Expect(MethodPatch, requestURI)
+}
+
+// ExpectDelete adds a new expected http.MethodDelete request to the scope.
+//
+//	Scope.ExpectDelete("/path")
+func (sc *Scope) ExpectDelete(requestURI any) Expectation {
+	return sc.Expect(MethodDelete, requestURI)
+}
+
+// ExpectationsWereMet checks whether all the expectations of the scope were met.
+func (sc *Scope) ExpectationsWereMet() error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	expectations := make([]planner.Expectation, len(sc.expectations))
+
+	for i, e := range sc.expectations {
+		expectations[i] = e
+	}
+
+	return expectationsWereMet(expectations)
+}
+
+func (sc *Scope) close() {
+	assert.NoError(sc.test, sc.ExpectationsWereMet())
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	owned := make(map[*requestExpectation]struct{}, len(sc.expectations))
+
+	for _, e := range sc.expectations {
+		owned[e] = struct{}{}
+	}
+
+	sc.server.mu.Lock()
+	defer sc.server.mu.Unlock()
+
+	sc.server.removeExpectations(func(e *requestExpectation) bool {
+		_, ok := owned[e]
+
+		return ok
+	})
+
+	sc.expectations = nil
+}
@@ -0,0 +1,76 @@
+package httpmock_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/planner"
+)
+
+func TestServer_Scope(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer().WithPlanner(planner.FirstMatch())
+
+	defer s.Close()
+
+	t.Run("group", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			id := i
+
+			t.Run(fmt.Sprintf("user %d", id), func(t *testing.T) {
+				t.Parallel()
+
+				uri := fmt.Sprintf("/users/%d", id)
+
+				s.Scope(t).ExpectGet(uri).
+					Returnf(`{"id":%d}`, id)
+
+				code, _, body, _ := doRequest(t, s.URL(), http.MethodGet, uri, nil, nil, 0)
+
+				assert.Equal(t, http.StatusOK, code)
+				assert.Equal(t, fmt.Sprintf(`{"id":%d}`, id), string(body))
+			})
+		}
+	})
+
+	// All the scoped expectations are removed.
+	assert.Empty(t, s.Stats())
+	assert.NoError(t, s.ExpectationsWereMet())
+}
+
+func TestServer_Scope_ExpectationsWereNotMet(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer().WithPlanner(planner.FirstMatch())
+
+	defer s.Close()
+
+	s.ExpectGet("/shared").UnlimitedTimes()
+
+	testingT := T()
+	sc := s.Scope(testingT)
+
+	sc.ExpectPost("/users")
+	sc.ExpectDelete("/users/42").Return("deleted")
+
+	code, _, body, _ := doRequest(t, s.URL(), http.MethodPost, "/users", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, body)
+
+	expectedError := `there are remaining expectations that were not met:
+- DELETE /users/42
+`
+
+	assert.EqualError(t, sc.ExpectationsWereMet(), expectedError)
+
+	testingT.clean()
+
+	assert.Contains(t, testingT.String(), "- DELETE /users/42")
+	assert.Len(t, s.Stats(), 1)
+}
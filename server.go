@@ -1,12 +1,18 @@
 package httpmock
 
 import (
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	mathrand "math/rand"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -16,9 +22,14 @@ import (
 	"go.nhat.io/httpmock/value"
 )
 
+var _ http.Handler = (*Server)(nil)
+
 // Server is a Mock server.
 type Server struct {
 	// Requests are the matched expectations.
+	//
+	// Deprecated: It is not safe to read while the server is handling requests, use Server.MatchedExpectations()
+	// instead.
 	Requests []planner.Expectation
 
 	// Test server.
@@ -34,20 +45,153 @@ type Server struct {
 	defaultRequestOptions []func(e Expectation)
 	// defaultResponseHeader contains a list of default headers that will be sent to client.
 	defaultResponseHeader map[string]string
+	// headerMerge is how the default response headers are merged with the headers of the expectations.
+	headerMerge HeaderMergePolicy
+	// echoHeaders contains a list of request headers that will be copied to the response.
+	echoHeaders []string
+	// dateSkew offsets the Date header of the responses from the real time.
+	dateSkew time.Duration
+	// autoHead indicates whether the HEAD requests are answered by the GET expectations.
+	autoHead bool
+	// methodNotAllowed indicates whether the server responds 405 when only the method of the request mismatches.
+	methodNotAllowed bool
+	// noExtraInteractions indicates whether the requests after all the expectations were met fail the test.
+	noExtraInteractions bool
+	// extraInteractions contains the requests received after all the expectations were met.
+	extraInteractions []string
+	// noBodyCapture indicates whether the server does not read the request bodies for the journal.
+	noBodyCapture bool
+	// expectationLocation indicates whether the locations of the expectations are recorded, see
+	// WithExpectationLocation.
+	expectationLocation bool
+	// uncountCancelled indicates whether the requests cancelled by the clients are not counted as the calls of their
+	// expectations, see WithoutCountingCancelledRequests.
+	uncountCancelled bool
+	// allMismatches indicates whether the mismatch errors report all the divergences instead of the first one.
+	allMismatches bool
+	// defaultDelay and defaultJitter delay the responses of the expectations that do not have their own delay.
+	defaultDelay  time.Duration
+	defaultJitter time.Duration
+	// latencyProfile delays the responses like defaultDelay, by a random duration from the distribution.
+	latencyProfile *latencyProfile
+	// random is the random source of the server, for example, to generate the jitter. The random sources of the
+	// expectations are seeded from it.
+	random   *mathrand.Rand
+	randSeed int64
+	// specDelayScale scales the response delays of the expectation specs.
+	specDelayScale float64
+	// tlsFault is the fault of the TLS server, 0 if there is no fault.
+	tlsFault     TLSFault
+	tlsFaultOnce sync.Once
+	tlsFaultCert tls.Certificate
+	tlsFaultErr  error
+	// requestDumpDir is the directory to dump the requests and the responses to, empty if they are not dumped.
+	requestDumpDir string
+	requestDumpSeq int
+	// failureLogs buffer the interactions of the tests that log them when they fail, see LogOnFailure.
+	failureLogs []*failureLog
+	// duplicateCheck is how the duplicate expectations are reported, 0 if they are not checked.
+	duplicateCheck DuplicateCheck
+	// duplicateCheckedID is the id of the last expectation that was checked for duplicates.
+	duplicateCheckedID int
+
+	// expectations contains all the expectations registered to the server.
+	expectations []*requestExpectation
+	lastID       int
+	// checkpoints are the states of the server at the checkpoints taken by Snapshot.
+	checkpoints map[*planner.Checkpoint]serverCheckpoint
+	// background contains the expectations that are matched before the planner, see ExpectBackground.
+	background []*requestExpectation
+	// ignored contains the requests that are answered with 204 without being recorded, see Ignore.
+	ignored []*requestExpectation
+	// journal contains all the requests received by the server.
+	journal []JournalEntry
+	// admin indicates whether the admin endpoints are enabled.
+	admin bool
+	// metrics collects the metrics of the server, nil if the metrics endpoint is disabled.
+	metrics *metrics
+	// inFlight contains the time ranges when the requests were in flight.
+	inFlight []inFlightRange
+	// conns counts the connections to the server.
+	conns connTracker
+	// headerOrderCapture is 1 if the header order of the requests is captured, see WithHeaderOrderCapture. It is read
+	// without the lock when a connection is accepted.
+	headerOrderCapture int32
+	// forwardProxy indicates whether the server behaves as a forward proxy, see WithForwardProxy.
+	forwardProxy bool
+	// proxyCA signs the certificates of the intercepted tunnels, nil if the tunnels are not intercepted.
+	proxyCA *CA
+	// tunnels serves the requests sent through the tunnels of the forward proxy.
+	tunnels tunnelServer
+	// scenarios contains the scenarios of the expectations, see Scenario.
+	scenarios map[string]*Scenario
+	// state is the key-value store shared by the expectations, see State.
+	state State
+	// startedAt is when the server was started, see Expectation.ExpectWithin.
+	startedAt time.Time
 }
 
 // NewServer creates a new server.
 func NewServer() *Server {
+	s := NewUnstartedServer()
+
+	s.Start()
+
+	return s
+}
+
+// NewUnstartedServer creates a new server but does not start it. The caller should call Start when finished setting up.
+func NewUnstartedServer() *Server {
+	seed := time.Now().UnixNano()
+
 	s := Server{
-		test:    test.NoOpT(),
-		planner: planner.Sequence(),
+		test:           test.NoOpT(),
+		planner:        planner.Sequence(),
+		random:         mathrand.New(mathrand.NewSource(seed)), // nolint: gosec
+		randSeed:       seed,
+		specDelayScale: 1,
 	}
 
-	s.server = httptest.NewServer(&s)
+	s.server = httptest.NewUnstartedServer(&s)
+	s.server.Config.ConnState = s.conns.track
 
 	return &s
 }
 
+// Start starts the server.
+func (s *Server) Start() {
+	s.captureHeaderOrder()
+	s.server.Start()
+	s.markStarted()
+}
+
+// captureHeaderOrder captures the raw header order of the requests of the connections that are accepted after the
+// capture is enabled, see RequestHeaderOrder.
+func (s *Server) captureHeaderOrder() {
+	if s.server.Listener != nil {
+		s.server.Listener = &headerOrderListener{Listener: s.server.Listener, capture: s.capturesHeaderOrder}
+	}
+
+	s.server.Config.ConnContext = withHeaderOrderConn
+}
+
+// WithListener sets the listener of the server, for example, to serve on a specific address. It must be called before
+// the server is started.
+func (s *Server) WithListener(l net.Listener) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.server.URL != "" {
+		panic(errors.New("could not change listener: server is already started")) // nolint: goerr113
+	}
+
+	_ = s.server.Listener.Close() //nolint: errcheck
+
+	s.server.Listener = l
+
+	return s
+}
+
 // WithPlanner sets the planner.
 func (s *Server) WithPlanner(p planner.Planner) *Server {
 	s.mu.Lock()
@@ -82,6 +226,27 @@ func (s *Server) WithDefaultRequestOptions(opt func(e Expectation)) *Server {
 	return s
 }
 
+// WithDefaultResponseCode sets the response code of all the new expectations, the expectations can still override it
+// with ReturnCode. It must be called before registering the expectations.
+//
+//	Server.WithDefaultResponseCode(httpmock.StatusNoContent)
+func (s *Server) WithDefaultResponseCode(code int) *Server {
+	return s.WithDefaultRequestOptions(func(e Expectation) {
+		e.ReturnCode(code)
+	})
+}
+
+// WithDefaultRepeatability sets how many times all the new expectations are expected, 0 means unlimited times, the
+// expectations can still override it with Once, Twice, Times or UnlimitedTimes. It must be called before registering
+// the expectations.
+//
+//	Server.WithDefaultRepeatability(0) // Every expectation is expected unlimited times.
+func (s *Server) WithDefaultRepeatability(times uint) *Server {
+	return s.WithDefaultRequestOptions(func(e Expectation) {
+		e.Times(times)
+	})
+}
+
 // WithDefaultResponseHeaders sets the default response headers of the server.
 func (s *Server) WithDefaultResponseHeaders(headers map[string]string) *Server {
 	s.mu.Lock()
@@ -92,6 +257,95 @@ func (s *Server) WithDefaultResponseHeaders(headers map[string]string) *Server {
 	return s
 }
 
+// WithRandSource seeds the random source of the server, which is used by all the random features, such as the weighted
+// responses and the jitter, so a failure can be reproduced exactly with the seed, see RandSeed. It must be called
+// before registering the expectations. The expectations that have their own seed, set by Expectation.WithRandSeed, are
+// not affected.
+//
+//	Server.WithRandSource(42)
+func (s *Server) WithRandSource(seed int64) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.random = mathrand.New(mathrand.NewSource(seed)) // nolint: gosec
+	s.randSeed = seed
+
+	return s
+}
+
+// RandSeed returns the seed of the random source of the server, so it can be logged to reproduce a failure with
+// WithRandSource.
+//
+//	t.Logf("httpmock seed: %d", srv.RandSeed())
+func (s *Server) RandSeed() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.randSeed
+}
+
+// WithEchoHeader copies the header from every request to its response, for example, a correlation id. If the request
+// does not have the header, a random value is generated.
+//
+//	Server.WithEchoHeader("X-Request-ID")
+func (s *Server) WithEchoHeader(header string) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.echoHeaders = append(s.echoHeaders, header)
+
+	return s
+}
+
+// WithDateSkew offsets the Date header of the responses from the real time, to test the clients that validate the time
+// of the server, for example, the expiry window of a signature. A negative duration sets the date in the past.
+//
+//	Server.WithDateSkew(-10 * time.Minute)
+func (s *Server) WithDateSkew(d time.Duration) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.dateSkew = d
+
+	return s
+}
+
+// WithNoExtraInteractions fails the test explicitly when a request is received after all the expectations were met,
+// to catch the clients that issue duplicate or spurious calls. The extra requests are also reported by
+// ExpectationsWereMet.
+func (s *Server) WithNoExtraInteractions() *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.noExtraInteractions = true
+
+	return s
+}
+
+// WithoutBodyCapture stops the server from reading the request bodies for the journal and the error messages. The body
+// is still read when an expectation has a body matcher. It reduces the memory usage when there are many or large
+// requests.
+func (s *Server) WithoutBodyCapture() *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.noBodyCapture = true
+
+	return s
+}
+
+// WithAllMismatches reports all the divergences of a mismatched request in one error, the method, the uri, the header,
+// the body and the request matchers, instead of stopping at the first one. It is useful when fixing the mismatches one
+// at a time is slow, for example, on CI.
+func (s *Server) WithAllMismatches() *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.allMismatches = true
+
+	return s
+}
+
 // URL returns the current URL of the httptest.Server.
 func (s *Server) URL() string {
 	return s.server.URL
@@ -100,26 +354,82 @@ func (s *Server) URL() string {
 // Close closes mocked server.
 func (s *Server) Close() {
 	s.server.Close()
+	s.tunnels.close()
 }
 
 // Expect adds a new expected request.
 //
 //	Server.Expect(httpmock.MethodGet, "/path").
 func (s *Server) Expect(method string, requestURI any) Expectation {
+	expect := s.newExpectation(method, requestURI)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.registerExpectation(expect)
+
+	return expect
+}
+
+// Override adds a new expected request that replaces the remaining expectations of the same method and uri, at the
+// position of the first one, instead of queueing behind them. It is useful when a test helper sets the default
+// expectations and a test needs to replace some of them.
+//
+//	Server.Override(httpmock.MethodGet, "/path").
+func (s *Server) Override(method string, requestURI any) Expectation {
+	expect := s.newExpectation(method, requestURI)
+	uri := expect.URIMatcher().Expected()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.replaceExpectations(func(e *requestExpectation) bool {
+		return e.Method() == method && e.URIMatcher().Expected() == uri
+	}, expect)
+
+	return expect
+}
+
+func (s *Server) newExpectation(method string, requestURI any) *requestExpectation {
 	expect := newRequestExpectation(method, requestURI)
 
 	expect.Once()
 
+	// The random source of the expectation is seeded from the server, so the random results are reproducible.
+	s.mu.Lock()
+	expect.random = mathrand.New(mathrand.NewSource(s.random.Int63())) // nolint: gosec
+	expect.scenarioOf = s.Scenario
+	expect.headerMergeOf = s.headerMergePolicy
+	expect.headerOrderCaptureOf = s.enableHeaderOrderCapture
+
+	if s.expectationLocation {
+		expect.location = callerLocation()
+	}
+	s.mu.Unlock()
+
 	for _, o := range s.defaultRequestOptions {
 		o(expect)
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	return expect
+}
+
+// registerExpectation registers the expectation to the server and the planner. The caller must hold the lock.
+func (s *Server) registerExpectation(expect *requestExpectation) {
+	s.lastID++
+	expect.id = s.lastID
+	expect.register = s.registerClone
 
+	s.expectations = append(s.expectations, expect)
 	s.planner.Expect(expect)
+}
 
-	return expect
+// registerClone registers a clone of an expectation, see Expectation.Clone.
+func (s *Server) registerClone(expect *requestExpectation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.registerExpectation(expect)
 }
 
 // ExpectGet adds a new expected http.MethodGet request.
@@ -164,16 +474,152 @@ func (s *Server) ExpectDelete(requestURI any) Expectation {
 	return s.Expect(MethodDelete, requestURI)
 }
 
+// Expectations returns all the expectations, in the order they were registered. The expectations could be printed, or
+// marshaled to JSON, to debug a failing test.
+//
+//	for _, e := range srv.Expectations() {
+//		t.Log(e)
+//	}
+func (s *Server) Expectations() []Expectation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]Expectation, len(s.expectations))
+
+	for i, e := range s.expectations {
+		result[i] = e
+	}
+
+	return result
+}
+
+// MatchedExpectations returns the expectations that matched the requests, in the order the requests were received.
+// It is safe to call while the server is handling requests.
+func (s *Server) MatchedExpectations() []planner.Expectation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]planner.Expectation, len(s.Requests))
+
+	copy(result, s.Requests)
+
+	return result
+}
+
 // ExpectationsWereMet checks whether all queued expectations were met in order.
 // If any of them was not met - an error is returned.
 func (s *Server) ExpectationsWereMet() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.checkDuplicates()
+
+	if err := s.checkDeadlines(); err != nil {
+		return err
+	}
+
+	if len(s.extraInteractions) > 0 {
+		var sb strings.Builder
+
+		sb.WriteString("there are extra requests after all expectations were met:\n")
+
+		for _, extra := range s.extraInteractions {
+			sb.WriteString("- ")
+			sb.WriteString(extra)
+			sb.WriteString("\n")
+		}
+
+		// nolint:goerr113
+		return errors.New(sb.String())
+	}
+
 	if s.planner.IsEmpty() {
 		return nil
 	}
 
+	return expectationsWereMet(s.planner.Remain())
+}
+
+// ValidateExpectations checks whether all the expectations can be reached, before any request is sent. For example,
+// in the sequence planner, the expectations registered after an unlimited expectation can never be reached. Only the
+// planners that implement planner.Validator are checked.
+func (s *Server) ValidateExpectations() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return planner.Validate(s.planner)
+}
+
+// Snapshot captures the state of the planner, the queue of expectations and their call counts, so it can be restored
+// later with Restore, for example, to roll back to a checkpoint between the phases of a long test. The planner must
+// implement planner.Snapshotter, like all the planners in the planner package.
+//
+//	cp, err := srv.Snapshot()
+//
+//	// More expectations and requests.
+//
+//	err = srv.Restore(cp)
+func (s *Server) Snapshot() (*planner.Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cp, err := planner.Snapshot(s.planner)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.checkpoints == nil {
+		s.checkpoints = make(map[*planner.Checkpoint]serverCheckpoint)
+	}
+
+	s.checkpoints[cp] = serverCheckpoint{lastID: s.lastID, requests: len(s.Requests)}
+
+	return cp, nil
+}
+
+// serverCheckpoint is the state of the server at a checkpoint, see Snapshot.
+type serverCheckpoint struct {
+	// lastID is the id of the last expectation registered before the checkpoint.
+	lastID int
+	// requests is the number of the matched expectations before the checkpoint.
+	requests int
+}
+
+// Restore restores the state of the planner to the checkpoint taken by Snapshot. The expectations added after the
+// checkpoint are removed from the server, and the ones matched after the checkpoint could be matched again, their calls
+// after the checkpoint are forgotten.
+func (s *Server) Restore(cp *planner.Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := planner.Restore(s.planner, cp); err != nil {
+		return err
+	}
+
+	sc, ok := s.checkpoints[cp]
+	if !ok {
+		return nil
+	}
+
+	expectations := make([]*requestExpectation, 0, len(s.expectations))
+
+	for _, e := range s.expectations {
+		if e.id <= sc.lastID {
+			expectations = append(expectations, e)
+		}
+	}
+
+	s.expectations = expectations
+
+	if len(s.Requests) > sc.requests {
+		s.Requests = s.Requests[:sc.requests]
+	}
+
+	return nil
+}
+
+// expectationsWereMet returns an error that lists the expectations that were not met.
+func expectationsWereMet(expectations []planner.Expectation) error {
 	var (
 		sb    strings.Builder
 		count int
@@ -181,7 +627,7 @@ func (s *Server) ExpectationsWereMet() error {
 
 	sb.WriteString("there are remaining expectations that were not met:\n")
 
-	for _, expected := range s.planner.Remain() {
+	for _, expected := range expectations {
 		repeat := expected.RemainTimes()
 		calls := expected.FulfilledTimes()
 
@@ -199,6 +645,12 @@ func (s *Server) ExpectationsWereMet() error {
 			int(repeat), //nolint: gosec
 		)
 
+		if l, ok := expected.(planner.Locator); ok && l.Location() != "" {
+			sb.WriteString("    declared at ")
+			sb.WriteString(l.Location())
+			sb.WriteString("\n")
+		}
+
 		count++
 	}
 
@@ -212,40 +664,220 @@ func (s *Server) ExpectationsWereMet() error {
 
 // ServeHTTP serves the request.
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	r = withState(withHeaderOrder(r), &s.state)
+
+	if s.isTunnelRequest(r) {
+		s.serveTunnel(w, r)
+
+		return
+	}
+
+	if s.isAdminRequest(r) {
+		s.serveAdmin(w, r)
+
+		return
+	}
+
+	if s.isMetricsRequest(r) {
+		s.serveMetrics(w)
+
+		return
+	}
+
+	if s.isIgnoredRequest(r) {
+		w.WriteHeader(http.StatusNoContent)
+
+		return
+	}
+
+	dump := s.newDumpResponseWriter(w)
+	if dump != nil {
+		w = dump
+	}
+
+	entry, h, defaultHeaders, t := s.planRequest(w, r)
+
+	// The lock is released while handling the request, so the handlers can register new expectations.
+	if h != nil {
+		err := s.delay(r, h)
+		if err == nil {
+			err = handleSafely(h, w, r, defaultHeaders)
+		}
+
+		if isClientCancelled(r, err) {
+			entry.ClientCancelled = true
+
+			s.forgetCancelled(h)
+		} else {
+			require.NoError(t, err)
+		}
+	}
+
+	// The body is not used anymore, its buffer can be reused.
+	defer value.ReleaseBody(r)
+
+	if dump != nil {
+		s.dump(r, dump)
+	}
+
+	end := time.Now()
+
+	if e, ok := asRequestExpectation(h); ok {
+		e.recordInFlight(start, end)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.inFlight = append(s.inFlight, inFlightRange{start: start, end: end})
+
+	if !s.noBodyCapture {
+		if body, err := value.PeekBody(r); err == nil {
+			entry.Body = string(body)
+		}
+	}
+
+	s.journal = append(s.journal, entry)
+
+	if s.metrics != nil {
+		s.metrics.observe(entry.Matched, time.Since(entry.Time))
+	}
+}
+
+// planRequest finds the expectation for the request. If there is no expectation, it writes the failure response and
+// returns a nil handler.
+func (s *Server) planRequest(w http.ResponseWriter, r *http.Request) (JournalEntry, ExpectationHandler, map[string]string, test.T) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	for _, header := range s.echoHeaders {
+		w.Header().Set(header, echoHeaderValue(r, header))
+	}
+
+	if s.dateSkew != 0 {
+		w.Header().Set("Date", time.Now().Add(s.dateSkew).UTC().Format(http.TimeFormat))
+	}
+
+	// The body is only read when it is needed, by a body matcher, a handler, or the journal.
+	if !s.noBodyCapture {
+		value.CaptureBody(r)
+	}
+
+	entry := newJournalEntry(r)
+
+	s.checkDuplicates()
+
+	if expected := s.findBackgroundHandler(r); expected != nil {
+		entry.Matched = true
+
+		expected.Fulfilled()
+
+		return entry, expected, s.defaultResponseHeader, s.test
+	}
+
+	if msg, ok := s.checkExtraInteraction(r); ok {
+		entry.Error = msg
+
+		s.failResponsef(w, entry.Error) //nolint: govet
+
+		return entry, nil, nil, s.test
+	}
+
 	if s.planner.IsEmpty() {
-		body, err := value.GetBody(r)
-		if err == nil && len(body) > 0 {
-			s.failResponsef(w, "unexpected request received: %s %s, body:\n%s", r.Method, r.RequestURI, string(body))
-		} else {
-			s.failResponsef(w, "unexpected request received: %s %s", r.Method, r.RequestURI)
+		entry.Error = fmt.Sprintf("unexpected request received: %s %s", r.Method, r.RequestURI)
+
+		if !s.noBodyCapture {
+			if body, err := value.PeekBody(r); err == nil && len(body) > 0 {
+				entry.Error += fmt.Sprintf(", body:\n%s", string(body))
+			}
 		}
 
-		return
+		s.failResponsef(w, entry.Error) //nolint: govet
+
+		return entry, nil, nil, s.test
 	}
 
 	expected, err := s.planner.Plan(r)
 	if err != nil {
+		if h := s.findHeadHandler(r); h != nil {
+			entry.Matched = true
+
+			return entry, h, s.defaultResponseHeader, s.test
+		}
+
+		if allowed := s.allowedMethods(r); len(allowed) > 0 {
+			entry.Error = writeMethodNotAllowed(w, r, allowed)
+
+			return entry, nil, nil, s.test
+		}
+
+		var mismatch *planner.Error
+
+		if s.allMismatches && errors.As(err, &mismatch) {
+			if all := planner.MatchRequestAll(mismatch.Expected(), r); all != nil {
+				err = all
+			}
+		}
+
+		entry.Error = err.Error()
+
 		s.failResponsef(w, err.Error()) //nolint: govet
 
-		return
+		return entry, nil, nil, s.test
 	}
 
+	entry.Matched = true
+
 	// Log the request.
 	expected.Fulfilled()
 
 	s.Requests = append(s.Requests, expected)
 
 	if h, ok := expected.(ExpectationHandler); ok {
-		err = h.Handle(w, r, s.defaultResponseHeader)
-		require.NoError(s.test, err)
-
-		return
+		return entry, h, s.defaultResponseHeader, s.test
 	}
 
 	s.failResponsef(w, "could not handle request: %s %s", r.Method, r.RequestURI)
+
+	return entry, nil, nil, s.test
+}
+
+// checkExtraInteraction checks whether the request is received after all the expectations were met. The caller must
+// hold the lock.
+func (s *Server) checkExtraInteraction(r *http.Request) (string, bool) {
+	if !s.noExtraInteractions || len(s.Requests) == 0 || expectationsWereMet(s.planner.Remain()) != nil {
+		return "", false
+	}
+
+	// The request still matches an unlimited expectation.
+	for _, expected := range s.planner.Remain() {
+		if planner.MatchRequest(expected, r) == nil {
+			return "", false
+		}
+	}
+
+	if s.findHeadHandler(r) != nil {
+		return "", false
+	}
+
+	request := fmt.Sprintf("%s %s", r.Method, r.RequestURI)
+
+	s.extraInteractions = append(s.extraInteractions, request)
+
+	return fmt.Sprintf("extra request received after all expectations were met: %s", request), true
+}
+
+func echoHeaderValue(r *http.Request, header string) string {
+	if v := r.Header.Get(header); v != "" {
+		return v
+	}
+
+	id := make([]byte, 16)
+
+	_, _ = rand.Read(id) //nolint: errcheck
+
+	return hex.EncodeToString(id)
 }
 
 func (s *Server) failResponsef(w http.ResponseWriter, format string, args ...any) {
@@ -263,6 +895,55 @@ func (s *Server) ResetExpectations() {
 	defer s.mu.Unlock()
 
 	s.Requests = nil
+	s.expectations = nil
+	s.background = nil
+	s.extraInteractions = nil
+	s.duplicateCheckedID = s.lastID
 
 	s.planner.Reset()
 }
+
+// removeExpectations removes the expectations that satisfy the given condition from the server and the planner. The
+// caller must hold the lock.
+func (s *Server) removeExpectations(remove func(e *requestExpectation) bool) {
+	s.replaceExpectations(remove, nil)
+}
+
+// replaceExpectations removes the expectations that satisfy the given condition from the server and the planner, and
+// registers the new expectation, if it is not nil, at the position of the first removed one. The caller must hold the
+// lock.
+func (s *Server) replaceExpectations(remove func(e *requestExpectation) bool, expect *requestExpectation) {
+	var replacement planner.Expectation
+
+	if expect != nil {
+		s.lastID++
+		expect.id = s.lastID
+		expect.register = s.registerClone
+
+		replacement = expect
+	}
+
+	expectations := make([]*requestExpectation, 0, len(s.expectations)+1)
+	replaced := expect == nil
+
+	for _, e := range s.expectations {
+		if !remove(e) {
+			expectations = append(expectations, e)
+		} else if !replaced {
+			expectations = append(expectations, expect)
+			replaced = true
+		}
+	}
+
+	if !replaced {
+		expectations = append(expectations, expect)
+	}
+
+	s.expectations = expectations
+
+	planner.Replace(s.planner, func(e planner.Expectation) bool {
+		re, ok := asRequestExpectation(e)
+
+		return ok && remove(re)
+	}, replacement)
+}
@@ -1,16 +1,24 @@
 package httpmock_test
 
 import (
+	"bufio"
+	"context"
 	"errors"
+	"io"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"go.nhat.io/httpmock"
-	"go.nhat.io/httpmock/mock/planner"
+	plannermock "go.nhat.io/httpmock/mock/planner"
+	"go.nhat.io/httpmock/planner"
+	"go.nhat.io/httpmock/value"
 )
 
 type (
@@ -241,6 +249,61 @@ Error: header "Content-Type" with value "application/json" expected, "text/plain
 	assert.Equal(t, expectedBody, body)
 }
 
+func TestServer_WithDefaultResponseCode(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.MockServer(func(s *httpmock.Server) {
+		s.WithDefaultResponseCode(httpmock.StatusNoContent)
+
+		s.ExpectPost("/users")
+
+		s.ExpectGet("/users").
+			ReturnCode(httpmock.StatusOK).
+			Return(`[]`)
+	})
+
+	defer s.Close()
+
+	code, _, _, _ := doRequest(t, s.URL(), http.MethodPost, "/users", nil, nil, 0)
+
+	assert.Equal(t, httpmock.StatusNoContent, code)
+
+	code, _, body, _ := doRequest(t, s.URL(), http.MethodGet, "/users", nil, nil, 0)
+
+	assert.Equal(t, httpmock.StatusOK, code)
+	assert.Equal(t, `[]`, string(body))
+}
+
+func TestServer_WithDefaultRepeatability(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.MockServer(func(s *httpmock.Server) {
+		s.WithPlanner(planner.FirstMatch()).
+			WithDefaultRepeatability(0)
+
+		s.ExpectGet("/users")
+
+		s.ExpectGet("/user/42").
+			Once()
+	})
+
+	defer s.Close()
+
+	for i := 0; i < 3; i++ {
+		code, _, _, _ := doRequest(t, s.URL(), http.MethodGet, "/users", nil, nil, 0)
+
+		assert.Equal(t, httpmock.StatusOK, code)
+	}
+
+	code, _, _, _ := doRequest(t, s.URL(), http.MethodGet, "/user/42", nil, nil, 0)
+
+	assert.Equal(t, httpmock.StatusOK, code)
+
+	code, _, _, _ = doRequest(t, s.URL(), http.MethodGet, "/user/42", nil, nil, 0)
+
+	assert.Equal(t, httpmock.StatusInternalServerError, code)
+}
+
 func TestServer_WithDefaultResponseHeaders(t *testing.T) {
 	t.Parallel()
 
@@ -297,12 +360,141 @@ func TestServer_WithDefaultResponseHeaders(t *testing.T) {
 	assert.NoError(t, s.ExpectationsWereMet())
 }
 
+func TestServer_WithHeaderMergePolicy(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		policy   httpmock.HeaderMergePolicy
+		expected []string
+	}{
+		{
+			scenario: "expectation wins",
+			policy:   httpmock.HeaderMergeExpectationWins,
+			expected: []string{"SAMEORIGIN"},
+		},
+		{
+			scenario: "server wins",
+			policy:   httpmock.HeaderMergeServerWins,
+			expected: []string{"DENY"},
+		},
+		{
+			scenario: "append",
+			policy:   httpmock.HeaderMergeAppend,
+			expected: []string{"DENY", "SAMEORIGIN"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			s := httpmock.NewServer().
+				WithDefaultResponseHeaders(httpmock.Header{"X-Frame-Options": "DENY"}).
+				WithHeaderMergePolicy(tc.policy)
+
+			defer s.Close()
+
+			s.ExpectGet("/").
+				ReturnHeader("x-frame-options", "SAMEORIGIN")
+
+			resp, err := http.Get(s.URL() + "/") //nolint: noctx
+			require.NoError(t, err)
+
+			defer resp.Body.Close() //nolint: errcheck
+
+			assert.Equal(t, tc.expected, resp.Header.Values("X-Frame-Options"))
+		})
+	}
+}
+
+func TestServer_WithoutDefaultHeaders(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer().
+		WithDefaultResponseHeaders(httpmock.Header{"X-Frame-Options": "DENY"})
+
+	defer s.Close()
+
+	s.ExpectGet("/legacy").
+		WithoutDefaultHeaders().
+		ReturnHeader("Content-Type", "text/plain")
+
+	s.ExpectGet("/")
+
+	_, headers, _, _ := doRequest(t, s.URL(), http.MethodGet, "/legacy", nil, nil, 0)
+
+	assert.Equal(t, "text/plain", headers["Content-Type"])
+	assert.NotContains(t, headers, "X-Frame-Options")
+
+	_, headers, _, _ = doRequest(t, s.URL(), http.MethodGet, "/", nil, nil, 0)
+
+	assert.Equal(t, "DENY", headers["X-Frame-Options"])
+}
+
+func TestServer_WithAllMismatches(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.MockServer(func(s *httpmock.Server) {
+		s.WithAllMismatches()
+
+		s.ExpectPost("/users").
+			WithHeader("Content-Type", "application/json").
+			WithBody(`{"name":"john"}`)
+	})
+
+	defer s.Close()
+
+	code, _, body, _ := doRequest(t, s.URL(), http.MethodPost, "/users", nil, []byte(`{"name":"jane"}`), 0)
+
+	expected := `Error: 2 mismatches:
+    - header "Content-Type" with value "application/json" expected, "" received
+    - expected request body: {"name":"john"}, received: {"name":"jane"}
+`
+
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Contains(t, string(body), expected)
+}
+
+func TestServer_WithEchoHeader(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.MockServer(func(s *httpmock.Server) {
+		s.WithEchoHeader("X-Request-ID")
+
+		s.ExpectGet("/").
+			Return(`hello world!`).
+			Twice()
+	})
+
+	defer s.Close()
+
+	// The header is copied.
+	code, headers, _, _ := doRequest(t, s.URL(), http.MethodGet, "/", Header{"X-Request-ID": "42"}, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "42", headers["X-Request-Id"])
+
+	// The header is generated.
+	code, headers, _, _ = doRequest(t, s.URL(), http.MethodGet, "/", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Regexp(t, `^[0-9a-f]{32}$`, headers["X-Request-Id"])
+
+	// The header is also sent with the failure response.
+	code, headers, _, _ = doRequest(t, s.URL(), http.MethodGet, "/", Header{"X-Request-ID": "43"}, nil, 0)
+
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Equal(t, "43", headers["X-Request-Id"])
+}
+
 func TestServer_WithPlanner(t *testing.T) {
 	t.Parallel()
 
 	testingT := T()
 
-	p := planner.Mock(func(p *planner.Planner) {
+	p := plannermock.Mock(func(p *plannermock.Planner) {
 		p.On("Expect", mock.Anything)
 
 		p.On("IsEmpty").Return(false)
@@ -339,7 +531,7 @@ func TestServer_WithPlanner_Panic(t *testing.T) {
 		s.ExpectGet("/").
 			Return(`hello world!`)
 
-		s.WithPlanner(planner.NoMockPlanner(t))
+		s.WithPlanner(plannermock.NoMockPlanner(t))
 	})
 }
 
@@ -422,6 +614,34 @@ func TestServer_Wait(t *testing.T) {
 			},
 			expectedDelay: expectedDelay,
 		},
+		{
+			scenario: "wait until context",
+			mockServer: func(s *Server) {
+				ctx, release := context.WithCancel(context.Background())
+
+				time.AfterFunc(waitTime, release)
+
+				s.ExpectGet("/").
+					WaitUntilContext(ctx)
+			},
+			expectedDelay: expectedDelay,
+		},
+		{
+			scenario: "wait for",
+			mockServer: func(s *Server) {
+				var ready int32
+
+				time.AfterFunc(waitTime, func() {
+					atomic.StoreInt32(&ready, 1)
+				})
+
+				s.ExpectGet("/").
+					WaitFor(func() bool {
+						return atomic.LoadInt32(&ready) == 1
+					}, time.Millisecond)
+			},
+			expectedDelay: expectedDelay,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -447,140 +667,944 @@ func TestServer_Wait(t *testing.T) {
 	}
 }
 
-func TestServer_ExpectationsWereNotMet(t *testing.T) {
+func TestServer_ExpectInHandler(t *testing.T) {
 	t.Parallel()
 
-	testingT := T()
+	s := httpmock.NewServer()
 
-	s := httpmock.MockServer(func(s *httpmock.Server) {
-		s.WithTest(testingT)
+	defer s.Close()
 
-		s.ExpectGet("/").Times(3)
-		s.ExpectGet("/unlimited").UnlimitedTimes()
-		s.ExpectGet("/path")
-	})
+	s.ExpectPost("/users").
+		ReturnCode(httpmock.StatusCreated).
+		Run(func(*http.Request) ([]byte, error) {
+			s.ExpectGet("/users/42").
+				Return(`{"id":42}`)
+
+			return []byte(`{"id":42}`), nil
+		})
+
+	code, _, body, _ := doRequest(t, s.URL(), http.MethodPost, "/users", nil, nil, 0)
+
+	assert.Equal(t, httpmock.StatusCreated, code)
+	assert.Equal(t, `{"id":42}`, string(body))
+
+	code, _, body, _ = doRequest(t, s.URL(), http.MethodGet, "/users/42", nil, nil, 0)
+
+	assert.Equal(t, httpmock.StatusOK, code)
+	assert.Equal(t, `{"id":42}`, string(body))
+
+	assert.NoError(t, s.ExpectationsWereMet())
+}
+
+func TestServer_PerSession(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer().
+		WithPlanner(planner.PerSession(planner.SessionByHeader("X-Session-ID"), planner.Sequence))
 
 	defer s.Close()
 
-	request := func(uri string) int {
-		code, _, _, _ := doRequest(t, s.URL(), http.MethodGet, uri, nil, nil, 0)
+	s.ExpectGet("/status").Return("pending")
+	s.ExpectGet("/status").Return("done")
 
-		return code
+	for _, session := range []string{"alice", "bob"} {
+		for _, expected := range []string{"pending", "done"} {
+			code, _, body, _ := doRequest(t, s.URL(), http.MethodGet, "/status", Header{"X-Session-ID": session}, nil, 0)
+
+			assert.Equal(t, httpmock.StatusOK, code)
+			assert.Equal(t, expected, string(body))
+		}
 	}
 
-	expectedCode := http.StatusOK
+	assert.NoError(t, s.ExpectationsWereMet())
+}
 
-	// 1st request is ok.
-	assert.Equal(t, expectedCode, request("/"))
+func TestServer_Override(t *testing.T) {
+	t.Parallel()
 
-	expectedErr := `there are remaining expectations that were not met:
-- GET / (called: 1 time(s), remaining: 2 time(s))
-- GET /unlimited
-- GET /path
-`
-	assert.EqualError(t, s.ExpectationsWereMet(), expectedErr)
+	s := httpmock.MockServer(func(s *httpmock.Server) {
+		// Defaults set by a helper.
+		s.ExpectGet("/users").Return("default users").UnlimitedTimes()
+		s.ExpectGet("/items").Return("default items")
+	})
 
-	// 2nd request is ok.
-	assert.Equal(t, expectedCode, request("/"))
+	defer s.Close()
 
-	expectedErr = `there are remaining expectations that were not met:
-- GET / (called: 2 time(s), remaining: 1 time(s))
-- GET /unlimited
-- GET /path
-`
-	assert.EqualError(t, s.ExpectationsWereMet(), expectedErr)
+	s.Override(httpmock.MethodGet, "/users").Return("overridden users")
+
+	// The overridden expectation keeps its position in the sequence.
+	code, _, body, _ := doRequest(t, s.URL(), http.MethodGet, "/users", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "overridden users", string(body))
+
+	code, _, body, _ = doRequest(t, s.URL(), http.MethodGet, "/items", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "default items", string(body))
+
+	assert.NoError(t, s.ExpectationsWereMet())
 }
 
-func TestServer_ExpectationsWereNotMet_UnlimitedRequest(t *testing.T) {
+func TestServer_Override_PerSession(t *testing.T) {
 	t.Parallel()
 
-	testingT := T()
-
 	s := httpmock.MockServer(func(s *httpmock.Server) {
-		s.WithTest(testingT)
+		s.WithPlanner(planner.PerSession(planner.SessionByHeader("X-Session-ID"), planner.Sequence))
 
-		s.ExpectGet("/").UnlimitedTimes()
-		s.ExpectGet("/path")
+		s.ExpectGet("/login").Return("default login")
+		s.ExpectGet("/users").Return("default users")
 	})
 
 	defer s.Close()
 
-	request := func(uri string) int {
-		code, _, _, _ := doRequest(t, s.URL(), http.MethodGet, uri, nil, nil, 0)
+	header := Header{"X-Session-ID": "alice"}
 
-		return code
-	}
+	code, _, body, _ := doRequest(t, s.URL(), http.MethodGet, "/login", header, nil, 0)
 
-	expectedCode := http.StatusOK
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "default login", string(body))
 
-	// 1st request is ok.
-	assert.Equal(t, expectedCode, request("/"))
+	s.Override(httpmock.MethodGet, "/users").Return("overridden users")
 
-	expectedErr := `there are remaining expectations that were not met:
-- GET /path
-`
-	assert.EqualError(t, s.ExpectationsWereMet(), expectedErr)
+	// The session keeps its progress.
+	code, _, body, _ = doRequest(t, s.URL(), http.MethodGet, "/users", header, nil, 0)
 
-	// 2nd request is ok.
-	assert.Equal(t, expectedCode, request("/"))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "overridden users", string(body))
 
-	expectedErr = `there are remaining expectations that were not met:
-- GET /path
-`
-	assert.EqualError(t, s.ExpectationsWereMet(), expectedErr)
+	assert.Len(t, s.Stats(), 2)
 }
 
-func TestServer_ExpectationsWereMet_UnlimitedRequest(t *testing.T) {
+func TestServer_WithNoExtraInteractions(t *testing.T) {
 	t.Parallel()
 
 	testingT := T()
 
 	s := httpmock.MockServer(func(s *httpmock.Server) {
-		s.WithTest(testingT)
+		s.WithTest(testingT).
+			WithNoExtraInteractions()
 
-		s.ExpectGet("/").UnlimitedTimes()
+		s.ExpectGet("/").Return("hello world!")
+		s.ExpectGet("/unlimited").UnlimitedTimes()
 	})
 
 	defer s.Close()
 
-	request := func(uri string) int {
-		code, _, _, _ := doRequest(t, s.URL(), http.MethodGet, uri, nil, nil, 0)
+	// The request before all the expectations were met is not an extra one.
+	code, _, _, _ := doRequest(t, s.URL(), http.MethodGet, "/unknown", nil, nil, 0)
 
-		return code
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.NotContains(t, testingT.String(), "extra request")
+
+	testingT.Reset()
+
+	for _, uri := range []string{"/", "/unlimited", "/unlimited"} {
+		code, _, _, _ = doRequest(t, s.URL(), http.MethodGet, uri, nil, nil, 0)
+
+		assert.Equal(t, http.StatusOK, code)
 	}
 
-	expectedCode := http.StatusOK
+	assert.NoError(t, s.ExpectationsWereMet())
+
+	code, _, _, _ = doRequest(t, s.URL(), http.MethodGet, "/", nil, nil, 0)
+
+	expectedError := `there are extra requests after all expectations were met:
+- GET /
+`
+
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Equal(t, "extra request received after all expectations were met: GET /", testingT.String())
+	assert.EqualError(t, s.ExpectationsWereMet(), expectedError)
+
+	s.ResetExpectations()
 
-	assert.Equal(t, expectedCode, request("/"))
 	assert.NoError(t, s.ExpectationsWereMet())
 }
 
-func TestServer_ResetExpectations(t *testing.T) {
+func TestServer_MatchedExpectations(t *testing.T) {
 	t.Parallel()
 
-	s := httpmock.MockServer(func(s *httpmock.Server) {
-		s.ExpectGet("/").Times(3)
-	})
+	s := httpmock.NewServer().WithPlanner(planner.FirstMatch())
 
 	defer s.Close()
 
+	users := s.ExpectGet("/users").UnlimitedTimes()
+	items := s.ExpectGet("/items")
+
+	assert.Empty(t, s.MatchedExpectations())
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			doRequest(t, s.URL(), http.MethodGet, "/users", nil, nil, 0)
+
+			_ = s.MatchedExpectations()
+		}()
+	}
+
+	wg.Wait()
+
+	doRequest(t, s.URL(), http.MethodGet, "/items", nil, nil, 0)
+
+	matched := s.MatchedExpectations()
+
+	assert.Len(t, matched, 11)
+	assert.Equal(t, users, matched[0])
+	assert.Equal(t, items, matched[10])
+
+	// The result is a copy.
+	matched[0] = nil
+
+	assert.Equal(t, users, s.MatchedExpectations()[0])
+
 	s.ResetExpectations()
 
-	assert.NoError(t, s.ExpectationsWereMet())
+	assert.Empty(t, s.MatchedExpectations())
 }
 
-// nolint:thelper // It is called in DoRequestWithTimeout.
-func doRequest(
-	t *testing.T,
-	baseURL string,
-	method, uri string,
-	headers Header,
-	body []byte,
-	waitTime time.Duration,
-) (int, map[string]string, []byte, time.Duration) {
-	return httpmock.DoRequestWithTimeout(t,
-		method, baseURL+uri,
-		headers, body,
-		waitTime+time.Second,
-	)
+func TestServer_ExpectInHandler_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer().WithPlanner(planner.FirstMatch())
+
+	defer s.Close()
+
+	var calls int32
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	s.ExpectPost("/users").
+		ReturnCode(httpmock.StatusCreated).
+		Run(func(*http.Request) ([]byte, error) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				close(started)
+				<-release
+
+				s.ExpectGet("/users/42").
+					Return(`{"id":42}`)
+			}
+
+			return []byte(`{"id":42}`), nil
+		}).
+		UnlimitedTimes()
+
+	done := make(chan int, 1)
+
+	go func() {
+		code, _, _, _ := doRequest(t, s.URL(), http.MethodPost, "/users", nil, nil, time.Second)
+
+		done <- code
+	}()
+
+	<-started
+
+	// The second request is handled while the first one is still in the handler.
+	code, _, _, _ := doRequest(t, s.URL(), http.MethodPost, "/users", nil, nil, 0)
+
+	close(release)
+
+	assert.Equal(t, httpmock.StatusCreated, code)
+	assert.Equal(t, httpmock.StatusCreated, <-done)
+
+	code, _, body, _ := doRequest(t, s.URL(), http.MethodGet, "/users/42", nil, nil, 0)
+
+	assert.Equal(t, httpmock.StatusOK, code)
+	assert.Equal(t, `{"id":42}`, string(body))
+
+	assert.NoError(t, s.ExpectationsWereMet())
+}
+
+func TestServer_SlowExpectationDoesNotBlockOthers(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+
+	defer s.Close()
+
+	s.ExpectGet("/slow").
+		After(500 * time.Millisecond).
+		UnlimitedTimes()
+
+	s.ExpectGet("/fast").
+		UnlimitedTimes()
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		doRequest(t, s.URL(), http.MethodGet, "/slow", nil, nil, time.Second)
+	}()
+
+	// Wait for the slow request to be in flight.
+	assert.Eventually(t, func() bool {
+		return s.Stats()[0].FulfilledTimes == 1
+	}, time.Second, 5*time.Millisecond)
+
+	_, _, _, elapsed := doRequest(t, s.URL(), http.MethodGet, "/fast", nil, nil, 0)
+
+	assert.Less(t, elapsed, 250*time.Millisecond)
+
+	<-done
+}
+
+func TestServer_HandlerKeepsBody(t *testing.T) {
+	t.Parallel()
+
+	var bodies [][]byte
+
+	s := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectPost("/users").
+			Run(func(r *http.Request) ([]byte, error) {
+				body, err := value.GetBody(r)
+
+				bodies = append(bodies, body)
+
+				return nil, err
+			}).
+			Times(2)
+	})(t)
+
+	doRequest(t, s.URL(), http.MethodPost, "/users", nil, []byte("first-body"), 0)
+	doRequest(t, s.URL(), http.MethodPost, "/users", nil, []byte("SECOND"), 0)
+
+	assert.Equal(t, [][]byte{[]byte("first-body"), []byte("SECOND")}, bodies)
+}
+
+func TestServer_TimedOutHandlerReadsBody(t *testing.T) {
+	t.Parallel()
+
+	bodies := make(chan string, 1)
+
+	s := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectPost("/slow").
+			WithTimeout(20 * time.Millisecond).
+			Run(func(r *http.Request) ([]byte, error) {
+				<-r.Context().Done()
+
+				// The request has been released by the server.
+				time.Sleep(50 * time.Millisecond)
+
+				body, err := value.GetBody(r)
+
+				bodies <- string(body)
+
+				return nil, err
+			})
+
+		s.ExpectPost("/fast")
+	})(t)
+
+	code, _, _, _ := doRequest(t, s.URL(), http.MethodPost, "/slow", nil, []byte("first-body"), 0)
+
+	assert.Equal(t, http.StatusGatewayTimeout, code)
+
+	// Reuse the buffer of the released body.
+	doRequest(t, s.URL(), http.MethodPost, "/fast", nil, []byte("SECOND"), 0)
+
+	assert.Equal(t, "first-body", <-bodies)
+}
+
+func TestServer_ExpectationsWereNotMet(t *testing.T) {
+	t.Parallel()
+
+	testingT := T()
+
+	s := httpmock.MockServer(func(s *httpmock.Server) {
+		s.WithTest(testingT)
+
+		s.ExpectGet("/").Times(3)
+		s.ExpectGet("/unlimited").UnlimitedTimes()
+		s.ExpectGet("/path")
+	})
+
+	defer s.Close()
+
+	request := func(uri string) int {
+		code, _, _, _ := doRequest(t, s.URL(), http.MethodGet, uri, nil, nil, 0)
+
+		return code
+	}
+
+	expectedCode := http.StatusOK
+
+	// 1st request is ok.
+	assert.Equal(t, expectedCode, request("/"))
+
+	expectedErr := `there are remaining expectations that were not met:
+- GET / (called: 1 time(s), remaining: 2 time(s))
+- GET /unlimited
+- GET /path
+`
+	assert.EqualError(t, s.ExpectationsWereMet(), expectedErr)
+
+	// 2nd request is ok.
+	assert.Equal(t, expectedCode, request("/"))
+
+	expectedErr = `there are remaining expectations that were not met:
+- GET / (called: 2 time(s), remaining: 1 time(s))
+- GET /unlimited
+- GET /path
+`
+	assert.EqualError(t, s.ExpectationsWereMet(), expectedErr)
+}
+
+func TestServer_ExpectationsWereNotMet_UnlimitedRequest(t *testing.T) {
+	t.Parallel()
+
+	testingT := T()
+
+	s := httpmock.MockServer(func(s *httpmock.Server) {
+		s.WithTest(testingT)
+
+		s.ExpectGet("/").UnlimitedTimes()
+		s.ExpectGet("/path")
+	})
+
+	defer s.Close()
+
+	request := func(uri string) int {
+		code, _, _, _ := doRequest(t, s.URL(), http.MethodGet, uri, nil, nil, 0)
+
+		return code
+	}
+
+	expectedCode := http.StatusOK
+
+	// 1st request is ok.
+	assert.Equal(t, expectedCode, request("/"))
+
+	expectedErr := `there are remaining expectations that were not met:
+- GET /path
+`
+	assert.EqualError(t, s.ExpectationsWereMet(), expectedErr)
+
+	// 2nd request is ok.
+	assert.Equal(t, expectedCode, request("/"))
+
+	expectedErr = `there are remaining expectations that were not met:
+- GET /path
+`
+	assert.EqualError(t, s.ExpectationsWereMet(), expectedErr)
+}
+
+func TestServer_ExpectationsWereMet_UnlimitedRequest(t *testing.T) {
+	t.Parallel()
+
+	testingT := T()
+
+	s := httpmock.MockServer(func(s *httpmock.Server) {
+		s.WithTest(testingT)
+
+		s.ExpectGet("/").UnlimitedTimes()
+	})
+
+	defer s.Close()
+
+	request := func(uri string) int {
+		code, _, _, _ := doRequest(t, s.URL(), http.MethodGet, uri, nil, nil, 0)
+
+		return code
+	}
+
+	expectedCode := http.StatusOK
+
+	assert.Equal(t, expectedCode, request("/"))
+	assert.NoError(t, s.ExpectationsWereMet())
+}
+
+func TestServer_ResetExpectations(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.MockServer(func(s *httpmock.Server) {
+		s.ExpectGet("/").Times(3)
+	})
+
+	defer s.Close()
+
+	s.ResetExpectations()
+
+	assert.NoError(t, s.ExpectationsWereMet())
+}
+
+// nolint:thelper // It is called in DoRequestWithTimeout.
+func doRequest(
+	t *testing.T,
+	baseURL string,
+	method, uri string,
+	headers Header,
+	body []byte,
+	waitTime time.Duration,
+) (int, map[string]string, []byte, time.Duration) {
+	return httpmock.DoRequestWithTimeout(t,
+		method, baseURL+uri,
+		headers, body,
+		waitTime+time.Second,
+	)
+}
+
+func TestServer_ValidateExpectations(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+
+	defer s.Close()
+
+	s.ExpectGet("/users").UnlimitedTimes()
+
+	assert.NoError(t, s.ValidateExpectations())
+
+	s.ExpectPost("/users").WithBody(`{"name":"john"}`)
+
+	expected := `there are expectations that can never be reached because of the unlimited expectation GET /users
+- POST /users
+    with body
+        {"name":"john"}
+`
+
+	assert.EqualError(t, s.ValidateExpectations(), expected)
+}
+
+func TestServer_ValidateExpectations_NotSupported(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer().WithPlanner(planner.FirstMatch())
+
+	defer s.Close()
+
+	s.ExpectGet("/users").UnlimitedTimes()
+	s.ExpectPost("/users")
+
+	assert.NoError(t, s.ValidateExpectations())
+}
+
+func TestServer_SnapshotRestore(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+
+	defer s.Close()
+
+	s.ExpectPost("/login").Return("token")
+	s.ExpectGet("/profile").Return("john")
+
+	code, _, body, _ := doRequest(t, s.URL(), http.MethodPost, "/login", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "token", string(body))
+
+	cp, err := s.Snapshot()
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		code, _, body, _ = doRequest(t, s.URL(), http.MethodGet, "/profile", nil, nil, 0)
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "john", string(body))
+		assert.NoError(t, s.ExpectationsWereMet())
+
+		// The next phase is rolled back to the checkpoint.
+		s.ExpectDelete("/profile")
+
+		require.NoError(t, s.Restore(cp))
+	}
+
+	assert.Error(t, s.ExpectationsWereMet())
+}
+
+func TestServer_Restore_Expectations(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer().WithPlanner(planner.FirstMatch())
+
+	defer s.Close()
+
+	s.ExpectGet("/profile").Return("john").UnlimitedTimes()
+
+	cp, err := s.Snapshot()
+	require.NoError(t, err)
+
+	doRequest(t, s.URL(), http.MethodGet, "/profile", nil, nil, 0)
+
+	s.ExpectDelete("/profile").
+		ExpectWithin(time.Millisecond)
+
+	require.NoError(t, s.Restore(cp))
+
+	// The expectation added after the checkpoint is forgotten.
+	stats := s.Stats()
+
+	require.Len(t, stats, 1)
+	assert.Equal(t, uint(0), stats[0].FulfilledTimes)
+	assert.Empty(t, stats[0].CalledAt)
+	assert.True(t, stats[0].FirstCalledAt.IsZero())
+	assert.True(t, stats[0].LastCalledAt.IsZero())
+	assert.Len(t, s.Report().Expectations, 1)
+	assert.Empty(t, s.MatchedExpectations())
+
+	time.Sleep(5 * time.Millisecond)
+
+	doRequest(t, s.URL(), http.MethodGet, "/profile", nil, nil, 0)
+
+	assert.NoError(t, s.ExpectationsWereMet())
+}
+
+func TestServer_Restore_Error(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+
+	defer s.Close()
+
+	cp, err := planner.Snapshot(planner.Sequence())
+	require.NoError(t, err)
+
+	assert.EqualError(t, s.Restore(cp), "could not restore planner: checkpoint is not taken from the planner")
+}
+
+func TestServer_RunHandler_Stream(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+
+	s := httpmock.NewServer()
+
+	defer s.Close()
+
+	s.ExpectGet("/events").
+		RunHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("first\n")) //nolint: errcheck
+
+			w.(http.Flusher).Flush()
+
+			<-release
+
+			_, _ = w.Write([]byte("second\n")) //nolint: errcheck
+		}))
+
+	resp, err := http.Get(s.URL() + "/events") //nolint: noctx
+	require.NoError(t, err)
+
+	defer resp.Body.Close() //nolint: errcheck
+
+	r := bufio.NewReader(resp.Body)
+
+	// The first event is received before the handler finishes.
+	line, err := r.ReadString('\n')
+	require.NoError(t, err)
+
+	assert.Equal(t, "first\n", line)
+
+	close(release)
+
+	line, err = r.ReadString('\n')
+	require.NoError(t, err)
+
+	assert.Equal(t, "second\n", line)
+	assert.NoError(t, s.ExpectationsWereMet())
+}
+
+func TestServer_RunHandler_Hijack(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+
+	defer s.Close()
+
+	s.ExpectGet("/hijack").
+		RunHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			conn, rw, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+
+			defer conn.Close() //nolint: errcheck
+
+			_, _ = rw.WriteString("HTTP/1.1 418 I'm a teapot\r\nContent-Length: 0\r\nConnection: close\r\n\r\n") //nolint: errcheck
+			_ = rw.Flush()                                                                                       //nolint: errcheck
+		}))
+
+	code, _, _, _ := doRequest(t, s.URL(), http.MethodGet, "/hijack", nil, nil, 0)
+
+	assert.Equal(t, http.StatusTeapot, code)
+}
+
+func TestServer_WithoutQuery(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		uri           string
+		expectedCode  int
+		expectedError string
+	}{
+		{
+			scenario:     "query is not sent",
+			uri:          "/users?page=2",
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			scenario:      "query is sent",
+			uri:           "/users?page=2&api_key=secret",
+			expectedCode:  http.StatusInternalServerError,
+			expectedError: `Error: query "api_key" is not expected, ["secret"] received`,
+		},
+		{
+			scenario:      "query is sent without value",
+			uri:           "/users?api_key",
+			expectedCode:  http.StatusInternalServerError,
+			expectedError: `Error: query "api_key" is not expected, [""] received`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			testingT := T()
+
+			s := httpmock.NewServer().WithTest(testingT)
+
+			defer s.Close()
+
+			s.ExpectGet(httpmock.RegexPattern(`^/users`)).
+				WithoutQuery("api_key").
+				ReturnCode(http.StatusNoContent)
+
+			code, _, _, _ := doRequest(t, s.URL(), http.MethodGet, tc.uri, nil, nil, 0)
+
+			assert.Equal(t, tc.expectedCode, code)
+
+			if tc.expectedError == "" {
+				assert.Empty(t, testingT.String())
+			} else {
+				assert.Contains(t, testingT.String(), tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestServer_ReturnCacheable(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+
+	defer s.Close()
+
+	s.ExpectGet("/cacheable").
+		ReturnCacheable(time.Hour)
+
+	s.ExpectGet("/no-cache").
+		ReturnNoCache()
+
+	s.ExpectGet("/private").
+		ReturnCacheable(time.Minute).
+		ReturnHeader("Cache-Control", "private, max-age=60")
+
+	s.ExpectGet("/lowercase").
+		ReturnNoCache().
+		ReturnHeader("cache-control", "no-cache")
+
+	start := time.Now().Truncate(time.Second)
+
+	_, headers, _, _ := doRequest(t, s.URL(), http.MethodGet, "/cacheable", nil, nil, 0)
+
+	assert.Equal(t, "public, max-age=3600", headers["Cache-Control"])
+	assert.Equal(t, "0", headers["Age"])
+
+	expires, err := http.ParseTime(headers["Expires"])
+	require.NoError(t, err)
+
+	assert.False(t, expires.Before(start.Add(time.Hour)))
+	assert.True(t, expires.Before(time.Now().Add(time.Hour+time.Second)))
+
+	_, headers, _, _ = doRequest(t, s.URL(), http.MethodGet, "/no-cache", nil, nil, 0)
+
+	expected := httpmock.Header{
+		"Cache-Control": "no-store, no-cache, must-revalidate, max-age=0",
+		"Pragma":        "no-cache",
+		"Expires":       "Thu, 01 Jan 1970 00:00:00 GMT",
+	}
+
+	httpmock.AssertHeaderContains(t, headers, expected)
+
+	_, headers, _, _ = doRequest(t, s.URL(), http.MethodGet, "/private", nil, nil, 0)
+
+	assert.Equal(t, "private, max-age=60", headers["Cache-Control"])
+	assert.Equal(t, "0", headers["Age"])
+
+	// The header set by ReturnHeader takes precedence regardless of its case.
+	_, headers, _, _ = doRequest(t, s.URL(), http.MethodGet, "/lowercase", nil, nil, 0)
+
+	assert.Equal(t, "no-cache", headers["Cache-Control"])
+	assert.Equal(t, "no-cache", headers["Pragma"])
+}
+
+func TestServer_WithDateSkew(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer().
+		WithDateSkew(-time.Hour)
+
+	defer s.Close()
+
+	s.ExpectGet("/")
+
+	start := time.Now().Truncate(time.Second)
+
+	_, headers, _, _ := doRequest(t, s.URL(), http.MethodGet, "/", nil, nil, 0)
+
+	date, err := http.ParseTime(headers["Date"])
+	require.NoError(t, err)
+
+	assert.False(t, date.Before(start.Add(-time.Hour)))
+	assert.True(t, date.Before(time.Now().Add(-time.Hour+time.Second)))
+}
+
+func TestServer_ReturnCorruptGzip(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+
+	defer s.Close()
+
+	s.ExpectGet("/").
+		ReturnCorruptGzip(`{"id": 42, "name": "John Doe"}`)
+
+	resp, err := http.Get(s.URL()) //nolint: noctx
+	require.NoError(t, err)
+
+	defer resp.Body.Close() //nolint: errcheck
+
+	// The client decompresses the body transparently, and fails to read it.
+	_, err = io.ReadAll(resp.Body)
+
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.True(t, resp.Uncompressed)
+	assert.NoError(t, s.ExpectationsWereMet())
+}
+
+func TestServer_WithRandSource(t *testing.T) {
+	t.Parallel()
+
+	responses := func() []string {
+		s := httpmock.NewServer().WithRandSource(42)
+
+		defer s.Close()
+
+		s.ExpectGet("/").UnlimitedTimes().
+			ReturnWeighted(map[any]int{"a": 1, "b": 1, "c": 1})
+
+		result := make([]string, 0, 20)
+
+		for i := 0; i < 20; i++ {
+			_, _, body, _ := doRequest(t, s.URL(), http.MethodGet, "/", nil, nil, 0)
+
+			result = append(result, string(body))
+		}
+
+		assert.Equal(t, int64(42), s.RandSeed())
+
+		return result
+	}
+
+	first := responses()
+
+	assert.Equal(t, first, responses())
+	assert.Contains(t, first, "a")
+	assert.Contains(t, first, "b")
+	assert.Contains(t, first, "c")
+}
+
+func TestServer_ReturnWithCharset(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+	defer s.Close()
+
+	s.ExpectGet("/text").
+		ReturnWithCharset("café", "ISO-8859-1")
+
+	s.ExpectGet("/html").
+		ReturnHeader("content-type", "text/html").
+		ReturnWithCharset("<p>café</p>", "windows-1252")
+
+	_, headers, body, _ := doRequest(t, s.URL(), http.MethodGet, "/text", nil, nil, 0)
+
+	assert.Equal(t, "text/plain; charset=ISO-8859-1", headers["Content-Type"])
+	assert.Equal(t, []byte("caf\xe9"), body)
+
+	_, headers, body, _ = doRequest(t, s.URL(), http.MethodGet, "/html", nil, nil, 0)
+
+	assert.Equal(t, "text/html; charset=windows-1252", headers["Content-Type"])
+	assert.Equal(t, []byte("<p>caf\xe9</p>"), body)
+
+	assert.PanicsWithError(t, `could not encode "5€" to ISO-8859-1: '€' is not in the charset`, func() {
+		s.ExpectGet("/").ReturnWithCharset("5€", "ISO-8859-1")
+	})
+}
+
+func TestServer_Base64(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer().WithTest(T())
+	defer s.Close()
+
+	s.ExpectPost("/upload").
+		WithBodyBase64("AAEC/w==").
+		ReturnBase64("iVBORw0KGgo=")
+
+	code, _, body, _ := doRequest(t, s.URL(), http.MethodPost, "/upload", nil, []byte{0x00, 0x01, 0x02, 0xFF}, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}, body)
+
+	assert.Panics(t, func() {
+		s.ExpectPost("/upload").WithBodyBase64("not base64")
+	})
+
+	assert.Panics(t, func() {
+		s.ExpectGet("/").ReturnBase64("not base64")
+	})
+}
+
+func TestServer_ReturnChunks(t *testing.T) {
+	t.Parallel()
+
+	const gap = 50 * time.Millisecond
+
+	s := httpmock.NewServer()
+	defer s.Close()
+
+	s.ExpectGet("/").
+		ReturnCode(httpmock.StatusAccepted).
+		ReturnChunks([][]byte{[]byte("hello "), []byte("world"), []byte("!")}, gap)
+
+	resp, err := http.Get(s.URL() + "/") //nolint: noctx
+	require.NoError(t, err)
+
+	defer resp.Body.Close() //nolint: errcheck
+
+	assert.Equal(t, httpmock.StatusAccepted, resp.StatusCode)
+	assert.Equal(t, []string{"chunked"}, resp.TransferEncoding)
+
+	first := make([]byte, len("hello "))
+
+	_, err = io.ReadFull(resp.Body, first)
+	require.NoError(t, err)
+
+	start := time.Now()
+
+	rest, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "hello ", string(first))
+	assert.Equal(t, "world!", string(rest))
+	assert.GreaterOrEqual(t, time.Since(start), gap)
 }
@@ -0,0 +1,224 @@
+package httpmock
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ExpectationSpec is a serializable definition of an expectation, used for file-driven stubs.
+//
+//	[
+//		{
+//			"method": "GET",
+//			"uri": "/users/42",
+//			"headers": {"Authorization": "Bearer token"},
+//			"times": 1,
+//			"response": {
+//				"code": 200,
+//				"headers": {"Content-Type": "application/json"},
+//				"bodyJSON": {"id": 42}
+//			}
+//		}
+//	]
+type ExpectationSpec struct {
+	// Method is the expected HTTP method.
+	Method string `json:"method"`
+	// URI is the expected request URI, matched exactly.
+	URI string `json:"uri,omitempty"`
+	// URIPattern is the expected request URI, matched by a regular expression. It takes precedence over URI.
+	URIPattern string `json:"uriPattern,omitempty"`
+	// Headers is a list of expected headers, matched exactly.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is the expected request body, matched exactly.
+	Body string `json:"body,omitempty"`
+	// BodyJSON is the expected request body, matched by JSON with <ignore-diff> support. It takes precedence over Body.
+	BodyJSON json.RawMessage `json:"bodyJSON,omitempty"`
+	// BodyBase64 is the expected request body in standard base64, for the binary payloads. It takes precedence over
+	// Body.
+	BodyBase64 string `json:"bodyBase64,omitempty"`
+	// Times is the number of times the expectation should be matched, 0 means unlimited.
+	Times uint `json:"times,omitempty"`
+	// Response is the response to send to client.
+	Response ResponseSpec `json:"response"`
+}
+
+// ResponseSpec is a serializable definition of a response.
+type ResponseSpec struct {
+	// Code is the response code, default to 200.
+	Code int `json:"code,omitempty"`
+	// Headers is a list of response headers.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is the response body.
+	Body string `json:"body,omitempty"`
+	// BodyJSON is the response body in JSON. It takes precedence over Body.
+	BodyJSON json.RawMessage `json:"bodyJSON,omitempty"`
+	// BodyBase64 is the response body in standard base64, for the binary payloads. It takes precedence over Body.
+	BodyBase64 string `json:"bodyBase64,omitempty"`
+	// File is the path to a file whose content is the response body. It takes precedence over Body, BodyJSON and
+	// BodyBase64.
+	File string `json:"file,omitempty"`
+	// Delay is the latency of the response, for example, the one recorded from a real upstream, in the format of
+	// time.ParseDuration, such as "150ms". It is scaled by Server.WithSpecDelayScale.
+	Delay string `json:"delay,omitempty"`
+}
+
+// Validate checks whether the spec is valid.
+func (s ExpectationSpec) Validate() error {
+	if s.Method == "" {
+		return errors.New("missing method") // nolint: goerr113
+	}
+
+	if s.URI == "" && s.URIPattern == "" {
+		return errors.New("missing uri or uriPattern") // nolint: goerr113
+	}
+
+	if _, err := base64.StdEncoding.DecodeString(s.BodyBase64); err != nil {
+		return fmt.Errorf("invalid bodyBase64: %w", err)
+	}
+
+	if _, err := base64.StdEncoding.DecodeString(s.Response.BodyBase64); err != nil {
+		return fmt.Errorf("invalid response bodyBase64: %w", err)
+	}
+
+	if s.Response.Delay != "" {
+		if _, err := time.ParseDuration(s.Response.Delay); err != nil {
+			return fmt.Errorf("invalid response delay: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ReadExpectationSpecs reads a list of expectation specs in JSON.
+func ReadExpectationSpecs(r io.Reader) ([]ExpectationSpec, error) {
+	var specs []ExpectationSpec
+
+	if err := json.NewDecoder(r).Decode(&specs); err != nil {
+		return nil, fmt.Errorf("could not decode expectations: %w", err)
+	}
+
+	for i, spec := range specs {
+		if err := spec.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid expectation #%d: %w", i+1, err)
+		}
+	}
+
+	return specs, nil
+}
+
+// LoadExpectationSpecs reads a list of expectation specs from a JSON file.
+func LoadExpectationSpecs(path string) ([]ExpectationSpec, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close() // nolint: errcheck
+
+	return ReadExpectationSpecs(f)
+}
+
+// ExpectSpec adds a new expectation from a spec. It returns an error, and does not add the expectation, if the spec is
+// not valid or its response file could not be read.
+//
+//	e, err := Server.ExpectSpec(httpmock.ExpectationSpec{
+//		Method:   httpmock.MethodGet,
+//		URI:      "/path",
+//		Response: httpmock.ResponseSpec{Body: "hello world!"},
+//	})
+func (s *Server) ExpectSpec(spec ExpectationSpec) (Expectation, error) {
+	if err := spec.check(); err != nil {
+		return nil, err
+	}
+
+	var uri any = spec.URI
+
+	if spec.URIPattern != "" {
+		uri = RegexPattern(spec.URIPattern)
+	}
+
+	e := s.Expect(spec.Method, uri).Times(spec.Times)
+
+	for header, val := range spec.Headers {
+		e.WithHeader(header, val)
+	}
+
+	switch {
+	case len(spec.BodyJSON) > 0:
+		e.WithBody(JSON(string(spec.BodyJSON)))
+
+	case spec.BodyBase64 != "":
+		e.WithBodyBase64(spec.BodyBase64)
+
+	case spec.Body != "":
+		e.WithBody(spec.Body)
+	}
+
+	if spec.Response.Code != 0 {
+		e.ReturnCode(spec.Response.Code)
+	}
+
+	for header, val := range spec.Response.Headers {
+		e.ReturnHeader(header, val)
+	}
+
+	switch {
+	case spec.Response.File != "":
+		e.ReturnFile(spec.Response.File)
+
+	case len(spec.Response.BodyJSON) > 0:
+		e.Return([]byte(spec.Response.BodyJSON))
+
+	case spec.Response.BodyBase64 != "":
+		e.ReturnBase64(spec.Response.BodyBase64)
+
+	case spec.Response.Body != "":
+		e.Return(spec.Response.Body)
+	}
+
+	if spec.Response.Delay != "" {
+		d, _ := time.ParseDuration(spec.Response.Delay) // nolint: errcheck // The delay is validated above.
+
+		s.mu.Lock()
+		scale := s.specDelayScale
+		s.mu.Unlock()
+
+		e.After(time.Duration(float64(d) * scale))
+	}
+
+	return e, nil
+}
+
+// check validates the spec and its response file, so the expectation could be added without panicking.
+func (s ExpectationSpec) check() error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	if s.Response.File != "" {
+		if _, err := os.Stat(filepath.Join(".", filepath.Clean(s.Response.File))); err != nil {
+			return fmt.Errorf("invalid response file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// WithSpecDelayScale scales the response delays of the expectation specs, for example, 0.5 to replay the recorded
+// latencies twice as fast, or 0 to ignore them. The default scale is 1.
+//
+//	Server.WithSpecDelayScale(0.5)
+func (s *Server) WithSpecDelayScale(scale float64) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.specDelayScale = scale
+
+	return s
+}
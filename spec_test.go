@@ -0,0 +1,222 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/planner"
+)
+
+func TestLoadExpectationSpecs(t *testing.T) {
+	t.Parallel()
+
+	specs, err := httpmock.LoadExpectationSpecs("resources/fixtures/expectations.json")
+	require.NoError(t, err)
+
+	s := httpmock.New(func(s *httpmock.Server) {
+		s.WithPlanner(planner.FirstMatch())
+
+		for _, spec := range specs {
+			_, err := s.ExpectSpec(spec)
+			require.NoError(t, err)
+		}
+	})(t)
+
+	code, _, body, _ := doRequest(t, s.URL(), http.MethodGet, "/file", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "hello world!\n", string(body))
+
+	code, _, body, _ = doRequest(t, s.URL(), http.MethodPost, "/users", nil, []byte(`{"name":"John Doe"}`), 0)
+
+	assert.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, "created", string(body))
+
+	code, headers, body, _ := doRequest(t, s.URL(), http.MethodGet, "/users/42", Header{"Authorization": "Bearer token"}, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "application/json", headers["Content-Type"])
+	assert.JSONEq(t, `{"id":42,"name":"John Doe"}`, string(body))
+}
+
+func TestReadExpectationSpecs_Error(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		input         string
+		expectedError string
+	}{
+		{
+			scenario:      "invalid json",
+			input:         `{`,
+			expectedError: `could not decode expectations: unexpected EOF`,
+		},
+		{
+			scenario:      "missing method",
+			input:         `[{"uri": "/"}]`,
+			expectedError: `invalid expectation #1: missing method`,
+		},
+		{
+			scenario:      "missing uri",
+			input:         `[{"method": "GET", "uri": "/"}, {"method": "GET"}]`,
+			expectedError: `invalid expectation #2: missing uri or uriPattern`,
+		},
+		{
+			scenario:      "invalid delay",
+			input:         `[{"method": "GET", "uri": "/", "response": {"delay": "soon"}}]`,
+			expectedError: `invalid expectation #1: invalid response delay: time: invalid duration "soon"`,
+		},
+		{
+			scenario:      "invalid body base64",
+			input:         `[{"method": "POST", "uri": "/", "bodyBase64": "not base64"}]`,
+			expectedError: `invalid expectation #1: invalid bodyBase64: illegal base64 data at input byte 3`,
+		},
+		{
+			scenario:      "invalid response body base64",
+			input:         `[{"method": "GET", "uri": "/", "response": {"bodyBase64": "AQI"}}]`,
+			expectedError: `invalid expectation #1: invalid response bodyBase64: illegal base64 data at input byte 0`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			specs, err := httpmock.ReadExpectationSpecs(strings.NewReader(tc.input))
+
+			assert.Nil(t, specs)
+			assert.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestLoadExpectationSpecs_FileNotFound(t *testing.T) {
+	t.Parallel()
+
+	specs, err := httpmock.LoadExpectationSpecs("resources/fixtures/not-found.json")
+
+	assert.Nil(t, specs)
+	assert.Error(t, err)
+}
+
+func TestServer_ExpectSpec_Error(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		spec          httpmock.ExpectationSpec
+		expectedError string
+	}{
+		{
+			scenario:      "invalid spec",
+			spec:          httpmock.ExpectationSpec{Method: httpmock.MethodGet},
+			expectedError: "missing uri or uriPattern",
+		},
+		{
+			scenario: "missing response file",
+			spec: httpmock.ExpectationSpec{
+				Method:   httpmock.MethodGet,
+				URI:      "/file",
+				Response: httpmock.ResponseSpec{File: "resources/fixtures/not-found.txt"},
+			},
+			expectedError: "invalid response file: stat resources/fixtures/not-found.txt: no such file or directory",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			s := httpmock.NewServer()
+
+			defer s.Close()
+
+			e, err := s.ExpectSpec(tc.spec)
+
+			assert.Nil(t, e)
+			assert.EqualError(t, err, tc.expectedError)
+
+			// The expectation is not added.
+			assert.Empty(t, s.Expectations())
+		})
+	}
+}
+
+func TestServer_ExpectSpec_Delay(t *testing.T) {
+	t.Parallel()
+
+	const delay = 100 * time.Millisecond
+
+	specs, err := httpmock.ReadExpectationSpecs(strings.NewReader(`[
+		{"method": "GET", "uri": "/slow", "response": {"body": "slow", "delay": "200ms"}}
+	]`))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		scenario    string
+		scale       float64
+		expectedMin time.Duration
+		expectedMax time.Duration
+	}{
+		{
+			scenario:    "scaled",
+			scale:       0.5,
+			expectedMin: delay,
+			expectedMax: 2 * delay,
+		},
+		{
+			scenario:    "ignored",
+			scale:       0,
+			expectedMax: delay,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			s := httpmock.NewServer().WithSpecDelayScale(tc.scale)
+
+			defer s.Close()
+
+			_, err := s.ExpectSpec(specs[0])
+			require.NoError(t, err)
+
+			code, _, body, elapsed := doRequest(t, s.URL(), http.MethodGet, "/slow", nil, nil, 0)
+
+			assert.Equal(t, http.StatusOK, code)
+			assert.Equal(t, "slow", string(body))
+			assert.GreaterOrEqual(t, elapsed, tc.expectedMin)
+			assert.Less(t, elapsed, tc.expectedMax)
+		})
+	}
+}
+
+func TestServer_ExpectSpec_Base64(t *testing.T) {
+	t.Parallel()
+
+	specs, err := httpmock.ReadExpectationSpecs(strings.NewReader(`[
+		{"method": "POST", "uri": "/upload", "bodyBase64": "AAEC/w==", "response": {"bodyBase64": "iVBORw0KGgo="}}
+	]`))
+	require.NoError(t, err)
+
+	s := httpmock.New(func(s *httpmock.Server) {
+		_, err := s.ExpectSpec(specs[0])
+		require.NoError(t, err)
+	})(t)
+
+	code, _, body, _ := doRequest(t, s.URL(), http.MethodPost, "/upload", nil, []byte{0x00, 0x01, 0x02, 0xFF}, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}, body)
+}
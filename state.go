@@ -0,0 +1,125 @@
+package httpmock
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// stateKey is the context key of the state of the server that receives a request.
+type stateKey struct{}
+
+// State is a key-value store shared by the expectations of a server, so the dynamic responses of many requests can
+// share the variables and the counters without the closures and the mutexes of the test, see Server.State and StateOf.
+// It is safe for concurrent use.
+type State struct {
+	mu     sync.Mutex
+	values map[string]any
+}
+
+// Get returns the value of the key, nil if it is not set.
+func (s *State) Get(key string) any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.values[key]
+}
+
+// Lookup returns the value of the key and whether it is set.
+func (s *State) Lookup(key string) (any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.values[key]
+
+	return v, ok
+}
+
+// Set sets the value of the key.
+func (s *State) Set(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.values == nil {
+		s.values = make(map[string]any)
+	}
+
+	s.values[key] = value
+}
+
+// Incr increases the counter of the key by 1 and returns the new value. A counter that is not set starts from 0. It
+// panics if the value of the key is not an int.
+func (s *State) Incr(key string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.values == nil {
+		s.values = make(map[string]any)
+	}
+
+	var n int
+
+	if v, ok := s.values[key]; ok {
+		n = v.(int) // nolint: forcetypeassert
+	}
+
+	n++
+
+	s.values[key] = n
+
+	return n
+}
+
+// Delete removes the key.
+func (s *State) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.values, key)
+}
+
+// Reset removes all the keys, for example, to start another subtest from scratch.
+func (s *State) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values = nil
+}
+
+// State returns the key-value store of the server. The same store is available to the handlers of the requests, see
+// StateOf.
+//
+//	srv.ExpectPost("/login").
+//		Run(func(r *http.Request) ([]byte, error) {
+//			httpmock.StateOf(r).Set("token", "secret")
+//
+//			return []byte(`{"token":"secret"}`), nil
+//		})
+//
+//	// Your requests.
+//
+//	assert.Equal(t, "secret", srv.State().Get("token"))
+func (s *Server) State() *State {
+	return &s.state
+}
+
+// StateOf returns the key-value store of the server that receives the request, nil if the request is not received by a
+// server. It could be used in the handlers, for example, Run and RunHandler, and in the matchers of the whole request,
+// see MatchRequest.
+//
+//	Server.Expect(httpmock.MethodGet, httpmock.MatchRequest(func(r *http.Request) (bool, error) {
+//		return httpmock.StateOf(r).Get("logged_in") == true, nil
+//	})).
+//		Run(func(r *http.Request) ([]byte, error) {
+//			return []byte(fmt.Sprintf(`{"visits":%d}`, httpmock.StateOf(r).Incr("visits"))), nil
+//		})
+func StateOf(r *http.Request) *State {
+	s, _ := r.Context().Value(stateKey{}).(*State) // nolint: errcheck
+
+	return s
+}
+
+// withState attaches the state to the request, see StateOf.
+func withState(r *http.Request, s *State) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), stateKey{}, s))
+}
@@ -0,0 +1,92 @@
+package httpmock_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/planner"
+)
+
+func TestState(t *testing.T) {
+	t.Parallel()
+
+	var s httpmock.State
+
+	assert.Nil(t, s.Get("foo"))
+
+	s.Set("foo", "bar")
+
+	v, ok := s.Lookup("foo")
+
+	assert.True(t, ok)
+	assert.Equal(t, "bar", v)
+
+	assert.Equal(t, 1, s.Incr("visits"))
+	assert.Equal(t, 2, s.Incr("visits"))
+
+	s.Delete("foo")
+
+	_, ok = s.Lookup("foo")
+
+	assert.False(t, ok)
+	assert.Equal(t, 2, s.Get("visits"))
+
+	s.Reset()
+
+	assert.Nil(t, s.Get("visits"))
+}
+
+func TestServer_State(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.WithPlanner(planner.FirstMatch())
+
+		s.ExpectPost("/login").
+			Run(func(r *http.Request) ([]byte, error) {
+				httpmock.StateOf(r).Set("logged_in", true)
+
+				return nil, nil
+			})
+
+		s.ExpectGet(httpmock.MatchRequest(func(r *http.Request) (bool, error) {
+			return httpmock.StateOf(r).Get("logged_in") == true, nil
+		})).
+			Run(func(r *http.Request) ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"visits":%d}`, httpmock.StateOf(r).Incr("visits"))), nil
+			}).
+			Twice()
+
+		s.ExpectGet("/profile").
+			ReturnCode(httpmock.StatusUnauthorized)
+	})(t)
+
+	code, _, _, _ := doRequest(t, srv.URL(), http.MethodGet, "/profile", nil, nil, 0)
+
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	code, _, _, _ = doRequest(t, srv.URL(), http.MethodPost, "/login", nil, nil, 0)
+
+	assert.Equal(t, http.StatusOK, code)
+
+	for i := 1; i <= 2; i++ {
+		code, _, body, _ := doRequest(t, srv.URL(), http.MethodGet, "/profile", nil, nil, 0)
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, fmt.Sprintf(`{"visits":%d}`, i), string(body))
+	}
+
+	assert.Equal(t, true, srv.State().Get("logged_in"))
+	assert.Equal(t, 2, srv.State().Get("visits"))
+}
+
+func TestStateOf_NotReceivedByServer(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, httpmock.StateOf(httptest.NewRequest(http.MethodGet, "/", nil)))
+}
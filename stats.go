@@ -0,0 +1,69 @@
+package httpmock
+
+import "time"
+
+// ExpectationStats contains the call statistics of an expectation.
+type ExpectationStats struct {
+	// ID is the identifier of the expectation in the server.
+	ID int
+	// Method is the expected HTTP method.
+	Method string
+	// URI is the expected request URI.
+	URI string
+	// FulfilledTimes is the number of times the expectation was called.
+	FulfilledTimes uint
+	// RemainTimes is the number of remaining calls, 0 means unlimited or no call is left.
+	RemainTimes uint
+	// FirstCalledAt is when the expectation was called for the first time.
+	FirstCalledAt time.Time
+	// LastCalledAt is when the expectation was called for the last time.
+	LastCalledAt time.Time
+	// CalledAt are the times when the expectation was called.
+	CalledAt []time.Time
+	// MaxConcurrent is the maximum number of the requests in flight at the same time.
+	MaxConcurrent int
+	// AverageLatency is the average time spent on handling a request, including the delay.
+	AverageLatency time.Duration
+}
+
+func (e *requestExpectation) stats() ExpectationStats {
+	e.lock()
+	defer e.unlock()
+
+	result := ExpectationStats{
+		ID:             e.id,
+		Method:         e.requestMethod,
+		URI:            e.requestURIMatcher.Expected(),
+		FulfilledTimes: e.fulfilledTimes,
+		RemainTimes:    e.repeatTimes,
+		FirstCalledAt:  e.firstCalledAt,
+		LastCalledAt:   e.lastCalledAt,
+		MaxConcurrent:  maxConcurrent(e.inFlight),
+	}
+
+	if len(e.calledAt) > 0 {
+		result.CalledAt = make([]time.Time, len(e.calledAt))
+
+		copy(result.CalledAt, e.calledAt)
+	}
+
+	if e.handledTimes > 0 {
+		result.AverageLatency = e.handleDuration / time.Duration(e.handledTimes)
+	}
+
+	return result
+}
+
+// Stats returns the call statistics of all the expectations, in the order they were registered.
+func (s *Server) Stats() []ExpectationStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]ExpectationStats, len(s.expectations))
+
+	for i, e := range s.expectations {
+		result[i] = e.stats()
+	}
+
+	return result
+}
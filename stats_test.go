@@ -0,0 +1,52 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestServer_Stats(t *testing.T) {
+	t.Parallel()
+
+	delay := 50 * time.Millisecond
+
+	s := httpmock.MockServer(func(s *httpmock.Server) {
+		s.ExpectGet("/slow").
+			After(delay).
+			Twice()
+
+		s.ExpectGet("/never")
+	})
+
+	defer s.Close()
+
+	start := time.Now()
+
+	doRequest(t, s.URL(), http.MethodGet, "/slow", nil, nil, delay)
+	doRequest(t, s.URL(), http.MethodGet, "/slow", nil, nil, delay)
+
+	stats := s.Stats()
+
+	assert.Len(t, stats, 2)
+
+	assert.Equal(t, 1, stats[0].ID)
+	assert.Equal(t, http.MethodGet, stats[0].Method)
+	assert.Equal(t, "/slow", stats[0].URI)
+	assert.Equal(t, uint(2), stats[0].FulfilledTimes)
+	assert.Equal(t, uint(0), stats[0].RemainTimes)
+	assert.True(t, stats[0].FirstCalledAt.After(start))
+	assert.True(t, stats[0].LastCalledAt.After(stats[0].FirstCalledAt))
+	assert.GreaterOrEqual(t, stats[0].AverageLatency, delay)
+
+	assert.Equal(t, httpmock.ExpectationStats{
+		ID:          2,
+		Method:      http.MethodGet,
+		URI:         "/never",
+		RemainTimes: 1,
+	}, stats[1])
+}
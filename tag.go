@@ -0,0 +1,60 @@
+package httpmock
+
+import "go.nhat.io/httpmock/planner"
+
+// Tag adds the expectation to a group, so the group could be verified on its own with Server.ExpectationsWereMetFor.
+// An expectation could have many tags.
+//
+//	Server.Expect(http.MethodPost, "/login").
+//		Tag("login")
+func (e *requestExpectation) Tag(tag string) Expectation {
+	e.lock()
+	defer e.unlock()
+
+	e.tags = append(e.tags, tag)
+
+	return e
+}
+
+// hasTag checks whether the expectation has the tag.
+func (e *requestExpectation) hasTag(tag string) bool {
+	e.lock()
+	defer e.unlock()
+
+	for _, t := range e.tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ExpectationsWereMetFor checks whether all the queued expectations of the tag were met, see Expectation.Tag. The
+// expectations of the other tags are not checked, so a phase of a test could be verified while the expectations of the
+// next phases are still pending.
+//
+//	srv.ExpectPost("/login").Tag("login")
+//	srv.ExpectGet("/profile").Tag("profile")
+//
+//	// Log in.
+//
+//	assert.NoError(t, srv.ExpectationsWereMetFor("login"))
+func (s *Server) ExpectationsWereMetFor(tag string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.planner.IsEmpty() {
+		return nil
+	}
+
+	var expectations []planner.Expectation
+
+	for _, expected := range s.planner.Remain() {
+		if e, ok := asRequestExpectation(expected); ok && e.hasTag(tag) {
+			expectations = append(expectations, e)
+		}
+	}
+
+	return expectationsWereMet(expectations)
+}
@@ -0,0 +1,74 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/planner"
+)
+
+func TestServer_ExpectationsWereMetFor(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.NewServer().WithPlanner(planner.FirstMatch())
+	defer srv.Close()
+
+	srv.ExpectPost("/login").
+		Tag("login")
+
+	srv.ExpectGet("/profile").
+		Tag("profile").
+		Tag("user")
+
+	srv.ExpectGet("/settings")
+
+	expected := "there are remaining expectations that were not met:\n- POST /login\n"
+
+	assert.EqualError(t, srv.ExpectationsWereMetFor("login"), expected)
+
+	doRequest(t, srv.URL(), http.MethodPost, "/login", nil, nil, 0)
+
+	assert.NoError(t, srv.ExpectationsWereMetFor("login"))
+	assert.NoError(t, srv.ExpectationsWereMetFor("unknown"))
+
+	expected = "there are remaining expectations that were not met:\n- GET /profile\n"
+
+	assert.EqualError(t, srv.ExpectationsWereMetFor("profile"), expected)
+	assert.EqualError(t, srv.ExpectationsWereMetFor("user"), expected)
+
+	doRequest(t, srv.URL(), http.MethodGet, "/profile", nil, nil, 0)
+
+	assert.NoError(t, srv.ExpectationsWereMetFor("user"))
+	assert.Error(t, srv.ExpectationsWereMet())
+}
+
+func TestServer_ExpectationsWereMetFor_Empty(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.NewServer()
+	defer srv.Close()
+
+	assert.NoError(t, srv.ExpectationsWereMetFor("login"))
+}
+
+func TestServer_ExpectationsWereMetFor_PerSession(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.NewServer().
+		WithPlanner(planner.Chain(planner.PerSession(planner.SessionByRemoteAddr(), planner.Sequence)))
+	defer srv.Close()
+
+	srv.ExpectPost("/login").
+		Tag("login")
+
+	srv.ExpectGet("/profile")
+
+	assert.Error(t, srv.ExpectationsWereMetFor("login"))
+
+	doRequest(t, srv.URL(), http.MethodPost, "/login", nil, nil, 0)
+
+	assert.NoError(t, srv.ExpectationsWereMetFor("login"))
+}
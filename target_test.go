@@ -0,0 +1,78 @@
+package httpmock_test
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/planner"
+)
+
+func TestExpectation_WithHost_AbsoluteForm(t *testing.T) {
+	t.Parallel()
+
+	testingT := T()
+
+	s := httpmock.NewServer().
+		WithTest(testingT).
+		WithPlanner(planner.FirstMatch())
+
+	defer s.Close()
+
+	s.ExpectGet("/users").
+		WithScheme("http").
+		WithHost("api.example.com").
+		Return("users of api")
+
+	s.ExpectGet("http://legacy.example.com:8080/users").
+		Return("users of legacy")
+
+	s.ExpectGet("/users").
+		WithHost(httpmock.RegexPattern(`^127\.0\.0\.1:\d+$`)).
+		Return("users of the server")
+
+	proxyURL, err := url.Parse(s.URL())
+	require.NoError(t, err)
+
+	proxy := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}
+
+	request := func(client *http.Client, target string) (int, string) {
+		resp, err := client.Get(target) //nolint: noctx
+		require.NoError(t, err)
+
+		defer resp.Body.Close() //nolint: errcheck
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp.StatusCode, string(body)
+	}
+
+	code, _ := request(proxy, "http://unknown.example.com/users")
+
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Contains(t, testingT.String(), `Error: host "api.example.com" expected, "unknown.example.com" received`)
+	assert.Equal(t, "http://unknown.example.com/users", s.LastRequest().RequestURI)
+
+	code, body := request(proxy, "http://API.example.com:80/users")
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "users of api", body)
+
+	code, body = request(proxy, "http://legacy.example.com:8080/users")
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "users of legacy", body)
+
+	code, body = request(http.DefaultClient, s.URL()+"/users")
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "users of the server", body)
+
+	assert.NoError(t, s.ExpectationsWereMet())
+}
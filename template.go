@@ -0,0 +1,68 @@
+package httpmock
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"text/template"
+
+	"go.nhat.io/httpmock/must"
+)
+
+// TemplateData is the data of a response template, see Expectation.ReturnTemplate.
+type TemplateData struct {
+	// CallNumber is the number of the call of the expectation that handles the request, starting from 1. It is the
+	// fulfilled times of the expectation, so the cancelled requests that are not counted, see
+	// Server.WithoutCountingCancelledRequests, do not take a number.
+	CallNumber uint
+	// Request is the request.
+	Request *http.Request
+	// State is the key-value store of the server that receives the request, see Server.State.
+	State *State
+	// PathParams are the parameters of the request uri template, see PathParams.
+	PathParams map[string]string
+}
+
+// ReturnTemplate uses the result of the text/template as the response, so the responses of the calls could differ
+// without a custom handler, for example, an incrementing id or a cursor. The data of the template is TemplateData. It
+// panics if the template could not be parsed.
+//
+//	Server.Expect(httpmock.MethodGet, "/events").
+//		ReturnTemplate(`{"id":{{.CallNumber}},"cursor":"{{.Request.URL.Query.Get "cursor"}}"}`).
+//		UnlimitedTimes()
+func (e *requestExpectation) ReturnTemplate(tmpl string) Expectation {
+	t, err := template.New("response").Parse(tmpl)
+	must.NotFail(err)
+
+	return e.Run(func(r *http.Request) ([]byte, error) {
+		data := TemplateData{
+			CallNumber: callNumber(r),
+			Request:    r,
+			State:      StateOf(r),
+			PathParams: PathParams(r),
+		}
+
+		var buf bytes.Buffer
+
+		if err := t.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	})
+}
+
+type callNumberKey struct{}
+
+// withCallNumber attaches the number of the call of the expectation that handles the request to it, see
+// TemplateData.CallNumber.
+func withCallNumber(r *http.Request, n uint) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), callNumberKey{}, n))
+}
+
+// callNumber returns the number of the call of the expectation that handles the request, 0 if it is unknown.
+func callNumber(r *http.Request) uint {
+	n, _ := r.Context().Value(callNumberKey{}).(uint) // nolint: errcheck
+
+	return n
+}
@@ -0,0 +1,60 @@
+package httpmock_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestExpectation_ReturnTemplate(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet(httpmock.RegexPattern(`^/events`)).
+			ReturnTemplate(`{"id":{{.CallNumber}},"cursor":"{{.Request.URL.Query.Get "cursor"}}","user":"{{.State.Get "user"}}"}`).
+			Times(3)
+	})(t)
+
+	srv.State().Set("user", "john")
+
+	for i := 1; i <= 3; i++ {
+		uri := fmt.Sprintf("/events?cursor=c%d", i)
+
+		code, _, body, _ := doRequest(t, srv.URL(), http.MethodGet, uri, nil, nil, 0)
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, fmt.Sprintf(`{"id":%d,"cursor":"c%d","user":"john"}`, i, i), string(body))
+	}
+}
+
+func TestExpectation_ReturnTemplate_Panic(t *testing.T) {
+	t.Parallel()
+
+	assert.PanicsWithError(t, `template: response:1: unclosed action`, func() {
+		httpmock.NewExpectation(http.MethodGet, "/").
+			ReturnTemplate(`{{.CallNumber`)
+	})
+}
+
+func TestExpectation_ReturnTemplate_Clone(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet("/events").
+			ReturnTemplate(`{{.CallNumber}}`).
+			Times(2).
+			Clone()
+	})(t)
+
+	// The clone counts its own calls.
+	for _, expected := range []string{"1", "2", "1", "2"} {
+		code, _, body, _ := doRequest(t, srv.URL(), http.MethodGet, "/events", nil, nil, 0)
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, expected, string(body))
+	}
+}
@@ -0,0 +1,303 @@
+package httpmock
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"go.nhat.io/httpmock/test"
+)
+
+// TLSFault is a fault of the TLS server, to test the certificate validation of the clients.
+type TLSFault int
+
+const (
+	// TLSExpiredCertificate presents an expired certificate.
+	TLSExpiredCertificate TLSFault = iota + 1
+	// TLSSelfSignedCertificate presents a self-signed certificate that is not trusted by Server.Client.
+	TLSSelfSignedCertificate
+	// TLSWrongHostCertificate presents a certificate for another host.
+	TLSWrongHostCertificate
+	// TLSAbortHandshake aborts the handshake.
+	TLSAbortHandshake
+)
+
+// JournalTLS is the state of the TLS connection of a request received by the server.
+type JournalTLS struct {
+	// Version is the TLS version, for example, tls.VersionTLS13.
+	Version uint16 `json:"version"`
+	// CipherSuite is the cipher suite, for example, tls.TLS_AES_128_GCM_SHA256.
+	CipherSuite uint16 `json:"cipherSuite"`
+	// ServerName is the server name sent by the client (SNI), empty if the client did not send it.
+	ServerName string `json:"serverName,omitempty"`
+	// NegotiatedProtocol is the application protocol negotiated with ALPN, for example, h2.
+	NegotiatedProtocol string `json:"negotiatedProtocol,omitempty"`
+	// PeerCertificates are the subjects of the client certificates, if the server requests them.
+	PeerCertificates []string `json:"peerCertificates,omitempty"`
+}
+
+func newJournalTLS(state *tls.ConnectionState) *JournalTLS {
+	if state == nil {
+		return nil
+	}
+
+	result := &JournalTLS{
+		Version:            state.Version,
+		CipherSuite:        state.CipherSuite,
+		ServerName:         state.ServerName,
+		NegotiatedProtocol: state.NegotiatedProtocol,
+	}
+
+	for _, c := range state.PeerCertificates {
+		result.PeerCertificates = append(result.PeerCertificates, c.Subject.String())
+	}
+
+	return result
+}
+
+// tlsVersionName returns the name of the TLS version, for example, TLS 1.3.
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	}
+
+	return fmt.Sprintf("0x%04X", version)
+}
+
+// AssertTLSMinVersion asserts that all the requests received by the server are sent over TLS with at least the version,
+// so a client that drops to an older version is caught.
+//
+//	srv.AssertTLSMinVersion(t, tls.VersionTLS12)
+func (s *Server) AssertTLSMinVersion(t test.T, version uint16) bool {
+	return s.assertTLS(t, func(entry JournalEntry) string {
+		if entry.TLS.Version < version {
+			return fmt.Sprintf("%s or later expected, %s received", tlsVersionName(version), tlsVersionName(entry.TLS.Version))
+		}
+
+		return ""
+	})
+}
+
+// AssertTLSServerName asserts that all the requests received by the server are sent over TLS with the server name
+// (SNI).
+//
+//	srv.AssertTLSServerName(t, "api.example.com")
+func (s *Server) AssertTLSServerName(t test.T, serverName string) bool {
+	return s.assertTLS(t, func(entry JournalEntry) string {
+		if entry.TLS.ServerName != serverName {
+			return fmt.Sprintf("server name %q expected, %q received", serverName, entry.TLS.ServerName)
+		}
+
+		return ""
+	})
+}
+
+// AssertTLSClientCertificate asserts that all the requests received by the server are sent over TLS with a client
+// certificate of the subject, for example, CN=client,O=Acme.
+func (s *Server) AssertTLSClientCertificate(t test.T, subject string) bool {
+	return s.assertTLS(t, func(entry JournalEntry) string {
+		for _, c := range entry.TLS.PeerCertificates {
+			if c == subject {
+				return ""
+			}
+		}
+
+		return fmt.Sprintf("client certificate %q expected, %q received", subject, entry.TLS.PeerCertificates)
+	})
+}
+
+func (s *Server) assertTLS(t test.T, check func(entry JournalEntry) string) bool {
+	ok := true
+
+	for _, entry := range s.Journal() {
+		msg := "request is not sent over TLS"
+
+		if entry.TLS != nil {
+			msg = check(entry)
+		}
+
+		if msg != "" {
+			t.Errorf("%s %s: %s", entry.Method, entry.RequestURI, msg)
+
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// errTLSHandshakeAborted indicates that the handshake is aborted by TLSAbortHandshake.
+var errTLSHandshakeAborted = errors.New("tls handshake aborted")
+
+// NewTLSServer creates a new server and starts it with TLS.
+func NewTLSServer() *Server {
+	s := NewUnstartedServer()
+
+	s.StartTLS()
+
+	return s
+}
+
+// StartTLS starts the server with TLS. The certificate is trusted by the client returned by Client.
+func (s *Server) StartTLS() {
+	s.mu.Lock()
+	fault := s.tlsFault
+	s.mu.Unlock()
+
+	if fault != 0 {
+		s.server.TLS = &tls.Config{ // nolint: gosec
+			GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+				return s.tlsFaultConfig(fault)
+			},
+		}
+
+		// The handshake errors are expected.
+		s.server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	}
+
+	s.captureHeaderOrder()
+	s.server.StartTLS()
+	s.markStarted()
+}
+
+// WithTLSFault makes the TLS server present a bad certificate or abort the handshake, so the certificate validation and
+// pinning of the clients can be tested. It must be called before StartTLS.
+//
+//	srv := httpmock.NewUnstartedServer().
+//		WithTLSFault(httpmock.TLSExpiredCertificate)
+//
+//	srv.StartTLS()
+//
+//	_, err := srv.Client().Get(srv.URL()) // x509: certificate has expired or is not yet valid.
+func (s *Server) WithTLSFault(fault TLSFault) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tlsFault = fault
+
+	return s
+}
+
+// WithHTTP2 enables HTTP/2 on the TLS server, the client returned by Client negotiates it. It must be called before
+// StartTLS.
+//
+//	srv := httpmock.NewUnstartedServer().
+//		WithHTTP2()
+//
+//	srv.StartTLS()
+func (s *Server) WithHTTP2() *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.server.EnableHTTP2 = true
+
+	return s
+}
+
+// Certificate returns the certificate of the TLS server, nil if the server is not started with TLS.
+func (s *Server) Certificate() *x509.Certificate {
+	return s.server.Certificate()
+}
+
+// TLSConfig returns a copy of the TLS config of the server, nil if the server is not started with TLS, for example, to
+// serve the same certificate on another listener.
+func (s *Server) TLSConfig() *tls.Config {
+	if s.server.TLS == nil {
+		return nil
+	}
+
+	return s.server.TLS.Clone()
+}
+
+// Client returns a client that trusts the certificate of the TLS server.
+func (s *Server) Client() *http.Client {
+	return s.server.Client()
+}
+
+// tlsFaultConfig returns the TLS config with the faulty certificate.
+func (s *Server) tlsFaultConfig(fault TLSFault) (*tls.Config, error) {
+	if fault == TLSAbortHandshake {
+		return nil, errTLSHandshakeAborted
+	}
+
+	s.tlsFaultOnce.Do(func() {
+		s.tlsFaultCert, s.tlsFaultErr = newFaultyCertificate(fault, s.server.TLS.Certificates[0])
+	})
+
+	if s.tlsFaultErr != nil {
+		return nil, s.tlsFaultErr
+	}
+
+	return &tls.Config{Certificates: []tls.Certificate{s.tlsFaultCert}}, nil // nolint: gosec
+}
+
+// newFaultyCertificate creates a certificate with the fault. Except the self-signed one, the certificates are signed by
+// the trusted certificate of the server.
+func newFaultyCertificate(fault TLSFault, trusted tls.Certificate) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	serial, err := newSerialNumber()
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	now := time.Now()
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{Organization: []string{"httpmock"}},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost", "example.com"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+
+	parent, signer := template, any(key)
+
+	switch fault {
+	case TLSExpiredCertificate:
+		template.NotBefore = now.Add(-48 * time.Hour)
+		template.NotAfter = now.Add(-24 * time.Hour)
+
+	case TLSWrongHostCertificate:
+		template.DNSNames = []string{"wrong.host.example"}
+		template.IPAddresses = nil
+	}
+
+	if fault != TLSSelfSignedCertificate {
+		if parent, err = x509.ParseCertificate(trusted.Certificate[0]); err != nil {
+			return tls.Certificate{}, err
+		}
+
+		signer = trusted.PrivateKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
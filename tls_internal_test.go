@@ -0,0 +1,44 @@
+package httpmock
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewJournalTLS(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, newJournalTLS(nil))
+
+	state := &tls.ConnectionState{
+		Version:            tls.VersionTLS13,
+		CipherSuite:        tls.TLS_AES_128_GCM_SHA256,
+		ServerName:         "example.com",
+		NegotiatedProtocol: "h2",
+		PeerCertificates: []*x509.Certificate{
+			{Subject: pkix.Name{CommonName: "client", Organization: []string{"Acme"}}},
+		},
+	}
+
+	expected := &JournalTLS{
+		Version:            tls.VersionTLS13,
+		CipherSuite:        tls.TLS_AES_128_GCM_SHA256,
+		ServerName:         "example.com",
+		NegotiatedProtocol: "h2",
+		PeerCertificates:   []string{"CN=client,O=Acme"},
+	}
+
+	assert.Equal(t, expected, newJournalTLS(state))
+}
+
+func TestTLSVersionName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "TLS 1.0", tlsVersionName(tls.VersionTLS10))
+	assert.Equal(t, "TLS 1.1", tlsVersionName(tls.VersionTLS11))
+	assert.Equal(t, "0x0300", tlsVersionName(0x0300))
+}
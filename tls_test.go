@@ -0,0 +1,193 @@
+package httpmock_test
+
+import (
+	"crypto/tls"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestNewTLSServer(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewTLSServer()
+
+	defer s.Close()
+
+	s.ExpectGet("/").Return("secured")
+
+	resp, err := s.Client().Get(s.URL() + "/") //nolint: noctx
+	require.NoError(t, err)
+
+	defer resp.Body.Close() //nolint: errcheck
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotNil(t, resp.TLS)
+	assert.NotNil(t, s.Certificate())
+	assert.NotEmpty(t, s.TLSConfig().Certificates)
+}
+
+func TestServer_TLSConfig_NotTLS(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+
+	defer s.Close()
+
+	assert.Nil(t, s.TLSConfig())
+}
+
+func TestServer_WithTLSFault(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		fault         httpmock.TLSFault
+		expectedError string
+	}{
+		{
+			scenario:      "expired certificate",
+			fault:         httpmock.TLSExpiredCertificate,
+			expectedError: "certificate has expired or is not yet valid",
+		},
+		{
+			scenario:      "self-signed certificate",
+			fault:         httpmock.TLSSelfSignedCertificate,
+			expectedError: "certificate signed by unknown authority",
+		},
+		{
+			scenario:      "wrong host certificate",
+			fault:         httpmock.TLSWrongHostCertificate,
+			expectedError: "cannot validate certificate for 127.0.0.1",
+		},
+		{
+			scenario:      "abort handshake",
+			fault:         httpmock.TLSAbortHandshake,
+			expectedError: "remote error: tls:",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			s := httpmock.NewUnstartedServer().
+				WithTLSFault(tc.fault)
+
+			s.StartTLS()
+
+			defer s.Close()
+
+			resp, err := s.Client().Get(s.URL() + "/") //nolint: noctx
+			if resp != nil {
+				_ = resp.Body.Close() //nolint: errcheck
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestServer_AssertTLS(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewTLSServer()
+
+	defer s.Close()
+
+	s.ExpectGet("/").Return("secured")
+
+	client := s.Client()
+	transport := client.Transport.(*http.Transport) //nolint: forcetypeassert
+
+	transport.TLSClientConfig.ServerName = "example.com"
+	transport.TLSClientConfig.MaxVersion = tls.VersionTLS12
+
+	resp, err := client.Get(s.URL() + "/") //nolint: noctx
+	require.NoError(t, err)
+
+	defer resp.Body.Close() //nolint: errcheck
+
+	journal := s.Journal()
+
+	require.Len(t, journal, 1)
+	require.NotNil(t, journal[0].TLS)
+
+	assert.Equal(t, uint16(tls.VersionTLS12), journal[0].TLS.Version)
+	assert.Equal(t, resp.TLS.CipherSuite, journal[0].TLS.CipherSuite)
+	assert.Equal(t, "example.com", journal[0].TLS.ServerName)
+
+	assert.True(t, s.AssertTLSMinVersion(T(), tls.VersionTLS12))
+	assert.True(t, s.AssertTLSServerName(T(), "example.com"))
+
+	testingT := T()
+
+	assert.False(t, s.AssertTLSMinVersion(testingT, tls.VersionTLS13))
+	assert.False(t, s.AssertTLSServerName(testingT, "api.example.com"))
+	assert.False(t, s.AssertTLSClientCertificate(testingT, "CN=client"))
+
+	expected := `GET /: TLS 1.3 or later expected, TLS 1.2 received` +
+		`GET /: server name "api.example.com" expected, "example.com" received` +
+		`GET /: client certificate "CN=client" expected, [] received`
+
+	assert.Equal(t, expected, testingT.String())
+}
+
+func TestServer_AssertTLS_NotTLS(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer()
+
+	defer s.Close()
+
+	s.ExpectGet("/")
+
+	doRequest(t, s.URL(), http.MethodGet, "/", nil, nil, 0)
+
+	testingT := T()
+
+	assert.False(t, s.AssertTLSMinVersion(testingT, tls.VersionTLS12))
+	assert.Equal(t, "GET /: request is not sent over TLS", testingT.String())
+	assert.Nil(t, s.Journal()[0].TLS)
+}
+
+func TestServer_WithHTTP2(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewUnstartedServer().
+		WithTest(T()).
+		WithHTTP2()
+
+	s.StartTLS()
+
+	defer s.Close()
+
+	s.ExpectGet("/h2").WithProto("HTTP/2.0").Return("h2")
+	s.ExpectGet("/h1").WithProto("HTTP/1.1").Return("h1")
+
+	resp, err := s.Client().Get(s.URL() + "/h2") //nolint: noctx
+	require.NoError(t, err)
+
+	defer resp.Body.Close() //nolint: errcheck
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "HTTP/2.0", resp.Proto)
+
+	resp, err = s.Client().Get(s.URL() + "/h1") //nolint: noctx
+	require.NoError(t, err)
+
+	defer resp.Body.Close() //nolint: errcheck
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Contains(t, string(body), `proto "HTTP/1.1" expected, "HTTP/2.0" received`)
+}
@@ -0,0 +1,183 @@
+package httpmock
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+var _ http.RoundTripper = (*traceTransport)(nil)
+
+// ClientTiming is the client-side timing of a request, see Server.TraceClient.
+type ClientTiming struct {
+	// Method is the HTTP method of the request.
+	Method string
+	// RequestURI is the request uri, the path and the query.
+	RequestURI string
+	// Start is when the request was sent.
+	Start time.Time
+	// DNS is the time spent on looking up the host, 0 if the host is an IP or the connection is reused.
+	DNS time.Duration
+	// Connect is the time spent on connecting to the server, 0 if the connection is reused.
+	Connect time.Duration
+	// TLSHandshake is the time spent on the TLS handshake, 0 if the request is not sent over TLS or the connection is
+	// reused.
+	TLSHandshake time.Duration
+	// ServerProcessing is the time between the request was written and the first byte of the response was received,
+	// including the delays of the expectation.
+	ServerProcessing time.Duration
+	// TTFB is the time to the first byte of the response, since the request was sent.
+	TTFB time.Duration
+	// ReusedConn indicates whether the request is sent on a reused connection.
+	ReusedConn bool
+	// LocalAddr is the local address of the connection.
+	LocalAddr string
+	// Err is the error of the request, nil if the response is received.
+	Err error
+	// Journal is the entry of the request in the journal of the server, nil if the server did not record it.
+	Journal *JournalEntry
+}
+
+// ClientTracer records the timings of the requests sent by a client, see Server.TraceClient.
+type ClientTracer struct {
+	server *Server
+
+	mu      sync.Mutex
+	timings []*ClientTiming
+}
+
+// Timings returns the timings of the requests sent by the client, in the order they were sent, correlated with the
+// journal entries of the server by the connection, the method and the uri. Call it after the responses are read, so the
+// server has recorded the requests.
+func (t *ClientTracer) Timings() []ClientTiming {
+	journal := t.server.Journal()
+	used := make([]bool, len(journal))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := make([]ClientTiming, len(t.timings))
+
+	for i, timing := range t.timings {
+		result[i] = *timing
+
+		for j, entry := range journal {
+			if used[j] || entry.RemoteAddr != timing.LocalAddr || entry.Method != timing.Method ||
+				entry.RequestURI != timing.RequestURI || entry.Time.Before(timing.Start) {
+				continue
+			}
+
+			used[j] = true
+			entry := entry
+			result[i].Journal = &entry
+
+			break
+		}
+	}
+
+	return result
+}
+
+// trace returns the client trace that records the timing of a request.
+func (t *ClientTracer) trace(timing *ClientTiming) *httptrace.ClientTrace {
+	var dnsStart, connectStart, tlsStart, wroteRequest time.Time
+
+	record := func(f func()) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		f()
+	}
+
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			record(func() { dnsStart = time.Now() })
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			record(func() { timing.DNS = time.Since(dnsStart) })
+		},
+		ConnectStart: func(string, string) {
+			record(func() { connectStart = time.Now() })
+		},
+		ConnectDone: func(string, string, error) {
+			record(func() { timing.Connect = time.Since(connectStart) })
+		},
+		TLSHandshakeStart: func() {
+			record(func() { tlsStart = time.Now() })
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			record(func() { timing.TLSHandshake = time.Since(tlsStart) })
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			record(func() {
+				timing.ReusedConn = info.Reused
+				timing.LocalAddr = info.Conn.LocalAddr().String()
+			})
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			record(func() { wroteRequest = time.Now() })
+		},
+		GotFirstResponseByte: func() {
+			record(func() {
+				timing.TTFB = time.Since(timing.Start)
+				timing.ServerProcessing = time.Since(wroteRequest)
+			})
+		},
+	}
+}
+
+// traceTransport records the timings of the requests with a ClientTracer.
+type traceTransport struct {
+	next   http.RoundTripper
+	tracer *ClientTracer
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	timing := &ClientTiming{
+		Method:     req.Method,
+		RequestURI: req.URL.RequestURI(),
+		Start:      time.Now(),
+	}
+
+	t.tracer.mu.Lock()
+	t.tracer.timings = append(t.tracer.timings, timing)
+	t.tracer.mu.Unlock()
+
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), t.tracer.trace(timing)))
+
+	resp, err := t.next.RoundTrip(req)
+
+	t.tracer.mu.Lock()
+	timing.Err = err
+	t.tracer.mu.Unlock()
+
+	return resp, err
+}
+
+// TraceClient returns a copy of the client that records the DNS, the connect, the TLS handshake and the time to the
+// first byte of every request with httptrace.ClientTrace, and a tracer that correlates them with the journal entries of
+// the server, so a latency-injection test can assert where the time was spent. The transport of the client is reused,
+// or http.DefaultTransport if it is not set.
+//
+//	client, tracer := srv.TraceClient(srv.Client())
+//
+//	// Send the requests with the client.
+//
+//	timing := tracer.Timings()[0]
+//
+//	assert.GreaterOrEqual(t, timing.ServerProcessing, time.Second)
+func (s *Server) TraceClient(c *http.Client) (*http.Client, *ClientTracer) {
+	tracer := &ClientTracer{server: s}
+
+	next := c.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	traced := *c
+	traced.Transport = &traceTransport{next: next, tracer: tracer}
+
+	return &traced, tracer
+}
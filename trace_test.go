@@ -0,0 +1,80 @@
+package httpmock_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestServer_TraceClient(t *testing.T) {
+	t.Parallel()
+
+	delay := 50 * time.Millisecond
+
+	srv := httpmock.New(func(s *httpmock.Server) {
+		s.ExpectGet("/slow").
+			After(delay)
+
+		s.ExpectGet("/fast?page=2")
+	})(t)
+
+	client, tracer := srv.TraceClient(srv.Client())
+
+	for _, uri := range []string{"/slow", "/fast?page=2"} {
+		resp, err := client.Get(srv.URL() + uri)
+		require.NoError(t, err)
+
+		_, _ = io.Copy(io.Discard, resp.Body) // nolint: errcheck
+		_ = resp.Body.Close()                 // nolint: errcheck
+	}
+
+	require.Eventually(t, func() bool {
+		return len(srv.Journal()) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	timings := tracer.Timings()
+
+	require.Len(t, timings, 2)
+
+	assert.Equal(t, http.MethodGet, timings[0].Method)
+	assert.Equal(t, "/slow", timings[0].RequestURI)
+	assert.False(t, timings[0].ReusedConn)
+	assert.Positive(t, timings[0].Connect)
+	assert.GreaterOrEqual(t, timings[0].ServerProcessing, delay)
+	assert.GreaterOrEqual(t, timings[0].TTFB, timings[0].ServerProcessing)
+	assert.NoError(t, timings[0].Err)
+	require.NotNil(t, timings[0].Journal)
+	assert.Equal(t, "/slow", timings[0].Journal.RequestURI)
+	assert.Equal(t, timings[0].LocalAddr, timings[0].Journal.RemoteAddr)
+
+	assert.Equal(t, "/fast?page=2", timings[1].RequestURI)
+	assert.True(t, timings[1].ReusedConn)
+	assert.Zero(t, timings[1].Connect)
+	assert.Less(t, timings[1].ServerProcessing, delay)
+	require.NotNil(t, timings[1].Journal)
+	assert.Equal(t, "2", timings[1].Journal.Query("page"))
+}
+
+func TestServer_TraceClient_NotReceived(t *testing.T) {
+	t.Parallel()
+
+	srv := httpmock.NewServer()
+	client, tracer := srv.TraceClient(&http.Client{})
+
+	srv.Close()
+
+	_, err := client.Get(srv.URL() + "/") // nolint: noctx,bodyclose
+	require.Error(t, err)
+
+	timings := tracer.Timings()
+
+	require.Len(t, timings, 1)
+	assert.Error(t, timings[0].Err)
+	assert.Nil(t, timings[0].Journal)
+}
@@ -0,0 +1,97 @@
+package httpmock
+
+import (
+	"net/http"
+	"strings"
+
+	"go.nhat.io/httpmock/test"
+)
+
+// WithAltSvc advertises the alternative services on every response, for example, `h3=":443"; ma=86400`, to test the
+// clients that switch to another protocol. The header is added to the default response headers, so it must be called
+// after WithDefaultResponseHeaders.
+//
+//	Server.WithAltSvc(`h3=":443"; ma=86400`)
+func (s *Server) WithAltSvc(services ...string) *Server {
+	return s.withDefaultResponseHeader("Alt-Svc", strings.Join(services, ", "))
+}
+
+// WithUpgradeHeader advertises the protocols that the client could upgrade to on every response, for example, h2c or
+// websocket, see AssertUpgradeAttempted. The header is added to the default response headers, so it must be called
+// after WithDefaultResponseHeaders.
+//
+//	Server.WithUpgradeHeader("h2c")
+func (s *Server) WithUpgradeHeader(protocols ...string) *Server {
+	s.withDefaultResponseHeader("Upgrade", strings.Join(protocols, ", "))
+
+	return s.withDefaultResponseHeader("Connection", "Upgrade")
+}
+
+func (s *Server) withDefaultResponseHeader(header, value string) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	headers := make(map[string]string, len(s.defaultResponseHeader)+1)
+
+	for k, v := range s.defaultResponseHeader {
+		headers[k] = v
+	}
+
+	headers[header] = value
+
+	s.defaultResponseHeader = headers
+
+	return s
+}
+
+// AssertUpgradeAttempted asserts that the client sent a request to upgrade to the protocol, with the Upgrade header.
+//
+//	srv.WithUpgradeHeader("h2c")
+//
+//	// Your requests.
+//
+//	srv.AssertUpgradeAttempted(t, "h2c")
+func (s *Server) AssertUpgradeAttempted(t test.T, protocol string) bool {
+	if s.upgradeAttempted(protocol) {
+		return true
+	}
+
+	t.Errorf("expected the client to upgrade to %q, but it did not", protocol)
+
+	return false
+}
+
+// AssertUpgradeNotAttempted asserts that the client never sent a request to upgrade to the protocol, with the Upgrade
+// header.
+func (s *Server) AssertUpgradeNotAttempted(t test.T, protocol string) bool {
+	if !s.upgradeAttempted(protocol) {
+		return true
+	}
+
+	t.Errorf("expected the client not to upgrade to %q, but it did", protocol)
+
+	return false
+}
+
+func (s *Server) upgradeAttempted(protocol string) bool {
+	for _, entry := range s.Journal() {
+		if hasHeaderToken(entry.Header, "Upgrade", protocol) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasHeaderToken checks whether the comma-separated values of the header contain the token, case-insensitively.
+func hasHeaderToken(header http.Header, key, token string) bool {
+	for _, v := range header.Values(key) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,60 @@
+package httpmock_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+)
+
+func TestServer_WithAltSvc(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer().
+		WithDefaultResponseHeaders(map[string]string{"Content-Type": "application/json"}).
+		WithAltSvc(`h3=":443"; ma=86400`, `h2=":8443"`)
+
+	defer s.Close()
+
+	s.ExpectGet("/")
+
+	_, headers, _, _ := doRequest(t, s.URL(), http.MethodGet, "/", nil, nil, 0)
+
+	expected := httpmock.Header{
+		"Alt-Svc":      `h3=":443"; ma=86400, h2=":8443"`,
+		"Content-Type": "application/json",
+	}
+
+	httpmock.AssertHeaderContains(t, headers, expected)
+}
+
+func TestServer_AssertUpgradeAttempted(t *testing.T) {
+	t.Parallel()
+
+	s := httpmock.NewServer().
+		WithUpgradeHeader("h2c")
+
+	defer s.Close()
+
+	s.ExpectGet("/").Twice()
+
+	_, headers, _, _ := doRequest(t, s.URL(), http.MethodGet, "/", nil, nil, 0)
+
+	httpmock.AssertHeaderContains(t, headers, httpmock.Header{"Upgrade": "h2c", "Connection": "Upgrade"})
+
+	testingT := T()
+
+	assert.False(t, s.AssertUpgradeAttempted(testingT, "h2c"))
+	assert.Equal(t, `expected the client to upgrade to "h2c", but it did not`, testingT.String())
+	assert.True(t, s.AssertUpgradeNotAttempted(T(), "h2c"))
+
+	doRequest(t, s.URL(), http.MethodGet, "/", httpmock.Header{"Upgrade": "websocket, H2C"}, nil, 0)
+
+	testingT = T()
+
+	assert.True(t, s.AssertUpgradeAttempted(T(), "h2c"))
+	assert.False(t, s.AssertUpgradeNotAttempted(testingT, "h2c"))
+	assert.Equal(t, `expected the client not to upgrade to "h2c", but it did`, testingT.String())
+}
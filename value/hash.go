@@ -0,0 +1,68 @@
+package value
+
+import (
+	"hash"
+	"io"
+	"net/http"
+)
+
+// ErrBodyConsumed represents that the request body was consumed by a streaming matcher and cannot be read again.
+const ErrBodyConsumed err = "request body was consumed by a streaming matcher"
+
+// hashedBody is a request body that has been hashed without being buffered. Only its digests are kept.
+type hashedBody struct {
+	digests map[string][]byte
+}
+
+func (hashedBody) Read([]byte) (int, error) {
+	return 0, ErrBodyConsumed
+}
+
+func (hashedBody) Close() error {
+	return nil
+}
+
+// HashBody returns the digest of the request body. If the body has not been read or captured, it is hashed
+// incrementally while being read, so a large or chunked body is not buffered in the memory, but it cannot be read
+// again, except for its digests of the same name.
+func HashBody(r *http.Request, name string, newHash func() hash.Hash) ([]byte, error) {
+	h := newHash()
+
+	switch b := r.Body.(type) {
+	case *body, *capturedBody:
+		data, err := PeekBody(r)
+		if err != nil {
+			return nil, err
+		}
+
+		_, _ = h.Write(data) //nolint: errcheck
+
+		return h.Sum(nil), nil
+
+	case *hashedBody:
+		if digest, ok := b.digests[name]; ok {
+			return digest, nil
+		}
+
+		return nil, ErrBodyConsumed
+	}
+
+	if r.Body == nil || r.Body == http.NoBody {
+		return h.Sum(nil), nil
+	}
+
+	_, err := io.Copy(h, r.Body)
+	if err == nil {
+		err = r.Body.Close()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	digest := h.Sum(nil)
+
+	r.Body = &hashedBody{digests: map[string][]byte{name: digest}}
+
+	return digest, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 )
 
 // String returns the string value of the given object.
@@ -23,19 +24,146 @@ func String(v any) string {
 	panic(ErrUnsupportedDataType)
 }
 
-// GetBody returns request body and lets it re-readable.
+// maxPooledBufferSize is the maximum size of a buffer that is put back to the pool, so a large body does not stay in
+// the memory.
+const maxPooledBufferSize = 64 << 10
+
+var bufferPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+// body is a request body that has been read and can be read again.
+type body struct {
+	*bytes.Reader
+
+	data []byte
+	buf  *bytes.Buffer
+}
+
+func (body) Close() error {
+	return nil
+}
+
+// capturedBody is a request body that records what has been read, so it can be read again with GetBody without
+// buffering the body in advance.
+type capturedBody struct {
+	io.ReadCloser
+
+	buf *bytes.Buffer
+}
+
+func (b *capturedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	b.buf.Write(p[:n])
+
+	return n, err
+}
+
+// Close does not close the upstream body because GetBody may read the rest of it later.
+func (*capturedBody) Close() error {
+	return nil
+}
+
+// CaptureBody records the request body while it is being read, so GetBody can still return the whole body after
+// someone reads it directly from the request. The body is not read until it is needed.
+func CaptureBody(r *http.Request) {
+	switch r.Body.(type) {
+	case *body, *capturedBody:
+		return
+	}
+
+	if r.Body == nil || r.Body == http.NoBody {
+		return
+	}
+
+	buf := bufferPool.Get().(*bytes.Buffer) //nolint: errcheck,forcetypeassert
+	buf.Reset()
+
+	r.Body = &capturedBody{ReadCloser: r.Body, buf: buf}
+}
+
+// GetBody returns request body and lets it re-readable. The body is only read from the request once, the result is a
+// copy that the caller owns.
 func GetBody(r *http.Request) ([]byte, error) {
-	body, err := io.ReadAll(r.Body)
+	data, err := PeekBody(r)
 	if err != nil {
 		return nil, err
 	}
 
-	err = r.Body.Close()
+	result := make([]byte, len(data))
+
+	copy(result, data)
+
+	return result, nil
+}
+
+// PeekBody is like GetBody but it returns the buffered bytes without copying, so the result must not be modified or
+// kept after ReleaseBody, which puts the buffer back to the pool. Use GetBody unless the bytes are only used during the
+// call, for example, to match the body.
+func PeekBody(r *http.Request) ([]byte, error) {
+	upstream := r.Body
+
+	var buf *bytes.Buffer
+
+	switch b := r.Body.(type) {
+	case *body:
+		b.Reset(b.data)
+
+		return b.data, nil
+
+	case *capturedBody:
+		// Read the rest of the body.
+		upstream, buf = b.ReadCloser, b.buf
+
+	default:
+		buf = bufferPool.Get().(*bytes.Buffer) //nolint: errcheck,forcetypeassert
+		buf.Reset()
+	}
+
+	_, err := buf.ReadFrom(upstream)
+	if err == nil {
+		err = upstream.Close()
+	}
+
 	if err != nil {
+		// The buffer of a captured body is put back to the pool by ReleaseBody.
+		if _, ok := r.Body.(*capturedBody); !ok {
+			putBuffer(buf)
+		}
+
 		return nil, err
 	}
 
-	r.Body = io.NopCloser(bytes.NewReader(body))
+	data := buf.Bytes()
+
+	r.Body = &body{Reader: bytes.NewReader(data), data: data, buf: buf}
+
+	return data, nil
+}
+
+// ReleaseBody puts the buffer of the request body read by GetBody back to the pool. The request body and the bytes
+// returned by PeekBody must not be used after that.
+func ReleaseBody(r *http.Request) {
+	switch b := r.Body.(type) {
+	case *body:
+		r.Body = http.NoBody
+
+		putBuffer(b.buf)
+
+	case *capturedBody:
+		r.Body = b.ReadCloser
+
+		putBuffer(b.buf)
+	}
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
 
-	return body, err
+	bufferPool.Put(buf)
 }
@@ -2,7 +2,11 @@ package value_test
 
 import (
 	"bytes"
+	"crypto/md5" // nolint: gosec
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -74,6 +78,133 @@ func TestGetBody(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestGetBody_ReadOnce(t *testing.T) {
+	t.Parallel()
+
+	req := http.BuildRequest().WithBody("body").Build()
+
+	body, err := value.GetBody(req)
+
+	assert.Equal(t, []byte("body"), body)
+	assert.NoError(t, err)
+
+	// The body is still readable.
+	data, err := io.ReadAll(req.Body)
+
+	assert.Equal(t, []byte("body"), data)
+	assert.NoError(t, err)
+
+	// The result is a copy.
+	again, err := value.GetBody(req)
+
+	assert.Equal(t, []byte("body"), again)
+	assert.NotSame(t, &body[0], &again[0])
+	assert.NoError(t, err)
+
+	// The body is not copied by PeekBody.
+	peek, err := value.PeekBody(req)
+	assert.NoError(t, err)
+
+	peekAgain, err := value.PeekBody(req)
+
+	assert.Equal(t, []byte("body"), peekAgain)
+	assert.Same(t, &peek[0], &peekAgain[0])
+	assert.NoError(t, err)
+}
+
+func TestGetBody_AfterRelease(t *testing.T) {
+	t.Parallel()
+
+	req := http.BuildRequest().WithBody("first-body").Build()
+
+	body, err := value.GetBody(req)
+
+	assert.NoError(t, err)
+
+	value.ReleaseBody(req)
+
+	// The buffer is reused by another request.
+	other := http.BuildRequest().WithBody("SECOND").Build()
+
+	_, err = value.PeekBody(other)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("first-body"), body)
+
+	value.ReleaseBody(other)
+}
+
+func TestCaptureBody(t *testing.T) {
+	t.Parallel()
+
+	req := http.BuildRequest().WithBody("hello world").Build()
+
+	value.CaptureBody(req)
+
+	// Someone reads a part of the body directly.
+	p := make([]byte, 5)
+
+	n, err := req.Body.Read(p)
+
+	assert.Equal(t, 5, n)
+	assert.Equal(t, []byte("hello"), p)
+	assert.NoError(t, err)
+	assert.NoError(t, req.Body.Close())
+
+	// The whole body is still available.
+	body, err := value.GetBody(req)
+
+	assert.Equal(t, []byte("hello world"), body)
+	assert.NoError(t, err)
+
+	// Capturing again does nothing.
+	value.CaptureBody(req)
+
+	body, err = value.GetBody(req)
+
+	assert.Equal(t, []byte("hello world"), body)
+	assert.NoError(t, err)
+
+	value.ReleaseBody(req)
+}
+
+func TestCaptureBody_ReadError(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("read error")
+	req := http.BuildRequest().WithBodyReadError(expectedErr).Build()
+
+	value.CaptureBody(req)
+
+	body, err := value.GetBody(req)
+
+	assert.Nil(t, body)
+	assert.Equal(t, expectedErr, err)
+
+	value.ReleaseBody(req)
+}
+
+func TestReleaseBody(t *testing.T) {
+	t.Parallel()
+
+	req := http.BuildRequest().WithBody("body").Build()
+
+	// Nothing happens if the body was not read.
+	value.ReleaseBody(req)
+
+	body, err := value.GetBody(req)
+
+	assert.Equal(t, []byte("body"), body)
+	assert.NoError(t, err)
+
+	value.ReleaseBody(req)
+
+	body, err = value.GetBody(req)
+
+	assert.Empty(t, body)
+	assert.NoError(t, err)
+}
+
 func TestGetBody_ReadError(t *testing.T) {
 	t.Parallel()
 
@@ -97,3 +228,63 @@ func TestGetBody_CloseError(t *testing.T) {
 	assert.Nil(t, body)
 	assert.Equal(t, expectedErr, err)
 }
+
+func TestHashBody(t *testing.T) {
+	t.Parallel()
+
+	const expected = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+
+	req := http.BuildRequest().WithBody("hello world").Build()
+
+	digest, err := value.HashBody(req, "sha256", sha256.New)
+
+	assert.Equal(t, expected, hex.EncodeToString(digest))
+	assert.NoError(t, err)
+
+	// The digest is kept.
+	digest, err = value.HashBody(req, "sha256", sha256.New)
+
+	assert.Equal(t, expected, hex.EncodeToString(digest))
+	assert.NoError(t, err)
+
+	// The body is not buffered.
+	digest, err = value.HashBody(req, "md5", md5.New)
+
+	assert.Nil(t, digest)
+	assert.ErrorIs(t, err, value.ErrBodyConsumed)
+
+	body, err := value.GetBody(req)
+
+	assert.Nil(t, body)
+	assert.ErrorIs(t, err, value.ErrBodyConsumed)
+}
+
+func TestHashBody_CapturedBody(t *testing.T) {
+	t.Parallel()
+
+	req := http.BuildRequest().WithBody("hello world").Build()
+
+	value.CaptureBody(req)
+
+	digest, err := value.HashBody(req, "md5", md5.New)
+
+	assert.Equal(t, "5eb63bbbe01eeed093cb22bb8f5acdc3", hex.EncodeToString(digest))
+	assert.NoError(t, err)
+
+	// The body is still readable.
+	body, err := value.GetBody(req)
+
+	assert.Equal(t, []byte("hello world"), body)
+	assert.NoError(t, err)
+}
+
+func TestHashBody_ReadError(t *testing.T) {
+	t.Parallel()
+
+	req := http.BuildRequest().WithBodyReadError(errors.New("read error")).Build()
+
+	digest, err := value.HashBody(req, "sha256", sha256.New)
+
+	assert.Nil(t, digest)
+	assert.EqualError(t, err, "read error")
+}
@@ -0,0 +1,21 @@
+package httpmock
+
+import "go.nhat.io/httpmock/matcher"
+
+// Match returns a matcher according to its type, see RegisterValueMatcher.
+var Match = matcher.Match
+
+// RegisterValueMatcher registers a function to convert the values of a type to a matcher, so they can be used in the
+// expectations, for example, WithBody or WithHeader, instead of panicking with unsupported data type.
+//
+//	httpmock.RegisterValueMatcher(func(u User) matcher.Matcher {
+//		b, _ := json.Marshal(u)
+//
+//		return matcher.JSON(string(b))
+//	})
+//
+//	Server.Expect(httpmock.MethodPost, "/users").
+//		WithBody(User{ID: 42})
+func RegisterValueMatcher[T any](fn func(v T) matcher.Matcher) {
+	matcher.RegisterValueMatcher(fn)
+}
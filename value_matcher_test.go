@@ -0,0 +1,43 @@
+package httpmock_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/httpmock"
+	"go.nhat.io/httpmock/matcher"
+)
+
+type registeredUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestRegisterValueMatcher(t *testing.T) {
+	t.Parallel()
+
+	httpmock.RegisterValueMatcher(func(u registeredUser) matcher.Matcher {
+		b, err := json.Marshal(u)
+		if err != nil {
+			panic(err)
+		}
+
+		return matcher.JSON(string(b))
+	})
+
+	s := httpmock.NewServer()
+
+	defer s.Close()
+
+	s.ExpectPost("/users").
+		WithBody(registeredUser{ID: 42, Name: "John Doe"}).
+		ReturnCode(http.StatusCreated)
+
+	code, _, _, _ := doRequest(t, s.URL(), http.MethodPost, "/users", nil, []byte(`{"name": "John Doe", "id": 42}`), 0)
+
+	assert.Equal(t, http.StatusCreated, code)
+	assert.NoError(t, s.ExpectationsWereMet())
+}